@@ -7,11 +7,10 @@ import (
 	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
 	"github.com/Layr-Labs/multichain-go/pkg/logger"
 	"github.com/Layr-Labs/multichain-go/pkg/operatorTableCalculator"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
 	"github.com/Layr-Labs/multichain-go/pkg/transport"
 	"github.com/Layr-Labs/multichain-go/pkg/txSigner"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/rpc"
-	"math/big"
 )
 
 var (
@@ -61,12 +60,12 @@ func main() {
 		l.Sugar().Fatalf("Failed to create StakeTableRootCalculator: %v", err)
 	}
 
-	block, err := sepoliaClient.RPCClient.BlockByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	snap, err := snapshot.Selector{Kind: snapshot.SelectFinalized}.Resolve(ctx, sepoliaClient.RPCClient)
 	if err != nil {
-		l.Sugar().Fatalf("Failed to get block number: %v", err)
+		l.Sugar().Fatalf("Failed to resolve finalized block: %v", err)
 	}
 
-	root, tree, dist, err := tableCalc.CalculateStakeTableRoot(ctx, block.NumberU64())
+	root, tree, dist, err := tableCalc.CalculateStakeTableRootAtSnapshot(ctx, snap)
 	if err != nil {
 		l.Sugar().Fatalf("Failed to calculate stake table root: %v", err)
 	}
@@ -95,15 +94,7 @@ func main() {
 		l.Sugar().Fatalf("Failed to create transport: %v", err)
 	}
 
-	referenceTimestamp := uint32(block.Time())
-
-	err = stakeTransport.SignAndTransportGlobalTableRoot(
-		ctx,
-		root,
-		referenceTimestamp,
-		block.NumberU64(),
-		nil,
-	)
+	err = stakeTransport.SignAndTransportGlobalTableRootForDistributionAtSnapshot(ctx, root, dist, snap, nil)
 	if err != nil {
 		l.Sugar().Fatalf("Failed to sign and transport global table root: %v", err)
 	}
@@ -115,16 +106,7 @@ func main() {
 		return
 	}
 	for _, opset := range opsets {
-		err = stakeTransport.SignAndTransportAvsStakeTable(
-			ctx,
-			referenceTimestamp,
-			block.NumberU64(),
-			opset,
-			root,
-			tree,
-			dist,
-			nil,
-		)
+		err = stakeTransport.SignAndTransportAvsStakeTableAtSnapshot(ctx, snap, opset, root, tree, dist, nil)
 		if err != nil {
 			l.Sugar().Fatalf("Failed to sign and transport AVS stake table for opset %v: %v", opset, err)
 		} else {
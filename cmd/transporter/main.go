@@ -2,20 +2,37 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Layr-Labs/crypto-libs/pkg/bn254"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
 	"github.com/Layr-Labs/multichain-go/pkg/blsSigner"
 	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
+	"github.com/Layr-Labs/multichain-go/pkg/changeDetector"
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/localTableCalculator"
 	"github.com/Layr-Labs/multichain-go/pkg/logger"
+	"github.com/Layr-Labs/multichain-go/pkg/multicall"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
 	"github.com/Layr-Labs/multichain-go/pkg/operatorTableCalculator"
+	"github.com/Layr-Labs/multichain-go/pkg/policy"
+	"github.com/Layr-Labs/multichain-go/pkg/scheduler"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/Layr-Labs/multichain-go/pkg/tracing"
 	"github.com/Layr-Labs/multichain-go/pkg/transport"
 	"github.com/Layr-Labs/multichain-go/pkg/txSigner"
+	"github.com/Layr-Labs/multichain-go/pkg/verifier"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	cli "github.com/urfave/cli/v2"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -50,7 +67,7 @@ direct private key signing and secure remote keystore integration.`,
 			&cli.StringSliceFlag{
 				Name:     "chains",
 				Aliases:  []string{"c"},
-				Usage:    "Blockchain configurations in format 'chainId:rpcUrl' (e.g., '17000:https://ethereum-holesky-rpc.publicnode.com')",
+				Usage:    "Blockchain configurations in format 'chainId:rpcUrl' (e.g., '17000:https://ethereum-holesky-rpc.publicnode.com'). Repeat a chain ID to add fallback RPC URLs, most preferred first",
 				Required: true,
 				EnvVars:  []string{"CHAINS"},
 			},
@@ -93,6 +110,34 @@ direct private key signing and secure remote keystore integration.`,
 				Value:   "us-east-1",
 				EnvVars: []string{"BLS_AWS_REGION"},
 			},
+			// RPC failover options
+			&cli.DurationFlag{
+				Name:    "rpc-call-timeout",
+				Usage:   "Timeout of each RPC call on one endpoint before failing over to the next",
+				Value:   chainManager.DefaultCallTimeout,
+				EnvVars: []string{"RPC_CALL_TIMEOUT"},
+			},
+			&cli.BoolFlag{
+				Name:    "rpc-sticky-nonce",
+				Usage:   "Read nonces from and send transactions to the same RPC endpoint until it fails",
+				EnvVars: []string{"RPC_STICKY_NONCE"},
+			},
+			&cli.BoolFlag{
+				Name:    "rpc-broadcast-tx",
+				Usage:   "Send each transaction to every available RPC endpoint of the chain",
+				EnvVars: []string{"RPC_BROADCAST_TX"},
+			},
+			// Tracing options
+			&cli.StringFlag{
+				Name:    "otlp-endpoint",
+				Usage:   "OTLP/HTTP collector endpoint (host:port) to export OpenTelemetry traces to; tracing is disabled when unset",
+				EnvVars: []string{"OTLP_ENDPOINT"},
+			},
+			&cli.BoolFlag{
+				Name:    "otlp-insecure",
+				Usage:   "Disable TLS when exporting traces to the OTLP collector",
+				EnvVars: []string{"OTLP_INSECURE"},
+			},
 		},
 		Commands: []*cli.Command{
 			{
@@ -102,39 +147,278 @@ direct private key signing and secure remote keystore integration.`,
 				Description: `Calculate stake table roots from operator set data and transport them 
 to all configured blockchain networks. This includes both global table roots 
 and individual AVS stake tables.`,
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					&cli.Uint64Flag{
 						Name:    "block-number",
 						Aliases: []string{"b"},
 						Usage:   "Specific block number to use for calculation (defaults to latest)",
 						EnvVars: []string{"BLOCK_NUMBER"},
 					},
+					blockSelectorFlag(),
+					alignToCadenceFlag(),
 					&cli.BoolFlag{
 						Name:    "skip-avs-tables",
 						Usage:   "Skip individual AVS stake table transport (only do global root)",
 						EnvVars: []string{"SKIP_AVS_TABLES"},
 					},
-				},
+					&cli.StringFlag{
+						Name:    "artifact-out",
+						Usage:   "Write the calculated distribution and Merkle tree to this file for later re-transport",
+						EnvVars: []string{"ARTIFACT_OUT"},
+					},
+					&cli.BoolFlag{
+						Name:    "schedule",
+						Usage:   "Transport AVS stake tables in order of their staleness deadlines on the destination chains",
+						EnvVars: []string{"SCHEDULE"},
+					},
+					transportDurationFlag(),
+				}, append(calculationFlags(), policyFlags()...)...),
+				Before: validateSignerFlags,
 				Action: transportAction,
 			},
+			{
+				Name:  "transport-opset",
+				Usage: "Re-transport a single operator set's table from a saved artifact",
+				Description: `Load a distribution artifact written by 'transport --artifact-out' or
+'calculate --artifact-out' and transport the table for a single operator set,
+without recalculating. The artifact's global table root must already be
+confirmed on the destination chains.`,
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:     "artifact",
+						Usage:    "Distribution artifact file to transport from",
+						Required: true,
+						EnvVars:  []string{"ARTIFACT"},
+					},
+					&cli.StringFlag{
+						Name:     "operator-set",
+						Usage:    "Operator set to transport, in format 'avsAddress:operatorSetId'",
+						Required: true,
+						EnvVars:  []string{"OPERATOR_SET"},
+					},
+				}, policyFlags()...),
+				Before: validateSignerFlags,
+				Action: transportOpsetAction,
+			},
 			{
 				Name:    "calculate",
 				Aliases: []string{"calc"},
 				Usage:   "Calculate stake table root without transporting",
 				Description: `Calculate the stake table root for the current state without 
 transporting it to any blockchain networks. Useful for testing and verification.`,
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					&cli.Uint64Flag{
 						Name:    "block-number",
 						Aliases: []string{"b"},
 						Usage:   "Specific block number to use for calculation (defaults to latest)",
 						EnvVars: []string{"BLOCK_NUMBER"},
 					},
-				},
+					blockSelectorFlag(),
+					alignToCadenceFlag(),
+					&cli.StringFlag{
+						Name:    "artifact-out",
+						Usage:   "Write the calculated distribution and Merkle tree to this file for later re-transport",
+						EnvVars: []string{"ARTIFACT_OUT"},
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output format: 'text' or 'json' (json prints the full calculation report)",
+						Value:   "text",
+						EnvVars: []string{"OUTPUT"},
+					},
+				}, calculationFlags()...),
 				Action: calculateAction,
 			},
+			{
+				Name:  "inspect-table",
+				Usage: "Decode and print an operator set's operator table",
+				Description: `Decode the operator table bytes of a single operator set into its operator set
+config and BN254 or ECDSA operator table. The table bytes are read from
+--table-bytes, from an artifact written with --artifact-out, or calculated
+by the CrossChainRegistry on the primary chain at --block-number.`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "operator-set",
+						Usage:   "Operator set to inspect, in format 'avsAddress:operatorSetId' (not needed with --table-bytes)",
+						EnvVars: []string{"OPERATOR_SET"},
+					},
+					&cli.StringFlag{
+						Name:    "table-bytes",
+						Usage:   "Hex-encoded operator table bytes to decode",
+						EnvVars: []string{"TABLE_BYTES"},
+					},
+					&cli.StringFlag{
+						Name:    "artifact",
+						Usage:   "Distribution artifact file to read the operator table from",
+						EnvVars: []string{"ARTIFACT"},
+					},
+					&cli.Uint64Flag{
+						Name:    "block-number",
+						Aliases: []string{"b"},
+						Usage:   "Specific block number to calculate the table at (defaults to latest)",
+						EnvVars: []string{"BLOCK_NUMBER"},
+					},
+					blockSelectorFlag(),
+					alignToCadenceFlag(),
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output format: 'text' or 'json'",
+						Value:   "text",
+						EnvVars: []string{"OUTPUT"},
+					},
+				},
+				Action: inspectTableAction,
+			},
+			{
+				Name:  "diff",
+				Usage: "Compare the stake tables at two reference blocks",
+				Description: `Calculate the distribution at two reference blocks and report the operator
+sets that were added, removed, or whose operator table changed, with the
+change in operator count, aggregate key, and total weights of each.`,
+				Flags: append([]cli.Flag{
+					&cli.Uint64Flag{
+						Name:     "from-block",
+						Usage:    "Older reference block number",
+						Required: true,
+						EnvVars:  []string{"FROM_BLOCK"},
+					},
+					&cli.Uint64Flag{
+						Name:    "to-block",
+						Usage:   "Newer reference block number (defaults to latest)",
+						EnvVars: []string{"TO_BLOCK"},
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output format: 'text' or 'json'",
+						Value:   "text",
+						EnvVars: []string{"OUTPUT"},
+					},
+				}, calculationFlags()...),
+				Action: diffAction,
+			},
+			{
+				Name:  "schedule",
+				Usage: "Plan AVS stake table transports around staleness deadlines",
+				Description: `Read the latest reference timestamp and max staleness period of every
+operator set on every destination chain, and list the transports in order of
+the deadline at which each destination's table goes stale. Deadlines that will
+be missed given the current gas price and transport throughput are reported as
+alerts.`,
+				Flags: append([]cli.Flag{
+					&cli.Uint64Flag{
+						Name:    "block-number",
+						Aliases: []string{"b"},
+						Usage:   "Specific block number to use for calculation (defaults to latest)",
+						EnvVars: []string{"BLOCK_NUMBER"},
+					},
+					blockSelectorFlag(),
+					alignToCadenceFlag(),
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output format: 'text' or 'json'",
+						Value:   "text",
+						EnvVars: []string{"OUTPUT"},
+					},
+					transportDurationFlag(),
+				}, append(calculationFlags(), policyFlags()...)...),
+				Action: scheduleAction,
+			},
+			{
+				Name:  "backfill",
+				Usage: "Calculate historical stake table roots across a block range",
+				Description: `Calculate the global table root at every --step blocks from --from to --to
+and write a time series of block number, hash, timestamp, root, operator set
+count, skipped reservation count, and reused table count as CSV or JSON. Reads
+at each block are pinned to its hash, so an archive node is required for old
+blocks. With --reuse-unchanged, operator sets without change events since the
+previous sampled block keep their table instead of being calculated again.`,
+				Flags: append([]cli.Flag{
+					&cli.Uint64Flag{
+						Name:     "from",
+						Usage:    "First block to sample",
+						Required: true,
+						EnvVars:  []string{"FROM_BLOCK"},
+					},
+					&cli.Uint64Flag{
+						Name:    "to",
+						Usage:   "Last block that may be sampled (defaults to latest)",
+						EnvVars: []string{"TO_BLOCK"},
+					},
+					&cli.Uint64Flag{
+						Name:    "step",
+						Usage:   "Number of blocks between samples",
+						Value:   1,
+						EnvVars: []string{"STEP"},
+					},
+					&cli.IntFlag{
+						Name:    "block-parallelism",
+						Usage:   "Maximum number of sampled blocks to calculate concurrently",
+						Value:   1,
+						EnvVars: []string{"BLOCK_PARALLELISM"},
+					},
+					&cli.BoolFlag{
+						Name:    "reuse-unchanged",
+						Usage:   "Reuse the tables of operator sets without change events since the previous sampled block instead of calculating them again",
+						EnvVars: []string{"REUSE_UNCHANGED"},
+					},
+					&cli.Uint64Flag{
+						Name:    "history-from-block",
+						Usage:   "First block searched for allocation changes and key rotations still pending at --from, with --reuse-unchanged; use the core contracts' deployment block",
+						EnvVars: []string{"HISTORY_FROM_BLOCK"},
+					},
+					&cli.Uint64Flag{
+						Name:    "log-range",
+						Usage:   "Number of blocks requested per eth_getLogs call, with --reuse-unchanged",
+						Value:   localTableCalculator.DefaultLogRangeBlocks,
+						EnvVars: []string{"LOG_RANGE"},
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output format: 'csv' or 'json'",
+						Value:   "csv",
+						EnvVars: []string{"OUTPUT"},
+					},
+					&cli.StringFlag{
+						Name:    "output-file",
+						Usage:   "Write the time series to this file instead of stdout",
+						EnvVars: []string{"OUTPUT_FILE"},
+					},
+				}, calculationFlags()...),
+				Action: backfillAction,
+			},
+			{
+				Name:  "verify",
+				Usage: "Check that destination chains hold the roots and tables calculated on L1",
+				Description: `Recalculate the global table root at the reference block and, for every chain
+in the CrossChainRegistry's supported chains, check the root confirmed for the
+block's reference timestamp and each operator set's table against the tables
+calculated on L1. Prints a per-chain and per-operator-set result table and
+exits non-zero if any check mismatched or failed.`,
+				Flags: append([]cli.Flag{
+					&cli.Uint64Flag{
+						Name:    "block-number",
+						Aliases: []string{"b"},
+						Usage:   "Reference block number whose roots to verify (defaults to latest)",
+						EnvVars: []string{"BLOCK_NUMBER"},
+					},
+					blockSelectorFlag(),
+					alignToCadenceFlag(),
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output format: 'text' or 'json'",
+						Value:   "text",
+						EnvVars: []string{"OUTPUT"},
+					},
+				}, append(calculationFlags(), policyFlags()...)...),
+				Action: verifyAction,
+			},
 		},
-		Before: validateFlags,
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -143,7 +427,9 @@ transporting it to any blockchain networks. Useful for testing and verification.
 	}
 }
 
-func validateFlags(c *cli.Context) error {
+// validateSignerFlags checks that exactly one transaction signer and one BLS signer are
+// configured. Only the commands that sign and send transactions need them.
+func validateSignerFlags(c *cli.Context) error {
 	// Validate transaction signing configuration
 	txPrivateKey := c.String("tx-private-key")
 	txKMSKeyID := c.String("tx-aws-kms-key-id")
@@ -187,9 +473,37 @@ func setupLogger(c *cli.Context) (*zap.Logger, error) {
 	})
 }
 
-func setupChainManager(c *cli.Context) (*chainManager.ChainManager, error) {
+// setupTracing creates a tracer provider exporting to the configured OTLP endpoint.
+// It returns a nil provider and a no-op shutdown function when tracing is not configured.
+func setupTracing(c *cli.Context) (trace.TracerProvider, func(), error) {
+	endpoint := c.String("otlp-endpoint")
+	if endpoint == "" {
+		return nil, func() {}, nil
+	}
+	tp, err := tracing.NewTracerProvider(c.Context, &tracing.ExporterConfig{
+		Endpoint:    endpoint,
+		Insecure:    c.Bool("otlp-insecure"),
+		ServiceName: "transporter",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	shutdown := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := tp.Shutdown(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to flush traces: %v\n", err)
+		}
+	}
+	return tp, shutdown, nil
+}
+
+func setupChainManager(c *cli.Context, tp trace.TracerProvider, l *zap.Logger) (*chainManager.ChainManager, error) {
 	cm := chainManager.NewChainManager()
 
+	// Repeated chain IDs add fallback RPC URLs, in the order given
+	configs := []*chainManager.ChainConfig{}
+	byId := make(map[uint64]*chainManager.ChainConfig)
 	chains := c.StringSlice("chains")
 	for _, chainConfig := range chains {
 		parts := strings.SplitN(chainConfig, ":", 2)
@@ -203,11 +517,29 @@ func setupChainManager(c *cli.Context) (*chainManager.ChainManager, error) {
 			return nil, fmt.Errorf("invalid chain ID: %s", parts[0])
 		}
 
+		if config, ok := byId[chainID.Uint64()]; ok {
+			config.RPCUrls = append(config.RPCUrls, parts[1])
+			continue
+		}
 		config := &chainManager.ChainConfig{
-			ChainID: chainID.Uint64(),
-			RPCUrl:  parts[1],
+			ChainID:        chainID.Uint64(),
+			RPCUrl:         parts[1],
+			TracerProvider: tp,
 		}
+		byId[config.ChainID] = config
+		configs = append(configs, config)
+	}
 
+	failoverFlagsSet := c.IsSet("rpc-call-timeout") || c.IsSet("rpc-sticky-nonce") || c.IsSet("rpc-broadcast-tx")
+	for _, config := range configs {
+		if len(config.RPCUrls) > 0 || failoverFlagsSet {
+			config.Failover = &chainManager.FailoverConfig{
+				CallTimeout:           c.Duration("rpc-call-timeout"),
+				StickyNonce:           c.Bool("rpc-sticky-nonce"),
+				BroadcastTransactions: c.Bool("rpc-broadcast-tx"),
+				Logger:                l.With(zap.Uint64("chainId", config.ChainID)),
+			}
+		}
 		if err := cm.AddChain(config); err != nil {
 			return nil, fmt.Errorf("failed to add chain %d: %w", config.ChainID, err)
 		}
@@ -257,7 +589,331 @@ func setupBLSSigner(c *cli.Context, l *zap.Logger) (blsSigner.IBLSSigner, error)
 	return nil, fmt.Errorf("no BLS signing method configured")
 }
 
-func setupTransport(c *cli.Context, cm *chainManager.ChainManager, txSig txSigner.ITransactionSigner, blsSig blsSigner.IBLSSigner, l *zap.Logger) (*transport.Transport, *chainManager.Chain, error) {
+// calculationFlags returns the flags that tune how the stake table calculation reads from L1.
+func calculationFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:    "parallelism",
+			Usage:   "Maximum number of operator table calculations to run concurrently",
+			Value:   1,
+			EnvVars: []string{"PARALLELISM"},
+		},
+		&cli.BoolFlag{
+			Name:    "multicall",
+			Usage:   "Batch registry reads through Multicall3",
+			EnvVars: []string{"MULTICALL"},
+		},
+		&cli.StringFlag{
+			Name:    "multicall-address",
+			Usage:   "Multicall3 contract address",
+			Value:   multicall.Multicall3Address.Hex(),
+			EnvVars: []string{"MULTICALL_ADDRESS"},
+		},
+		&cli.IntFlag{
+			Name:    "multicall-batch-size",
+			Usage:   "Largest number of calls per Multicall3 batch; shrinks when the RPC rejects a batch for gas or response size and grows back as batches succeed",
+			Value:   multicall.DefaultBatchSize,
+			EnvVars: []string{"MULTICALL_BATCH_SIZE"},
+		},
+		&cli.IntFlag{
+			Name:    "page-size",
+			Usage:   "Starting number of reservations per GetActiveGenerationReservationsByRange call; halves when the RPC rejects a page or times out",
+			Value:   operatorTableCalculator.DefaultPageSize,
+			EnvVars: []string{"PAGE_SIZE"},
+		},
+		&cli.IntFlag{
+			Name:    "max-page-size",
+			Usage:   "Largest page size to grow to after successful pages",
+			Value:   500,
+			EnvVars: []string{"MAX_PAGE_SIZE"},
+		},
+		&cli.IntFlag{
+			Name:    "page-retries",
+			Usage:   "Number of times a page that fails transiently is retried (negative disables retries)",
+			Value:   operatorTableCalculator.DefaultPageRetries,
+			EnvVars: []string{"PAGE_RETRIES"},
+		},
+		&cli.BoolFlag{
+			Name:    "cross-check",
+			Usage:   "Recalculate standard BN254 and ECDSA operator tables off-chain and compare them with the on-chain result",
+			EnvVars: []string{"CROSS_CHECK"},
+		},
+		&cli.StringFlag{
+			Name:    "cross-check-mode",
+			Usage:   "What to do when an on-chain table differs from the off-chain calculation: warn, exclude or abort",
+			Value:   string(operatorTableCalculator.CrossCheckWarn),
+			EnvVars: []string{"CROSS_CHECK_MODE"},
+		},
+		&cli.BoolFlag{
+			Name:    "local-fallback",
+			Usage:   "Calculate standard BN254 and ECDSA operator tables off-chain when CalculateOperatorTableBytes runs out of gas, instead of skipping the operator set",
+			EnvVars: []string{"LOCAL_FALLBACK"},
+		},
+	}
+}
+
+// newStakeTableCalculator creates the stake table calculator configured by the calculation flags.
+func newStakeTableCalculator(
+	c *cli.Context,
+	registryAddr common.Address,
+	ec chainManager.EthClientInterface,
+	tp trace.TracerProvider,
+	l *zap.Logger,
+) (*operatorTableCalculator.StakeTableCalculator, error) {
+	cfg := calculatorConfig(c, registryAddr, tp)
+	if !c.Bool("cross-check") && !c.Bool("local-fallback") {
+		return operatorTableCalculator.NewStakeTableRootCalculator(cfg, ec, l)
+	}
+	local, err := localTableCalculator.NewCalculator(&localTableCalculator.Config{CrossChainRegistryAddress: registryAddr}, ec, l)
+	if err != nil {
+		return nil, err
+	}
+	if c.Bool("cross-check") {
+		mode, err := operatorTableCalculator.ParseCrossCheckMode(c.String("cross-check-mode"))
+		if err != nil {
+			return nil, err
+		}
+		cfg.LocalTableComputer = local
+		cfg.CrossCheckMode = mode
+	}
+	if c.Bool("local-fallback") {
+		cfg.LocalFallback = local
+	}
+	return operatorTableCalculator.NewStakeTableRootCalculator(cfg, ec, l)
+}
+
+// calculatorConfig builds the stake table calculator configuration from the calculation flags.
+func calculatorConfig(c *cli.Context, registryAddr common.Address, tp trace.TracerProvider) *operatorTableCalculator.Config {
+	cfg := &operatorTableCalculator.Config{
+		CrossChainRegistryAddress: registryAddr,
+		TracerProvider:            tp,
+		Parallelism:               c.Int("parallelism"),
+		PageSize:                  c.Int("page-size"),
+		MaxPageSize:               c.Int("max-page-size"),
+		PageRetries:               c.Int("page-retries"),
+	}
+	if c.Bool("multicall") {
+		cfg.Multicall = &multicall.Config{
+			Address:   common.HexToAddress(c.String("multicall-address")),
+			BatchSize: c.Int("multicall-batch-size"),
+		}
+	}
+	return cfg
+}
+
+// policyFlags returns the flags that configure which chains and operator sets are transported.
+func policyFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "policy-file",
+			Usage:   "YAML transport policy deciding which chains and operator sets to transport",
+			EnvVars: []string{"POLICY_FILE"},
+		},
+		&cli.Uint64SliceFlag{
+			Name:    "only-chain",
+			Usage:   "Only transport to these destination chain IDs (can be specified multiple times)",
+			EnvVars: []string{"ONLY_CHAIN"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "only-avs",
+			Usage:   "Only transport operator tables for these AVS addresses (can be specified multiple times)",
+			EnvVars: []string{"ONLY_AVS"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "exclude-opset",
+			Usage:   "Never transport these operator sets, in format 'avsAddress:operatorSetId' (can be specified multiple times)",
+			EnvVars: []string{"EXCLUDE_OPSET"},
+		},
+		&cli.BoolFlag{
+			Name:    "only-changed",
+			Usage:   "Only transport operator tables that changed since the destination last received them, or that are approaching their max staleness period",
+			EnvVars: []string{"ONLY_CHANGED"},
+		},
+		&cli.StringFlag{
+			Name:    "table-store",
+			Usage:   "JSON file recording the operator tables transported to each chain; consulted before destination state",
+			EnvVars: []string{"TABLE_STORE"},
+		},
+		&cli.Float64Flag{
+			Name:    "staleness-threshold",
+			Usage:   "Fraction of an operator set's max staleness period after which an unchanged table is transported again",
+			Value:   changeDetector.DefaultStalenessThreshold,
+			EnvVars: []string{"STALENESS_THRESHOLD"},
+		},
+	}
+}
+
+// setupChangeDetector creates the detector consulted for change-only transport. It reads the
+// destination's current table from chain, after the --table-store file when one is given.
+func setupChangeDetector(c *cli.Context, cm *chainManager.ChainManager) (*changeDetector.Detector, error) {
+	cfg := &changeDetector.Config{
+		Reader:             changeDetector.NewChainReader(cm),
+		StalenessThreshold: c.Float64("staleness-threshold"),
+	}
+	if path := c.String("table-store"); path != "" {
+		store, err := changeDetector.NewFileStore(path)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Store = store
+	}
+	return changeDetector.NewDetector(cfg)
+}
+
+// logTableDecisions summarizes which operator tables were transported to which chains, and why.
+func logTableDecisions(l *zap.Logger, stakeTransport *transport.Transport) {
+	for _, decision := range stakeTransport.TableDecisions() {
+		l.Sugar().Infow("Operator table decision",
+			"chainId", decision.ChainId,
+			"operatorSet", decision.OperatorSet,
+			"transported", decision.Transported,
+			"reason", decision.Reason,
+		)
+	}
+}
+
+// blockSelectorFlag returns the flag selecting the reference block by finality tag, number, or timestamp.
+func blockSelectorFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "block",
+		Usage:   "Reference block: 'latest', 'safe', 'finalized', any of these with '-N' to go N blocks back, a block number, or 'timestamp:T' for the last block at or before T (Unix or RFC 3339)",
+		EnvVars: []string{"BLOCK"},
+	}
+}
+
+// alignToCadenceFlag returns the flag that aligns the reference block to the registry's table update cadence.
+func alignToCadenceFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:    "align-to-cadence",
+		Usage:   "Move the reference block back to the last block at or before the start of the CrossChainRegistry's table update cadence period",
+		EnvVars: []string{"ALIGN_TO_CADENCE"},
+	}
+}
+
+// transportDurationFlag returns the flag giving the expected duration of a single transport.
+func transportDurationFlag() cli.Flag {
+	return &cli.DurationFlag{
+		Name:    "transport-duration",
+		Usage:   "Expected time for one operator table transport to land, used to predict missed staleness deadlines",
+		Value:   scheduler.DefaultTransportDuration,
+		EnvVars: []string{"TRANSPORT_DURATION"},
+	}
+}
+
+// planSchedule orders the distribution's transports to the registry's destination chains by
+// staleness deadline.
+func planSchedule(
+	ctx context.Context,
+	c *cli.Context,
+	cm *chainManager.ChainManager,
+	stakeTransport *transport.Transport,
+	snap *snapshot.Snapshot,
+	dist *distribution.Distribution,
+) (*scheduler.Plan, error) {
+	pol, err := setupPolicy(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup transport policy: %w", err)
+	}
+	destinations, err := stakeTransport.SupportedDestinations(ctx, snap)
+	if err != nil {
+		return nil, err
+	}
+	reader := scheduler.NewChainReader(cm)
+	sched, err := scheduler.NewScheduler(&scheduler.Config{
+		Reader:            reader,
+		GasOracle:         reader,
+		Policy:            pol,
+		TransportDuration: c.Duration("transport-duration"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scheduler: %w", err)
+	}
+	plan, err := sched.Plan(ctx, destinations, dist)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan transports: %w", err)
+	}
+	return plan, nil
+}
+
+// logScheduleAlerts warns about every staleness deadline the plan expects to miss.
+func logScheduleAlerts(l *zap.Logger, plan *scheduler.Plan) {
+	for _, alert := range plan.Alerts {
+		l.Sugar().Warnw("Operator table will miss its staleness deadline",
+			"chainId", alert.Task.Destination.ChainId,
+			"operatorSet", alert.Task.OperatorSet,
+			"deadline", alert.Task.Deadline,
+			"reason", alert.Reason,
+		)
+	}
+}
+
+// scheduledOrder orders opsets by the plan, followed by any operator sets the plan left out so
+// that their policy decisions are still recorded.
+func scheduledOrder(plan *scheduler.Plan, opsets []distribution.OperatorSet) []distribution.OperatorSet {
+	order := plan.OperatorSetOrder()
+	scheduled := make(map[distribution.OperatorSet]bool, len(order))
+	for _, opset := range order {
+		scheduled[opset] = true
+	}
+	for _, opset := range opsets {
+		if !scheduled[opset] {
+			order = append(order, opset)
+		}
+	}
+	return order
+}
+
+// setupPolicy loads the policy file, if any, and places the filter flags ahead of its rules.
+func setupPolicy(c *cli.Context) (*policy.Policy, error) {
+	var pol *policy.Policy
+	if path := c.String("policy-file"); path != "" {
+		loaded, err := policy.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		pol = loaded
+	}
+
+	filters := &policy.Filters{
+		OnlyChainIds: c.Uint64Slice("only-chain"),
+	}
+	for _, avs := range c.StringSlice("only-avs") {
+		if !common.IsHexAddress(avs) {
+			return nil, fmt.Errorf("invalid AVS address: %s", avs)
+		}
+		filters.OnlyAvs = append(filters.OnlyAvs, common.HexToAddress(avs))
+	}
+	for _, opset := range c.StringSlice("exclude-opset") {
+		parsed, err := parseOperatorSet(opset)
+		if err != nil {
+			return nil, err
+		}
+		filters.ExcludeOperatorSets = append(filters.ExcludeOperatorSets, parsed)
+	}
+
+	if len(filters.Rules()) == 0 {
+		return pol, nil
+	}
+	return pol.WithFilters(filters), nil
+}
+
+// parseOperatorSet parses an operator set in format 'avsAddress:operatorSetId'.
+func parseOperatorSet(value string) (distribution.OperatorSet, error) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 || !common.IsHexAddress(parts[0]) {
+		return distribution.OperatorSet{}, fmt.Errorf("invalid operator set: %s (expected format: 'avsAddress:operatorSetId')", value)
+	}
+	id, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return distribution.OperatorSet{}, fmt.Errorf("invalid operator set ID in %s: %w", value, err)
+	}
+	return distribution.OperatorSet{
+		Id:  uint32(id),
+		Avs: common.HexToAddress(parts[0]),
+	}, nil
+}
+
+func setupTransport(c *cli.Context, cm *chainManager.ChainManager, txSig txSigner.ITransactionSigner, blsSig blsSigner.IBLSSigner, tp trace.TracerProvider, l *zap.Logger) (*transport.Transport, *chainManager.Chain, error) {
 	// Get the first chain as the primary chain for CrossChainRegistry
 	chains := c.StringSlice("chains")
 	if len(chains) == 0 {
@@ -277,8 +933,22 @@ func setupTransport(c *cli.Context, cm *chainManager.ChainManager, txSig txSigne
 	// Parse CrossChainRegistry address
 	registryAddr := common.HexToAddress(c.String("cross-chain-registry"))
 
+	pol, err := setupPolicy(c)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to setup transport policy: %w", err)
+	}
+
+	detector, err := setupChangeDetector(c, cm)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to setup change detector: %w", err)
+	}
+
 	config := &transport.TransportConfig{
 		L1CrossChainRegistryAddress: registryAddr,
+		TracerProvider:              tp,
+		Policy:                      pol,
+		ChangeDetector:              detector,
+		OnlyChangedTables:           c.Bool("only-changed"),
 	}
 
 	transport, err := transport.NewTransport(config, primaryChain.RPCClient, blsSig, txSig, cm, l)
@@ -295,7 +965,13 @@ func transportAction(c *cli.Context) error {
 		return fmt.Errorf("failed to setup logger: %w", err)
 	}
 
-	cm, err := setupChainManager(c)
+	tp, shutdownTracing, err := setupTracing(c)
+	if err != nil {
+		return fmt.Errorf("failed to setup tracing: %w", err)
+	}
+	defer shutdownTracing()
+
+	cm, err := setupChainManager(c, tp, l)
 	if err != nil {
 		return fmt.Errorf("failed to setup chain manager: %w", err)
 	}
@@ -310,47 +986,35 @@ func transportAction(c *cli.Context) error {
 		return fmt.Errorf("failed to setup BLS signer: %w", err)
 	}
 
-	stakeTransport, primaryChain, err := setupTransport(c, cm, txSig, blsSig, l)
+	stakeTransport, primaryChain, err := setupTransport(c, cm, txSig, blsSig, tp, l)
 	if err != nil {
 		return fmt.Errorf("failed to setup transport: %w", err)
 	}
 
 	ctx := context.Background()
 
-	// Determine block number
-	var blockNumber uint64
-	if specified := c.Uint64("block-number"); specified != 0 {
-		blockNumber = specified
-	} else {
-		blockNumber, err = primaryChain.RPCClient.BlockNumber(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get latest block number: %w", err)
-		}
-	}
-
-	// Get block details for timestamp
-	block, err := primaryChain.RPCClient.BlockByNumber(ctx, big.NewInt(int64(blockNumber)))
+	// Pin every L1 read to the reference block
+	snap, err := resolveSnapshot(c, primaryChain)
 	if err != nil {
-		return fmt.Errorf("failed to get block %d: %w", blockNumber, err)
+		return err
 	}
-
-	referenceTimestamp := uint32(block.Time())
+	blockNumber := snap.Number
+	referenceTimestamp := snap.ReferenceTimestamp()
 
 	l.Sugar().Infow("Starting transport operation",
 		"blockNumber", blockNumber,
+		"blockHash", snap.Hash.Hex(),
 		"timestamp", referenceTimestamp,
 	)
 
 	// Calculate stake table root
 	registryAddr := common.HexToAddress(c.String("cross-chain-registry"))
-	tableCalc, err := operatorTableCalculator.NewStakeTableRootCalculator(&operatorTableCalculator.Config{
-		CrossChainRegistryAddress: registryAddr,
-	}, primaryChain.RPCClient, l)
+	tableCalc, err := newStakeTableCalculator(c, registryAddr, primaryChain.RPCClient, tp, l)
 	if err != nil {
 		return fmt.Errorf("failed to create stake table calculator: %w", err)
 	}
 
-	root, tree, dist, err := tableCalc.CalculateStakeTableRoot(ctx, blockNumber)
+	root, tree, dist, err := tableCalc.CalculateStakeTableRootAtSnapshot(ctx, snap)
 	if err != nil {
 		return fmt.Errorf("failed to calculate stake table root: %w", err)
 	}
@@ -360,8 +1024,15 @@ func transportAction(c *cli.Context) error {
 		"blockNumber", blockNumber,
 	)
 
+	if path := c.String("artifact-out"); path != "" {
+		if err := writeArtifact(path, blockNumber, snap.Hash, referenceTimestamp, root, dist); err != nil {
+			return err
+		}
+		l.Sugar().Infow("Wrote distribution artifact", "path", path)
+	}
+
 	// Transport global table root
-	err = stakeTransport.SignAndTransportGlobalTableRoot(ctx, root, referenceTimestamp, blockNumber, nil)
+	err = stakeTransport.SignAndTransportGlobalTableRootForDistributionAtSnapshot(ctx, root, dist, snap, nil)
 	if err != nil {
 		return fmt.Errorf("failed to transport global table root: %w", err)
 	}
@@ -371,12 +1042,20 @@ func transportAction(c *cli.Context) error {
 	// Transport individual AVS stake tables (unless skipped)
 	if !c.Bool("skip-avs-tables") {
 		opsets := dist.GetOperatorSets()
+		if c.Bool("schedule") {
+			plan, err := planSchedule(ctx, c, cm, stakeTransport, snap, dist)
+			if err != nil {
+				return err
+			}
+			logScheduleAlerts(l, plan)
+			opsets = scheduledOrder(plan, opsets)
+		}
 		if len(opsets) == 0 {
 			l.Sugar().Infow("No operator sets found, skipping AVS stake table transport")
 		} else {
 			l.Sugar().Infow("Transporting AVS stake tables", "operatorSetCount", len(opsets))
 			for _, opset := range opsets {
-				err = stakeTransport.SignAndTransportAvsStakeTable(ctx, referenceTimestamp, blockNumber, opset, root, tree, dist, nil)
+				err = stakeTransport.SignAndTransportAvsStakeTableAtSnapshot(ctx, snap, opset, root, tree, dist, nil)
 				if err != nil {
 					return fmt.Errorf("failed to transport AVS stake table for opset %v: %w", opset, err)
 				}
@@ -385,17 +1064,28 @@ func transportAction(c *cli.Context) error {
 		}
 	}
 
+	logTableDecisions(l, stakeTransport)
 	l.Sugar().Infow("Transport operation completed successfully")
 	return nil
 }
 
 func calculateAction(c *cli.Context) error {
+	if output := c.String("output"); output != "text" && output != "json" {
+		return fmt.Errorf("invalid output format %q: must be 'text' or 'json'", output)
+	}
+
 	l, err := setupLogger(c)
 	if err != nil {
 		return fmt.Errorf("failed to setup logger: %w", err)
 	}
 
-	cm, err := setupChainManager(c)
+	tp, shutdownTracing, err := setupTracing(c)
+	if err != nil {
+		return fmt.Errorf("failed to setup tracing: %w", err)
+	}
+	defer shutdownTracing()
+
+	cm, err := setupChainManager(c, tp, l)
 	if err != nil {
 		return fmt.Errorf("failed to setup chain manager: %w", err)
 	}
@@ -417,45 +1107,728 @@ func calculateAction(c *cli.Context) error {
 
 	ctx := context.Background()
 
-	// Determine block number
-	var blockNumber uint64
-	if specified := c.Uint64("block-number"); specified != 0 {
-		blockNumber = specified
-	} else {
-		blockNumber, err = primaryChain.RPCClient.BlockNumber(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get latest block number: %w", err)
-		}
+	// Pin every L1 read to the reference block
+	snap, err := resolveSnapshot(c, primaryChain)
+	if err != nil {
+		return err
 	}
+	blockNumber := snap.Number
 
 	l.Sugar().Infow("Starting calculation",
 		"blockNumber", blockNumber,
+		"blockHash", snap.Hash.Hex(),
 	)
 
 	// Calculate stake table root
 	registryAddr := common.HexToAddress(c.String("cross-chain-registry"))
-	tableCalc, err := operatorTableCalculator.NewStakeTableRootCalculator(&operatorTableCalculator.Config{
-		CrossChainRegistryAddress: registryAddr,
-	}, primaryChain.RPCClient, l)
+	tableCalc, err := newStakeTableCalculator(c, registryAddr, primaryChain.RPCClient, tp, l)
 	if err != nil {
 		return fmt.Errorf("failed to create stake table calculator: %w", err)
 	}
 
-	root, _, dist, err := tableCalc.CalculateStakeTableRoot(ctx, blockNumber)
+	root, _, dist, report, err := tableCalc.CalculateStakeTableRootWithReport(ctx, snap)
 	if err != nil {
 		return fmt.Errorf("failed to calculate stake table root: %w", err)
 	}
 
+	if path := c.String("artifact-out"); path != "" {
+		if err := writeArtifact(path, blockNumber, snap.Hash, snap.ReferenceTimestamp(), root, dist); err != nil {
+			return err
+		}
+		l.Sugar().Infow("Wrote distribution artifact", "path", path)
+	}
+
 	// Display results
+	if c.String("output") == "json" {
+		encoded, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode calculation report: %w", err)
+		}
+		fmt.Println(string(encoded))
+		return nil
+	}
+
 	opsets := dist.GetOperatorSets()
 	fmt.Printf("Stake Table Root: %x\n", root)
 	fmt.Printf("Block Number: %d\n", blockNumber)
+	fmt.Printf("Block Hash: %s\n", snap.Hash.Hex())
+	fmt.Printf("Total Reservations: %d\n", report.TotalReservations)
 	fmt.Printf("Tree Leaves: %d\n", len(opsets))
 	fmt.Printf("Operator Sets: %d\n", len(opsets))
 	for i, opset := range opsets {
 		index, _ := dist.GetTableIndex(opset)
 		fmt.Printf("  [%d] ID: %d, AVS: %s, Index: %d\n", i, opset.Id, opset.Avs.Hex(), index)
 	}
+	fmt.Printf("Skipped Operator Sets: %d\n", len(report.Skipped))
+	for _, skipped := range report.Skipped {
+		fmt.Printf("  ID: %d, AVS: %s, Reason: %s\n", skipped.Id, skipped.Avs.Hex(), skipped.Reason)
+	}
+	if report.CrossChecks != nil {
+		counts := map[operatorTableCalculator.CrossCheckStatus]int{}
+		for _, check := range report.CrossChecks {
+			counts[check.Status]++
+		}
+		fmt.Printf("Cross-Check: %d matched, %d mismatched, %d unavailable\n",
+			counts[operatorTableCalculator.CrossCheckMatch],
+			counts[operatorTableCalculator.CrossCheckMismatch],
+			counts[operatorTableCalculator.CrossCheckUnavailable],
+		)
+		for _, check := range report.CrossChecks {
+			if check.Status == operatorTableCalculator.CrossCheckMismatch {
+				fmt.Printf("  MISMATCH ID: %d, AVS: %s, On-Chain: %s, Local: %s\n",
+					check.Id, check.Avs.Hex(), check.OnChainTableBytesHash.Hex(), check.LocalTableBytesHash.Hex())
+			}
+		}
+	}
+	fmt.Printf("Elapsed: %s\n", time.Duration(report.Elapsed))
+
+	return nil
+}
+
+func transportOpsetAction(c *cli.Context) error {
+	l, err := setupLogger(c)
+	if err != nil {
+		return fmt.Errorf("failed to setup logger: %w", err)
+	}
+
+	artifact, err := distribution.ReadArtifact(c.String("artifact"))
+	if err != nil {
+		return fmt.Errorf("failed to load artifact: %w", err)
+	}
+	opset, err := parseOperatorSet(c.String("operator-set"))
+	if err != nil {
+		return err
+	}
+
+	tp, shutdownTracing, err := setupTracing(c)
+	if err != nil {
+		return fmt.Errorf("failed to setup tracing: %w", err)
+	}
+	defer shutdownTracing()
+
+	cm, err := setupChainManager(c, tp, l)
+	if err != nil {
+		return fmt.Errorf("failed to setup chain manager: %w", err)
+	}
+
+	txSig, err := setupTransactionSigner(c)
+	if err != nil {
+		return fmt.Errorf("failed to setup transaction signer: %w", err)
+	}
 
+	blsSig, err := setupBLSSigner(c, l)
+	if err != nil {
+		return fmt.Errorf("failed to setup BLS signer: %w", err)
+	}
+
+	stakeTransport, _, err := setupTransport(c, cm, txSig, blsSig, tp, l)
+	if err != nil {
+		return fmt.Errorf("failed to setup transport: %w", err)
+	}
+
+	l.Sugar().Infow("Transporting AVS stake table from artifact",
+		"operatorSet", opset,
+		"root", artifact.Root.Hex(),
+		"blockNumber", artifact.BlockNumber,
+		"timestamp", artifact.ReferenceTimestamp,
+	)
+
+	err = stakeTransport.SignAndTransportAvsStakeTableFromArtifact(context.Background(), artifact, opset, nil)
+	if err != nil {
+		return fmt.Errorf("failed to transport AVS stake table for opset %v: %w", opset, err)
+	}
+
+	logTableDecisions(l, stakeTransport)
+	l.Sugar().Infow("Successfully transported AVS stake table", "operatorSet", opset)
+	return nil
+}
+
+func inspectTableAction(c *cli.Context) error {
+	if output := c.String("output"); output != "text" && output != "json" {
+		return fmt.Errorf("invalid output format %q: must be 'text' or 'json'", output)
+	}
+
+	tableBytes, err := loadTableBytes(c)
+	if err != nil {
+		return err
+	}
+
+	table, err := operatorTable.Decode(tableBytes)
+	if err != nil {
+		return err
+	}
+
+	if c.String("output") == "json" {
+		encoded, err := json.MarshalIndent(table, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode operator table: %w", err)
+		}
+		fmt.Println(string(encoded))
+		return nil
+	}
+
+	fmt.Printf("Operator Set: %s:%d\n", table.OperatorSet.Avs.Hex(), table.OperatorSet.Id)
+	fmt.Printf("Curve Type: %s\n", table.CurveType)
+	fmt.Printf("Owner: %s\n", table.Config.Owner.Hex())
+	fmt.Printf("Max Staleness Period: %ds\n", table.Config.MaxStalenessPeriod)
+	switch {
+	case table.BN254 != nil:
+		fmt.Printf("Operator Info Tree Root: %s\n", table.BN254.OperatorInfoTreeRoot.Hex())
+		fmt.Printf("Operators: %s\n", table.BN254.NumOperators)
+		fmt.Printf("Aggregate Pubkey: (%s, %s)\n", table.BN254.AggregatePubkey.X, table.BN254.AggregatePubkey.Y)
+		fmt.Printf("Total Weights: %v\n", table.BN254.TotalWeights)
+	case table.ECDSA != nil:
+		fmt.Printf("Operators: %d\n", len(table.ECDSA))
+		for i, operator := range table.ECDSA {
+			fmt.Printf("  [%d] Pubkey: %s, Weights: %v\n", i, operator.Pubkey.Hex(), operator.Weights)
+		}
+	default:
+		fmt.Printf("Operator Table Info: %s\n", table.OperatorTableInfo)
+	}
+	return nil
+}
+
+func diffAction(c *cli.Context) error {
+	if output := c.String("output"); output != "text" && output != "json" {
+		return fmt.Errorf("invalid output format %q: must be 'text' or 'json'", output)
+	}
+	if to := c.Uint64("to-block"); to != 0 && to <= c.Uint64("from-block") {
+		return fmt.Errorf("--to-block (%d) must be after --from-block (%d)", to, c.Uint64("from-block"))
+	}
+
+	l, err := setupLogger(c)
+	if err != nil {
+		return fmt.Errorf("failed to setup logger: %w", err)
+	}
+
+	tp, shutdownTracing, err := setupTracing(c)
+	if err != nil {
+		return fmt.Errorf("failed to setup tracing: %w", err)
+	}
+	defer shutdownTracing()
+
+	cm, err := setupChainManager(c, tp, l)
+	if err != nil {
+		return fmt.Errorf("failed to setup chain manager: %w", err)
+	}
+
+	parts := strings.SplitN(c.StringSlice("chains")[0], ":", 2)
+	chainID := new(big.Int)
+	chainID, _ = chainID.SetString(parts[0], 10)
+
+	primaryChain, err := cm.GetChainForId(chainID.Uint64())
+	if err != nil {
+		return fmt.Errorf("failed to get primary chain: %w", err)
+	}
+
+	from, err := resolveSnapshotAt(c, primaryChain, c.Uint64("from-block"))
+	if err != nil {
+		return err
+	}
+	to, err := resolveSnapshotAt(c, primaryChain, c.Uint64("to-block"))
+	if err != nil {
+		return err
+	}
+	if to.Number <= from.Number {
+		return fmt.Errorf("latest block %d is not after --from-block %d", to.Number, from.Number)
+	}
+
+	l.Sugar().Infow("Comparing stake tables",
+		"fromBlock", from.Number,
+		"toBlock", to.Number,
+	)
+
+	registryAddr := common.HexToAddress(c.String("cross-chain-registry"))
+	tableCalc, err := newStakeTableCalculator(c, registryAddr, primaryChain.RPCClient, tp, l)
+	if err != nil {
+		return fmt.Errorf("failed to create stake table calculator: %w", err)
+	}
+
+	diff, err := tableCalc.CalculateStakeTableDiff(context.Background(), from, to)
+	if err != nil {
+		return err
+	}
+
+	if c.String("output") == "json" {
+		encoded, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode stake table diff: %w", err)
+		}
+		fmt.Println(string(encoded))
+		return nil
+	}
+
+	fmt.Printf("From Block: %d (%s)\n", diff.FromBlockNumber, diff.FromBlockHash.Hex())
+	fmt.Printf("To Block: %d (%s)\n", diff.ToBlockNumber, diff.ToBlockHash.Hex())
+	fmt.Printf("From Root: %s\n", diff.FromRoot.Hex())
+	fmt.Printf("To Root: %s\n", diff.ToRoot.Hex())
+	fmt.Printf("Unchanged Operator Sets: %d\n", diff.Unchanged)
+	fmt.Printf("Added Operator Sets: %d\n", len(diff.Added))
+	for _, added := range diff.Added {
+		fmt.Printf("  + ID: %d, AVS: %s, Curve: %s, Operators: %d, Total Weights: %v\n",
+			added.Id, added.Avs.Hex(), added.CurveType, added.OperatorCount, added.TotalWeights)
+	}
+	fmt.Printf("Removed Operator Sets: %d\n", len(diff.Removed))
+	for _, removed := range diff.Removed {
+		fmt.Printf("  - ID: %d, AVS: %s, Curve: %s, Operators: %d, Total Weights: %v\n",
+			removed.Id, removed.Avs.Hex(), removed.CurveType, removed.OperatorCount, removed.TotalWeights)
+	}
+	fmt.Printf("Changed Operator Sets: %d\n", len(diff.Changed))
+	for _, changed := range diff.Changed {
+		if changed.DecodeError != "" {
+			fmt.Printf("  ~ ID: %d, AVS: %s, Table bytes changed (%s)\n", changed.Id, changed.Avs.Hex(), changed.DecodeError)
+			continue
+		}
+		fmt.Printf("  ~ ID: %d, AVS: %s, Curve: %s, Operators: %+d, Total Weights: %v, Aggregate Key Changed: %t, Config Changed: %t\n",
+			changed.Id, changed.Avs.Hex(), changed.CurveType, changed.OperatorCountDelta, changed.TotalWeightDeltas,
+			changed.AggregatePubkeyChanged, changed.ConfigChanged)
+	}
+	return nil
+}
+
+func scheduleAction(c *cli.Context) error {
+	if output := c.String("output"); output != "text" && output != "json" {
+		return fmt.Errorf("invalid output format %q: must be 'text' or 'json'", output)
+	}
+
+	l, err := setupLogger(c)
+	if err != nil {
+		return fmt.Errorf("failed to setup logger: %w", err)
+	}
+
+	tp, shutdownTracing, err := setupTracing(c)
+	if err != nil {
+		return fmt.Errorf("failed to setup tracing: %w", err)
+	}
+	defer shutdownTracing()
+
+	cm, err := setupChainManager(c, tp, l)
+	if err != nil {
+		return fmt.Errorf("failed to setup chain manager: %w", err)
+	}
+
+	// Planning sends no transactions, so no signers are needed
+	stakeTransport, primaryChain, err := setupTransport(c, cm, nil, nil, tp, l)
+	if err != nil {
+		return fmt.Errorf("failed to setup transport: %w", err)
+	}
+
+	ctx := context.Background()
+	snap, err := resolveSnapshot(c, primaryChain)
+	if err != nil {
+		return err
+	}
+
+	registryAddr := common.HexToAddress(c.String("cross-chain-registry"))
+	tableCalc, err := newStakeTableCalculator(c, registryAddr, primaryChain.RPCClient, tp, l)
+	if err != nil {
+		return fmt.Errorf("failed to create stake table calculator: %w", err)
+	}
+	_, _, dist, err := tableCalc.CalculateStakeTableRootAtSnapshot(ctx, snap)
+	if err != nil {
+		return fmt.Errorf("failed to calculate stake table root: %w", err)
+	}
+
+	plan, err := planSchedule(ctx, c, cm, stakeTransport, snap, dist)
+	if err != nil {
+		return err
+	}
+	logScheduleAlerts(l, plan)
+
+	if c.String("output") == "json" {
+		encoded, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode transport plan: %w", err)
+		}
+		fmt.Println(string(encoded))
+		return nil
+	}
+
+	fmt.Printf("Generated At: %s\n", plan.GeneratedAt.Format(time.RFC3339))
+	fmt.Printf("Transports: %d\n", len(plan.Tasks))
+	for i, task := range plan.Tasks {
+		deadline := "never stale"
+		switch {
+		case task.LastUpdated.IsZero():
+			deadline = "no table on destination"
+		case task.HasDeadline():
+			deadline = "stale at " + task.Deadline.Format(time.RFC3339)
+		}
+		fmt.Printf("  %d. Chain: %d, ID: %d, AVS: %s, Curve: %s, %s, ETA: %s\n",
+			i+1, task.Destination.ChainId, task.OperatorSet.Id, task.OperatorSet.Avs.Hex(), task.CurveType,
+			deadline, task.EstimatedCompletion.Format(time.RFC3339))
+	}
+	fmt.Printf("Alerts: %d\n", len(plan.Alerts))
+	for _, alert := range plan.Alerts {
+		fmt.Printf("  ! Chain: %d, ID: %d, AVS: %s, %s\n",
+			alert.Task.Destination.ChainId, alert.Task.OperatorSet.Id, alert.Task.OperatorSet.Avs.Hex(), alert.Reason)
+	}
+	return nil
+}
+
+func backfillAction(c *cli.Context) error {
+	if output := c.String("output"); output != "csv" && output != "json" {
+		return fmt.Errorf("invalid output format %q: must be 'csv' or 'json'", output)
+	}
+	if c.Uint64("step") == 0 {
+		return fmt.Errorf("--step must be at least 1")
+	}
+	if to := c.Uint64("to"); to != 0 && to < c.Uint64("from") {
+		return fmt.Errorf("--to (%d) must not be before --from (%d)", to, c.Uint64("from"))
+	}
+
+	l, err := setupLogger(c)
+	if err != nil {
+		return fmt.Errorf("failed to setup logger: %w", err)
+	}
+
+	tp, shutdownTracing, err := setupTracing(c)
+	if err != nil {
+		return fmt.Errorf("failed to setup tracing: %w", err)
+	}
+	defer shutdownTracing()
+
+	cm, err := setupChainManager(c, tp, l)
+	if err != nil {
+		return fmt.Errorf("failed to setup chain manager: %w", err)
+	}
+
+	parts := strings.SplitN(c.StringSlice("chains")[0], ":", 2)
+	chainID := new(big.Int)
+	chainID, _ = chainID.SetString(parts[0], 10)
+
+	primaryChain, err := cm.GetChainForId(chainID.Uint64())
+	if err != nil {
+		return fmt.Errorf("failed to get primary chain: %w", err)
+	}
+
+	toBlock := c.Uint64("to")
+	if toBlock == 0 {
+		latest, err := resolveSnapshotAt(c, primaryChain, 0)
+		if err != nil {
+			return err
+		}
+		toBlock = latest.Number
+		if toBlock < c.Uint64("from") {
+			return fmt.Errorf("latest block %d is before --from %d", toBlock, c.Uint64("from"))
+		}
+	}
+	backfillRange := operatorTableCalculator.BackfillRange{
+		From:        c.Uint64("from"),
+		To:          toBlock,
+		Step:        c.Uint64("step"),
+		Parallelism: c.Int("block-parallelism"),
+	}
+
+	l.Sugar().Infow("Starting stake table root backfill",
+		"fromBlock", backfillRange.From,
+		"toBlock", backfillRange.To,
+		"step", backfillRange.Step,
+		"sampleCount", len(backfillRange.Blocks()),
+	)
+
+	registryAddr := common.HexToAddress(c.String("cross-chain-registry"))
+	tableCalc, err := newStakeTableCalculator(c, registryAddr, primaryChain.RPCClient, tp, l)
+	if err != nil {
+		return fmt.Errorf("failed to create stake table calculator: %w", err)
+	}
+	if c.Bool("reuse-unchanged") {
+		detector, err := localTableCalculator.NewChangeDetector(&localTableCalculator.ChangeDetectorConfig{
+			CrossChainRegistryAddress: registryAddr,
+			HistoryFromBlock:          c.Uint64("history-from-block"),
+			LogRangeBlocks:            c.Uint64("log-range"),
+		}, primaryChain.RPCClient, l)
+		if err != nil {
+			return fmt.Errorf("failed to create change detector: %w", err)
+		}
+		backfillRange.ChangeSource = detector
+	}
+
+	samples, err := tableCalc.CalculateStakeTableRootRange(context.Background(), backfillRange)
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if path := c.String("output-file"); path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if c.String("output") == "json" {
+		encoded, err := json.MarshalIndent(samples, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode root samples: %w", err)
+		}
+		if _, err := fmt.Fprintln(out, string(encoded)); err != nil {
+			return fmt.Errorf("failed to write root samples: %w", err)
+		}
+	} else if err := operatorTableCalculator.WriteRootSamplesCSV(out, samples); err != nil {
+		return err
+	}
+
+	l.Sugar().Infow("Completed stake table root backfill", "sampleCount", len(samples))
+	return nil
+}
+
+func verifyAction(c *cli.Context) error {
+	if output := c.String("output"); output != "text" && output != "json" {
+		return fmt.Errorf("invalid output format %q: must be 'text' or 'json'", output)
+	}
+
+	l, err := setupLogger(c)
+	if err != nil {
+		return fmt.Errorf("failed to setup logger: %w", err)
+	}
+
+	tp, shutdownTracing, err := setupTracing(c)
+	if err != nil {
+		return fmt.Errorf("failed to setup tracing: %w", err)
+	}
+	defer shutdownTracing()
+
+	cm, err := setupChainManager(c, tp, l)
+	if err != nil {
+		return fmt.Errorf("failed to setup chain manager: %w", err)
+	}
+
+	// Verification sends no transactions, so no signers are needed
+	stakeTransport, primaryChain, err := setupTransport(c, cm, nil, nil, tp, l)
+	if err != nil {
+		return fmt.Errorf("failed to setup transport: %w", err)
+	}
+
+	ctx := context.Background()
+	snap, err := resolveSnapshot(c, primaryChain)
+	if err != nil {
+		return err
+	}
+
+	registryAddr := common.HexToAddress(c.String("cross-chain-registry"))
+	tableCalc, err := newStakeTableCalculator(c, registryAddr, primaryChain.RPCClient, tp, l)
+	if err != nil {
+		return fmt.Errorf("failed to create stake table calculator: %w", err)
+	}
+	root, _, dist, err := tableCalc.CalculateStakeTableRootAtSnapshot(ctx, snap)
+	if err != nil {
+		return fmt.Errorf("failed to calculate stake table root: %w", err)
+	}
+
+	destinations, err := stakeTransport.SupportedDestinations(ctx, snap)
+	if err != nil {
+		return err
+	}
+	pol, err := setupPolicy(c)
+	if err != nil {
+		return fmt.Errorf("failed to setup transport policy: %w", err)
+	}
+	v, err := verifier.NewVerifier(&verifier.Config{
+		Reader: verifier.NewChainReader(cm),
+		Policy: pol,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create verifier: %w", err)
+	}
+
+	l.Sugar().Infow("Verifying destination chains",
+		"blockNumber", snap.Number,
+		"referenceTimestamp", snap.ReferenceTimestamp(),
+		"root", fmt.Sprintf("%x", root),
+		"chainCount", len(destinations),
+	)
+	report, err := v.Verify(ctx, snap, root, dist, destinations)
+	if err != nil {
+		return err
+	}
+
+	if c.String("output") == "json" {
+		encoded, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode verification report: %w", err)
+		}
+		fmt.Println(string(encoded))
+	} else {
+		printVerificationReport(report)
+	}
+
+	if !report.OK() {
+		return fmt.Errorf("verification failed: %d checks mismatched or could not be read", report.FailureCount())
+	}
+	return nil
+}
+
+// printVerificationReport prints one line per destination chain root and operator table check.
+func printVerificationReport(report *verifier.Report) {
+	fmt.Printf("Block Number: %d (%s)\n", report.BlockNumber, report.BlockHash.Hex())
+	fmt.Printf("Reference Timestamp: %d\n", report.ReferenceTimestamp)
+	fmt.Printf("Root: %s\n", report.Root.Hex())
+	for _, chain := range report.Chains {
+		fmt.Printf("Chain %d (%s): root %s", chain.ChainId, chain.OperatorTableUpdater.Hex(), chain.RootStatus)
+		if chain.RootReason != "" {
+			fmt.Printf(" (%s)", chain.RootReason)
+		}
+		fmt.Println()
+		for _, opset := range chain.OperatorSets {
+			fmt.Printf("  %-8s ID: %d, AVS: %s, Curve: %s", opset.Status, opset.Id, opset.Avs.Hex(), opset.CurveType)
+			if opset.Reason != "" {
+				fmt.Printf(" (%s)", opset.Reason)
+			}
+			fmt.Println()
+		}
+	}
+	fmt.Printf("Failed Checks: %d\n", report.FailureCount())
+}
+
+// loadTableBytes returns the operator table bytes given by --table-bytes, read from --artifact,
+// or calculated on the primary chain.
+func loadTableBytes(c *cli.Context) ([]byte, error) {
+	if raw := c.String("table-bytes"); raw != "" {
+		tableBytes, err := hexutil.Decode(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid table bytes: %w", err)
+		}
+		return tableBytes, nil
+	}
+
+	if c.String("operator-set") == "" {
+		return nil, fmt.Errorf("must specify --operator-set or --table-bytes")
+	}
+	opset, err := parseOperatorSet(c.String("operator-set"))
+	if err != nil {
+		return nil, err
+	}
+
+	if path := c.String("artifact"); path != "" {
+		artifact, err := distribution.ReadArtifact(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load artifact: %w", err)
+		}
+		dist, err := artifact.Distribution()
+		if err != nil {
+			return nil, fmt.Errorf("invalid artifact: %w", err)
+		}
+		tableBytes, ok := dist.GetTableData(opset)
+		if !ok {
+			return nil, fmt.Errorf("operator set %s with ID %d is not in the artifact", opset.Avs.String(), opset.Id)
+		}
+		return tableBytes, nil
+	}
+
+	l, err := setupLogger(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup logger: %w", err)
+	}
+
+	tp, shutdownTracing, err := setupTracing(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup tracing: %w", err)
+	}
+	defer shutdownTracing()
+
+	cm, err := setupChainManager(c, tp, l)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup chain manager: %w", err)
+	}
+
+	parts := strings.SplitN(c.StringSlice("chains")[0], ":", 2)
+	chainID := new(big.Int)
+	chainID, _ = chainID.SetString(parts[0], 10)
+
+	primaryChain, err := cm.GetChainForId(chainID.Uint64())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get primary chain: %w", err)
+	}
+
+	snap, err := resolveSnapshot(c, primaryChain)
+	if err != nil {
+		return nil, err
+	}
+
+	registryAddr := common.HexToAddress(c.String("cross-chain-registry"))
+	tableCalc, err := operatorTableCalculator.NewStakeTableRootCalculator(&operatorTableCalculator.Config{
+		CrossChainRegistryAddress: registryAddr,
+		TracerProvider:            tp,
+	}, primaryChain.RPCClient, l)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stake table calculator: %w", err)
+	}
+
+	tableBytes, err := tableCalc.CalculateOperatorTableBytesAtSnapshot(c.Context, snap, ICrossChainRegistry.OperatorSet{
+		Avs: opset.Avs,
+		Id:  opset.Id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate operator table bytes: %w", err)
+	}
+	return tableBytes, nil
+}
+
+// resolveSnapshot pins the reference block given by --block or --block-number, or the latest
+// block, on the primary chain. With --align-to-cadence, the block is moved back to the start of
+// the CrossChainRegistry's table update cadence period.
+func resolveSnapshot(c *cli.Context, primaryChain *chainManager.Chain) (*snapshot.Snapshot, error) {
+	selector, err := referenceBlockSelector(c)
+	if err != nil {
+		return nil, err
+	}
+	if c.Bool("align-to-cadence") {
+		registryAddr := common.HexToAddress(c.String("cross-chain-registry"))
+		registry, err := ICrossChainRegistry.NewICrossChainRegistryCaller(registryAddr, primaryChain.RPCClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to bind NewICrossChainRegistryCaller: %w", err)
+		}
+		cadence, err := registry.GetTableUpdateCadence(&bind.CallOpts{Context: c.Context})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get table update cadence: %w", err)
+		}
+		selector.Cadence = cadence
+	}
+	snap, err := selector.Resolve(c.Context, primaryChain.RPCClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve reference block %s: %w", selector, err)
+	}
+	return snap, nil
+}
+
+// referenceBlockSelector parses --block, falling back to --block-number and then the latest block.
+func referenceBlockSelector(c *cli.Context) (snapshot.Selector, error) {
+	if c.IsSet("block") && c.IsSet("block-number") {
+		return snapshot.Selector{}, fmt.Errorf("cannot specify both --block and --block-number")
+	}
+	if c.IsSet("block") {
+		return snapshot.ParseSelector(c.String("block"))
+	}
+	if number := c.Uint64("block-number"); number != 0 {
+		return snapshot.Selector{Kind: snapshot.SelectNumber, Number: number}, nil
+	}
+	return snapshot.Selector{Kind: snapshot.SelectLatest}, nil
+}
+
+// resolveSnapshotAt pins the given block number, or the latest block if it is zero, on the primary chain.
+func resolveSnapshotAt(c *cli.Context, primaryChain *chainManager.Chain, blockNumber uint64) (*snapshot.Snapshot, error) {
+	var number *big.Int
+	if blockNumber != 0 {
+		number = new(big.Int).SetUint64(blockNumber)
+	}
+	snap, err := snapshot.Resolve(c.Context, primaryChain.RPCClient, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve reference block: %w", err)
+	}
+	return snap, nil
+}
+
+// writeArtifact persists a calculated distribution so individual operator sets can be re-transported later.
+func writeArtifact(path string, blockNumber uint64, blockHash common.Hash, referenceTimestamp uint32, root [32]byte, dist *distribution.Distribution) error {
+	artifact, err := distribution.NewArtifact(blockNumber, blockHash, referenceTimestamp, root, dist)
+	if err != nil {
+		return fmt.Errorf("failed to build artifact: %w", err)
+	}
+	if err := distribution.WriteArtifact(path, artifact); err != nil {
+		return err
+	}
 	return nil
 }
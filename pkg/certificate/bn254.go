@@ -0,0 +1,230 @@
+package certificate
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/crypto-libs/pkg/bn254"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IBN254CertificateVerifier"
+	"github.com/Layr-Labs/multichain-go/pkg/blsSigner"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorInfoTree"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// BN254_CERTIFICATE_TYPEHASH is the type hash of BN254 certificate digests, matching
+// BN254CertificateVerifierStorage.BN254_CERTIFICATE_TYPEHASH.
+var BN254_CERTIFICATE_TYPEHASH = crypto.Keccak256Hash([]byte("BN254Certificate(uint32 referenceTimestamp,bytes32 messageHash)"))
+
+// certificateDigestArgs is the (typehash, referenceTimestamp, messageHash) struct hash
+// encoding both certificate verifiers use.
+var certificateDigestArgs = abi.Arguments{
+	{Type: mustNewType("bytes32")},
+	{Type: mustNewType("uint32")},
+	{Type: mustNewType("bytes32")},
+}
+
+func mustNewType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(fmt.Sprintf("invalid ABI type %s: %v", t, err))
+	}
+	return typ
+}
+
+// BN254CertificateDigest returns the digest operators sign for a BN254 certificate, matching
+// BN254CertificateVerifier.calculateCertificateDigest.
+//
+// Parameters:
+//   - referenceTimestamp: The reference timestamp of the operator table the certificate is for
+//   - messageHash: The hash of the task response
+//
+// Returns:
+//   - [32]byte: keccak256(abi.encode(BN254_CERTIFICATE_TYPEHASH, referenceTimestamp, messageHash))
+func BN254CertificateDigest(referenceTimestamp uint32, messageHash [32]byte) [32]byte {
+	encoded, err := certificateDigestArgs.Pack(BN254_CERTIFICATE_TYPEHASH, referenceTimestamp, messageHash)
+	if err != nil {
+		// Static types always pack
+		panic(fmt.Sprintf("failed to pack certificate digest: %v", err))
+	}
+	return crypto.Keccak256Hash(encoded)
+}
+
+// BN254Signature is one operator's signature over a BN254 certificate digest.
+type BN254Signature struct {
+	// PublicKey is the operator's key, with both its G1 and G2 points
+	PublicKey *bn254.PublicKey
+	// Signature is the operator's signature over BN254CertificateDigest
+	Signature *bn254.Signature
+}
+
+// SignBN254 signs a BN254 certificate digest with an operator's BLS signer.
+//
+// Parameters:
+//   - signer: The operator's BLS signer
+//   - referenceTimestamp: The reference timestamp of the operator table the certificate is for
+//   - messageHash: The hash of the task response
+//
+// Returns:
+//   - *BN254Signature: The operator's key and signature
+//   - error: An error if the signer fails
+func SignBN254(signer blsSigner.IBLSSigner, referenceTimestamp uint32, messageHash [32]byte) (*BN254Signature, error) {
+	publicKey, err := signer.GetPublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public key: %w", err)
+	}
+	signature, err := signer.SignBytes(BN254CertificateDigest(referenceTimestamp, messageHash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign certificate digest: %w", err)
+	}
+	return &BN254Signature{PublicKey: publicKey, Signature: signature}, nil
+}
+
+// BN254CertificateRequest holds what is needed to build a BN254 certificate.
+type BN254CertificateRequest struct {
+	// ReferenceTimestamp is the reference timestamp of the operator table on the destination chain
+	ReferenceTimestamp uint32
+	// MessageHash is the hash of the task response
+	MessageHash [32]byte
+	// OperatorSetInfo is the operator set's BN254 table at the reference timestamp
+	OperatorSetInfo *operatorTable.BN254OperatorSetInfo
+	// Operators are the operator infos of the table, in table order, such as
+	// localTableCalculator.Result.BN254OperatorInfos at the table's reference block
+	Operators []operatorTable.BN254OperatorInfo
+	// Signatures are the signing operators' signatures, in any order
+	Signatures []BN254Signature
+}
+
+// BN254Certificate is a certificate for the BN254CertificateVerifier along with the operators
+// it counts as signers and non-signers.
+type BN254Certificate struct {
+	// Certificate is the certificate to pass to verifyCertificate
+	Certificate IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254Certificate
+	// SignerIndices are the signing operators' indices in the table, ascending
+	SignerIndices []uint32
+	// NonSignerIndices are the other operators' indices in the table, ascending
+	NonSignerIndices []uint32
+}
+
+// BuildBN254Certificate builds a BN254 certificate from operator signatures. Each signature
+// is matched to its operator by G1 key and checked against the certificate digest, so a bad
+// signature is reported against its signer instead of failing the aggregate on-chain. The
+// operator infos are checked against the table's operator info tree root before any
+// non-signer witness is produced.
+//
+// Parameters:
+//   - req: The reference timestamp, message hash, operator table and signatures
+//
+// Returns:
+//   - *BN254Certificate: The certificate and its signer and non-signer indices
+//   - error: An error wrapping ErrNoSigners, ErrUnknownSigner, ErrDuplicateSigner or
+//     ErrInvalidSignature for bad signatures, or an error if the operator infos do not make
+//     up the table
+func BuildBN254Certificate(req *BN254CertificateRequest) (*BN254Certificate, error) {
+	if len(req.Signatures) == 0 {
+		return nil, ErrNoSigners
+	}
+	tree, err := operatorInfoTree.NewForTable(req.OperatorSetInfo, req.Operators)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build operator info tree: %w", err)
+	}
+	digest := BN254CertificateDigest(req.ReferenceTimestamp, req.MessageHash)
+
+	signed := make(map[uint32]bool, len(req.Signatures))
+	signatures := make([]*bn254.Signature, 0, len(req.Signatures))
+	apk := bn254.NewZeroG2Point()
+	for _, sig := range req.Signatures {
+		if sig.PublicKey == nil || sig.PublicKey.GetG1Point() == nil || sig.PublicKey.GetG2Point() == nil || sig.Signature == nil {
+			return nil, fmt.Errorf("%w: signature needs a G1 and G2 public key", ErrInvalidSignature)
+		}
+		g1 := sig.PublicKey.GetG1Point()
+		pubkey := operatorTable.G1Point{X: g1.X.BigInt(new(big.Int)), Y: g1.Y.BigInt(new(big.Int))}
+		index, ok := tree.IndexOf(pubkey)
+		if !ok {
+			return nil, fmt.Errorf("%w: G1 key (%s, %s)", ErrUnknownSigner, pubkey.X, pubkey.Y)
+		}
+		if signed[index] {
+			return nil, fmt.Errorf("%w: operator %d", ErrDuplicateSigner, index)
+		}
+		valid, err := sig.Signature.VerifySolidityCompatible(sig.PublicKey, digest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify signature of operator %d: %w", index, err)
+		}
+		if !valid {
+			return nil, fmt.Errorf("%w: operator %d", ErrInvalidSignature, index)
+		}
+		signed[index] = true
+		signatures = append(signatures, sig.Signature)
+		apk.AddPublicKey(sig.PublicKey)
+	}
+
+	aggregate, err := bn254.AggregateSignatures(signatures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate signatures: %w", err)
+	}
+	signatureG1, err := toBindingG1(&bn254.G1Point{G1Affine: aggregate.GetG1Point()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert aggregate signature: %w", err)
+	}
+	apkG2, err := toBindingG2(apk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert aggregate public key: %w", err)
+	}
+
+	result := &BN254Certificate{
+		Certificate: IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254Certificate{
+			ReferenceTimestamp: req.ReferenceTimestamp,
+			MessageHash:        req.MessageHash,
+			Signature:          signatureG1,
+			Apk:                apkG2,
+			NonSignerWitnesses: []IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254OperatorInfoWitness{},
+		},
+	}
+	// The verifier requires non-signer witnesses in strictly increasing index order
+	for i := 0; i < tree.Len(); i++ {
+		index := uint32(i)
+		if signed[index] {
+			result.SignerIndices = append(result.SignerIndices, index)
+			continue
+		}
+		proof, err := tree.Proof(index)
+		if err != nil {
+			return nil, err
+		}
+		result.NonSignerIndices = append(result.NonSignerIndices, index)
+		result.Certificate.NonSignerWitnesses = append(result.Certificate.NonSignerWitnesses, proof.Witness())
+	}
+	return result, nil
+}
+
+// toBindingG1 converts a G1 point to the contract's (X, Y) representation.
+func toBindingG1(p *bn254.G1Point) (IBN254CertificateVerifier.BN254G1Point, error) {
+	g1Bytes, err := p.ToPrecompileFormat()
+	if err != nil {
+		return IBN254CertificateVerifier.BN254G1Point{}, err
+	}
+	return IBN254CertificateVerifier.BN254G1Point{
+		X: new(big.Int).SetBytes(g1Bytes[0:32]),
+		Y: new(big.Int).SetBytes(g1Bytes[32:64]),
+	}, nil
+}
+
+// toBindingG2 converts a G2 point to the contract's representation, whose coordinates put the
+// imaginary part first, as the precompile format does.
+func toBindingG2(p *bn254.G2Point) (IBN254CertificateVerifier.BN254G2Point, error) {
+	g2Bytes, err := p.ToPrecompileFormat()
+	if err != nil {
+		return IBN254CertificateVerifier.BN254G2Point{}, err
+	}
+	return IBN254CertificateVerifier.BN254G2Point{
+		X: [2]*big.Int{
+			new(big.Int).SetBytes(g2Bytes[0:32]),
+			new(big.Int).SetBytes(g2Bytes[32:64]),
+		},
+		Y: [2]*big.Int{
+			new(big.Int).SetBytes(g2Bytes[64:96]),
+			new(big.Int).SetBytes(g2Bytes[96:128]),
+		},
+	}, nil
+}
@@ -0,0 +1,235 @@
+package certificate
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/crypto-libs/pkg/bn254"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IBN254CertificateVerifier"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorInfoTree"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	gnarkbn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// frModulus is the order of the BN254 groups, BN254.FR_MODULUS in Solidity.
+var frModulus, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+
+// BN254VerificationResult is the outcome of verifying a BN254 certificate against a table.
+type BN254VerificationResult struct {
+	// SignedWeights is the table's total weight of each weight type minus the non-signers'
+	// weights, as verifyCertificate returns it
+	SignedWeights []*big.Int
+	// TotalWeights is the table's total weight of each weight type
+	TotalWeights []*big.Int
+	// NonSignerIndices are the non-signers' operator indices, ascending
+	NonSignerIndices []uint32
+	// SignerApk is the table's aggregate G1 key minus the non-signers' keys
+	SignerApk operatorTable.G1Point
+}
+
+// VerifyBN254Certificate verifies a BN254 certificate against the operator table stored for
+// its reference timestamp, with the same checks as BN254CertificateVerifier._verifyCertificate:
+// non-signer indices must be strictly increasing and in range, and each non-signer's operator
+// info must be proven against the operator info tree root. The non-signers' keys are
+// subtracted from the table's aggregate key and the signature is checked with
+// BN254SignatureVerifier's gamma-combined pairing over calculateCertificateDigest.
+//
+// Staleness, whether the reference timestamp's table was transported and whether its root is
+// disabled depend on destination chain state and are not checked. Unlike the contract, every
+// non-signer proof is checked, whereas the contract skips proofs of operator infos it cached
+// from earlier certificates.
+//
+// Parameters:
+//   - info: The operator set's BN254 table at the certificate's reference timestamp
+//   - cert: The certificate
+//
+// Returns:
+//   - *BN254VerificationResult: The signed weights and the signers' aggregate key
+//   - error: An error wrapping ErrNonSignerIndicesNotSorted, ErrInvalidOperatorIndex or
+//     ErrVerificationFailed where the contract would revert
+func VerifyBN254Certificate(
+	info *operatorTable.BN254OperatorSetInfo,
+	cert *IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254Certificate,
+) (*BN254VerificationResult, error) {
+	if info == nil {
+		return nil, fmt.Errorf("operator table has no BN254 operator set info")
+	}
+	numOperators := info.NumOperators
+	if numOperators == nil {
+		numOperators = new(big.Int)
+	}
+
+	result := &BN254VerificationResult{
+		SignedWeights:    make([]*big.Int, len(info.TotalWeights)),
+		TotalWeights:     info.TotalWeights,
+		NonSignerIndices: make([]uint32, 0, len(cert.NonSignerWitnesses)),
+	}
+	for i, weight := range info.TotalWeights {
+		result.SignedWeights[i] = new(big.Int).Set(weight)
+	}
+
+	var nonSignerApk gnarkbn254.G1Affine
+	for i, witness := range cert.NonSignerWitnesses {
+		if i > 0 && witness.OperatorIndex <= cert.NonSignerWitnesses[i-1].OperatorIndex {
+			return nil, fmt.Errorf("%w: index %d follows %d", ErrNonSignerIndicesNotSorted, witness.OperatorIndex, cert.NonSignerWitnesses[i-1].OperatorIndex)
+		}
+		if new(big.Int).SetUint64(uint64(witness.OperatorIndex)).Cmp(numOperators) >= 0 {
+			return nil, fmt.Errorf("%w: %d of %s operators", ErrInvalidOperatorIndex, witness.OperatorIndex, numOperators)
+		}
+
+		operatorInfo := operatorTable.BN254OperatorInfo{
+			Pubkey:  operatorTable.G1Point{X: witness.OperatorInfo.Pubkey.X, Y: witness.OperatorInfo.Pubkey.Y},
+			Weights: witness.OperatorInfo.Weights,
+		}
+		proof := &operatorInfoTree.OperatorProof{Index: witness.OperatorIndex, OperatorInfo: operatorInfo, Proof: witness.OperatorInfoProof}
+		if !proof.Verify(info.OperatorInfoTreeRoot) {
+			return nil, fmt.Errorf("%w: invalid operator info proof for non-signer %d", ErrVerificationFailed, witness.OperatorIndex)
+		}
+
+		pubkey, err := toGnarkG1(operatorInfo.Pubkey.X, operatorInfo.Pubkey.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pubkey of non-signer %d: %w", witness.OperatorIndex, err)
+		}
+		nonSignerApk.Add(&nonSignerApk, pubkey)
+		for j, weight := range operatorInfo.Weights {
+			if j >= len(result.SignedWeights) {
+				break
+			}
+			// The contract's checked subtraction reverts on underflow
+			if result.SignedWeights[j].Cmp(weight) < 0 {
+				return nil, fmt.Errorf("non-signer weights exceed total weight %d", j)
+			}
+			result.SignedWeights[j].Sub(result.SignedWeights[j], weight)
+		}
+		result.NonSignerIndices = append(result.NonSignerIndices, witness.OperatorIndex)
+	}
+
+	aggregate, err := toGnarkG1(info.AggregatePubkey.X, info.AggregatePubkey.Y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid aggregate pubkey: %w", err)
+	}
+	var signerApk gnarkbn254.G1Affine
+	signerApk.Sub(aggregate, &nonSignerApk)
+	result.SignerApk = operatorTable.G1Point{X: signerApk.X.BigInt(new(big.Int)), Y: signerApk.Y.BigInt(new(big.Int))}
+
+	digest := BN254CertificateDigest(cert.ReferenceTimestamp, cert.MessageHash)
+	valid, err := verifyBN254Signature(digest, &signerApk, cert.Apk, cert.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrVerificationFailed, err)
+	}
+	if !valid {
+		return nil, fmt.Errorf("%w: signature does not verify against the signers' aggregate key", ErrVerificationFailed)
+	}
+	return result, nil
+}
+
+// MeetsProportionThresholds reports whether each weight type's signed weight reaches its
+// threshold, as verifyCertificateProportion does.
+//
+// Parameters:
+//   - thresholds: The minimum signed proportion of each weight type's total, in basis points
+//
+// Returns:
+//   - bool: Whether every threshold is met
+//   - error: An error wrapping ErrArrayLengthMismatch if there is not one threshold per weight type
+func (r *BN254VerificationResult) MeetsProportionThresholds(thresholds []uint16) (bool, error) {
+	return meetsProportionThresholds(r.SignedWeights, r.TotalWeights, thresholds)
+}
+
+// MeetsNominalThresholds reports whether each weight type's signed weight reaches its
+// threshold, as verifyCertificateNominal does.
+//
+// Parameters:
+//   - thresholds: The minimum signed weight of each weight type
+//
+// Returns:
+//   - bool: Whether every threshold is met
+//   - error: An error wrapping ErrArrayLengthMismatch if there is not one threshold per weight type
+func (r *BN254VerificationResult) MeetsNominalThresholds(thresholds []*big.Int) (bool, error) {
+	return meetsNominalThresholds(r.SignedWeights, thresholds)
+}
+
+// verifyBN254Signature mirrors BN254SignatureVerifier.verifySignature: with
+// gamma = keccak256(msgHash, pubkeyG1, pubkeyG2, signature) mod r, it checks
+// e(signature + gamma*pubkeyG1, -G2) * e(hashToG1(msgHash) + gamma*G1, pubkeyG2) == 1, which
+// holds when the signature is valid for pubkeyG2 and pubkeyG1 is the same key in G1.
+func verifyBN254Signature(
+	msgHash [32]byte,
+	pubkeyG1 *gnarkbn254.G1Affine,
+	pubkeyG2 IBN254CertificateVerifier.BN254G2Point,
+	signature IBN254CertificateVerifier.BN254G1Point,
+) (bool, error) {
+	sig, err := toGnarkG1(signature.X, signature.Y)
+	if err != nil {
+		return false, fmt.Errorf("invalid signature point: %w", err)
+	}
+	apk, err := toGnarkG2(pubkeyG2)
+	if err != nil {
+		return false, fmt.Errorf("invalid apk point: %w", err)
+	}
+	messagePoint, err := bn254.SolidityHashToG1(msgHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to hash digest to G1: %w", err)
+	}
+
+	pubkeyX, pubkeyY := pubkeyG1.X.BigInt(new(big.Int)), pubkeyG1.Y.BigInt(new(big.Int))
+	gamma := new(big.Int).SetBytes(crypto.Keccak256(
+		msgHash[:],
+		math.U256Bytes(pubkeyX),
+		math.U256Bytes(pubkeyY),
+		math.U256Bytes(new(big.Int).Set(pubkeyG2.X[0])),
+		math.U256Bytes(new(big.Int).Set(pubkeyG2.X[1])),
+		math.U256Bytes(new(big.Int).Set(pubkeyG2.Y[0])),
+		math.U256Bytes(new(big.Int).Set(pubkeyG2.Y[1])),
+		math.U256Bytes(new(big.Int).Set(signature.X)),
+		math.U256Bytes(new(big.Int).Set(signature.Y)),
+	))
+	gamma.Mod(gamma, frModulus)
+
+	_, _, g1Gen, g2Gen := gnarkbn254.Generators()
+	var leftG1, rightG1, scaled gnarkbn254.G1Affine
+	scaled.ScalarMultiplication(pubkeyG1, gamma)
+	leftG1.Add(sig, &scaled)
+	scaled.ScalarMultiplication(&g1Gen, gamma)
+	rightG1.Add(messagePoint, &scaled)
+	var negG2 gnarkbn254.G2Affine
+	negG2.Neg(&g2Gen)
+
+	return gnarkbn254.PairingCheck([]gnarkbn254.G1Affine{leftG1, rightG1}, []gnarkbn254.G2Affine{negG2, *apk})
+}
+
+// toGnarkG1 converts contract coordinates to a G1 point, rejecting what the precompiles reject.
+// (0, 0) is the point at infinity.
+func toGnarkG1(x, y *big.Int) (*gnarkbn254.G1Affine, error) {
+	if x == nil || y == nil || !bn254.ValidateFieldOrder(x) || !bn254.ValidateFieldOrder(y) {
+		return nil, bn254.ErrInvalidFieldOrder
+	}
+	p := new(gnarkbn254.G1Affine)
+	p.X.SetBigInt(x)
+	p.Y.SetBigInt(y)
+	if !p.IsInfinity() && !p.IsOnCurve() {
+		return nil, fmt.Errorf("G1 point is not on the curve")
+	}
+	return p, nil
+}
+
+// toGnarkG2 converts contract coordinates, imaginary part first, to a G2 point, rejecting
+// what the pairing precompile rejects.
+func toGnarkG2(p IBN254CertificateVerifier.BN254G2Point) (*gnarkbn254.G2Affine, error) {
+	for _, c := range []*big.Int{p.X[0], p.X[1], p.Y[0], p.Y[1]} {
+		if c == nil || !bn254.ValidateFieldOrder(c) {
+			return nil, bn254.ErrInvalidFieldOrder
+		}
+	}
+	q := new(gnarkbn254.G2Affine)
+	q.X.A1.SetBigInt(p.X[0])
+	q.X.A0.SetBigInt(p.X[1])
+	q.Y.A1.SetBigInt(p.Y[0])
+	q.Y.A0.SetBigInt(p.Y[1])
+	if !q.IsInfinity() && (!q.IsOnCurve() || !q.IsInSubGroup()) {
+		return nil, bn254.ErrPointNotInSubgroup
+	}
+	return q, nil
+}
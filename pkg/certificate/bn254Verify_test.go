@@ -0,0 +1,253 @@
+package certificate
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/Layr-Labs/crypto-libs/pkg/bn254"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IBN254CertificateVerifier"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorInfoTree"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The fixture below reproduces BN254CertificateVerifierUnit.t.sol: signer keys are split so
+// they sum to aggSignerPrivKey = 69, whose G2 key the Solidity test hardcodes.
+const aggSignerPrivKey = 69
+
+func bigFromString(t *testing.T, s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	require.True(t, ok)
+	return n
+}
+
+func solidityAggSignerApkG2(t *testing.T) IBN254CertificateVerifier.BN254G2Point {
+	return IBN254CertificateVerifier.BN254G2Point{
+		X: [2]*big.Int{
+			bigFromString(t, "5334410886741819556325359147377682006012228123419628681352847439302316235957"),
+			bigFromString(t, "19101821850089705274637533855249918363070101489527618151493230256975900223847"),
+		},
+		Y: [2]*big.Int{
+			bigFromString(t, "4185483097059047421902184823581361466320657066600218863748375739772335928910"),
+			bigFromString(t, "354176189041917478648604979334478067325821134838555150300539079146482658331"),
+		},
+	}
+}
+
+func privateKeyFromScalar(t *testing.T, scalar *big.Int) *bn254.PrivateKey {
+	privateKey, err := bn254.NewPrivateKeyFromBytes(math.U256Bytes(new(big.Int).Set(scalar)))
+	require.NoError(t, err)
+	return privateKey
+}
+
+// fixtureKey mirrors uint(keccak256(abi.encodePacked(label, pseudoRandomNumber, i))) % FR_MODULUS.
+func fixtureKey(label string, pseudoRandomNumber, i int64) *big.Int {
+	key := new(big.Int).SetBytes(crypto.Keccak256(
+		[]byte(label),
+		math.U256Bytes(big.NewInt(pseudoRandomNumber)),
+		math.U256Bytes(big.NewInt(i)),
+	))
+	return key.Mod(key, frModulus)
+}
+
+type bn254Fixture struct {
+	signerKeys    []*bn254.PrivateKey
+	operators     []operatorTable.BN254OperatorInfo
+	info          *operatorTable.BN254OperatorSetInfo
+	nonSignerIdxs []uint32
+}
+
+// newBN254Fixture mirrors _createOperatorsWithSplitKeys and _createOperatorSetInfo.
+func newBN254Fixture(t *testing.T, pseudoRandomNumber int64, numSigners, numNonSigners int) *bn254Fixture {
+	f := &bn254Fixture{}
+	var signerScalars []*big.Int
+	sum := new(big.Int)
+	for i := 0; i < numSigners-1; i++ {
+		key := fixtureKey("signerPrivateKey", pseudoRandomNumber, int64(i))
+		signerScalars = append(signerScalars, key)
+		sum.Add(sum, key).Mod(sum, frModulus)
+	}
+	last := new(big.Int).Sub(big.NewInt(aggSignerPrivKey), sum)
+	signerScalars = append(signerScalars, last.Mod(last, frModulus))
+
+	var nonSignerKeys []*bn254.PrivateKey
+	for i := 0; i < numNonSigners; i++ {
+		nonSignerKeys = append(nonSignerKeys, privateKeyFromScalar(t, fixtureKey("nonSignerPrivateKey", pseudoRandomNumber, int64(i))))
+	}
+	pubkeyHash := func(key *bn254.PrivateKey) []byte {
+		g1 := key.Public().GetG1Point()
+		return crypto.Keccak256(math.U256Bytes(g1.X.BigInt(new(big.Int))), math.U256Bytes(g1.Y.BigInt(new(big.Int))))
+	}
+	sort.SliceStable(nonSignerKeys, func(i, j int) bool {
+		return bytes.Compare(pubkeyHash(nonSignerKeys[i]), pubkeyHash(nonSignerKeys[j])) < 0
+	})
+
+	var keys []*bn254.PrivateKey
+	for _, scalar := range signerScalars {
+		f.signerKeys = append(f.signerKeys, privateKeyFromScalar(t, scalar))
+	}
+	keys = append(keys, f.signerKeys...)
+	keys = append(keys, nonSignerKeys...)
+
+	aggregate := bn254.NewZeroG1Point()
+	totalWeights := []*big.Int{new(big.Int), new(big.Int)}
+	for i, key := range keys {
+		g1 := key.Public().GetG1Point()
+		weights := []*big.Int{big.NewInt(int64(100 + i*10)), big.NewInt(int64(200 + i*20))}
+		f.operators = append(f.operators, operatorTable.BN254OperatorInfo{
+			Pubkey:  operatorTable.G1Point{X: g1.X.BigInt(new(big.Int)), Y: g1.Y.BigInt(new(big.Int))},
+			Weights: weights,
+		})
+		aggregate.AddPublicKey(key.Public())
+		totalWeights[0].Add(totalWeights[0], weights[0])
+		totalWeights[1].Add(totalWeights[1], weights[1])
+		if i >= numSigners {
+			f.nonSignerIdxs = append(f.nonSignerIdxs, uint32(i))
+		}
+	}
+	tree, err := operatorInfoTree.New(f.operators)
+	require.NoError(t, err)
+	f.info = &operatorTable.BN254OperatorSetInfo{
+		OperatorInfoTreeRoot: tree.Root(),
+		NumOperators:         big.NewInt(int64(len(keys))),
+		AggregatePubkey:      operatorTable.G1Point{X: aggregate.X.BigInt(new(big.Int)), Y: aggregate.Y.BigInt(new(big.Int))},
+		TotalWeights:         totalWeights,
+	}
+	return f
+}
+
+// certificate mirrors _createCertificate: the signature is hashToG1(digest) * aggSignerPrivKey
+// and the APK is the Solidity test's hardcoded aggSignerApkG2.
+func (f *bn254Fixture) certificate(t *testing.T, referenceTimestamp uint32, messageHash [32]byte) *IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254Certificate {
+	tree, err := operatorInfoTree.New(f.operators)
+	require.NoError(t, err)
+	witnesses := []IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254OperatorInfoWitness{}
+	for _, index := range f.nonSignerIdxs {
+		proof, err := tree.Proof(index)
+		require.NoError(t, err)
+		witnesses = append(witnesses, proof.Witness())
+	}
+	signature, err := privateKeyFromScalar(t, big.NewInt(aggSignerPrivKey)).SignSolidityCompatible(BN254CertificateDigest(referenceTimestamp, messageHash))
+	require.NoError(t, err)
+	signatureG1, err := toBindingG1(&bn254.G1Point{G1Affine: signature.GetG1Point()})
+	require.NoError(t, err)
+	return &IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254Certificate{
+		ReferenceTimestamp: referenceTimestamp,
+		MessageHash:        messageHash,
+		Signature:          signatureG1,
+		Apk:                solidityAggSignerApkG2(t),
+		NonSignerWitnesses: witnesses,
+	}
+}
+
+var solidityDefaultMsgHash = crypto.Keccak256Hash([]byte("test message"))
+
+func TestAggSignerApkG2MatchesSolidity(t *testing.T) {
+	apk, err := toBindingG2(bn254.NewZeroG2Point().AddPublicKey(privateKeyFromScalar(t, big.NewInt(aggSignerPrivKey)).Public()))
+	require.NoError(t, err)
+	assert.Equal(t, solidityAggSignerApkG2(t), apk)
+}
+
+func TestVerifyBN254Certificate(t *testing.T) {
+	f := newBN254Fixture(t, 1, 2, 2)
+	cert := f.certificate(t, testReferenceTimestamp, solidityDefaultMsgHash)
+
+	result, err := VerifyBN254Certificate(f.info, cert)
+	require.NoError(t, err)
+	// Totals are 460 and 920; non-signers 2 and 3 hold 120+130 and 240+260
+	assert.Equal(t, []*big.Int{big.NewInt(210), big.NewInt(420)}, result.SignedWeights)
+	assert.Equal(t, []uint32{2, 3}, result.NonSignerIndices)
+	signerApk := privateKeyFromScalar(t, big.NewInt(aggSignerPrivKey)).Public().GetG1Point()
+	assert.Equal(t, operatorTable.G1Point{X: signerApk.X.BigInt(new(big.Int)), Y: signerApk.Y.BigInt(new(big.Int))}, result.SignerApk)
+
+	// 210/460 is 45.6%, 420/920 is 45.6%
+	ok, err := result.MeetsProportionThresholds([]uint16{4500, 4500})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = result.MeetsProportionThresholds([]uint16{4500, 4600})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, err = result.MeetsProportionThresholds([]uint16{4500})
+	assert.ErrorIs(t, err, ErrArrayLengthMismatch)
+
+	ok, err = result.MeetsNominalThresholds([]*big.Int{big.NewInt(210), big.NewInt(420)})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = result.MeetsNominalThresholds([]*big.Int{big.NewInt(211), big.NewInt(0)})
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestVerifyBN254Certificate_AllSigners(t *testing.T) {
+	f := newBN254Fixture(t, 7, 4, 0)
+
+	result, err := VerifyBN254Certificate(f.info, f.certificate(t, testReferenceTimestamp, solidityDefaultMsgHash))
+	require.NoError(t, err)
+	assert.Equal(t, f.info.TotalWeights, result.SignedWeights)
+	assert.Empty(t, result.NonSignerIndices)
+}
+
+func TestVerifyBN254Certificate_BuiltCertificate(t *testing.T) {
+	f := newBN254Fixture(t, 3, 3, 1)
+	digest := BN254CertificateDigest(testReferenceTimestamp, solidityDefaultMsgHash)
+	var signatures []BN254Signature
+	for _, key := range f.signerKeys {
+		signature, err := key.SignSolidityCompatible(digest)
+		require.NoError(t, err)
+		signatures = append(signatures, BN254Signature{PublicKey: key.Public(), Signature: signature})
+	}
+
+	built, err := BuildBN254Certificate(&BN254CertificateRequest{
+		ReferenceTimestamp: testReferenceTimestamp,
+		MessageHash:        solidityDefaultMsgHash,
+		OperatorSetInfo:    f.info,
+		Operators:          f.operators,
+		Signatures:         signatures,
+	})
+	require.NoError(t, err)
+	// The split keys sum to aggSignerPrivKey, so the builder reproduces the Solidity certificate
+	assert.Equal(t, *f.certificate(t, testReferenceTimestamp, solidityDefaultMsgHash), built.Certificate)
+
+	result, err := VerifyBN254Certificate(f.info, &built.Certificate)
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{3}, result.NonSignerIndices)
+}
+
+func TestVerifyBN254Certificate_Rejects(t *testing.T) {
+	f := newBN254Fixture(t, 1, 2, 2)
+	verify := func(mutate func(cert *IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254Certificate)) error {
+		cert := f.certificate(t, testReferenceTimestamp, solidityDefaultMsgHash)
+		mutate(cert)
+		_, err := VerifyBN254Certificate(f.info, cert)
+		return err
+	}
+
+	assert.ErrorIs(t, verify(func(cert *IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254Certificate) {
+		cert.NonSignerWitnesses[0], cert.NonSignerWitnesses[1] = cert.NonSignerWitnesses[1], cert.NonSignerWitnesses[0]
+	}), ErrNonSignerIndicesNotSorted)
+
+	assert.ErrorIs(t, verify(func(cert *IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254Certificate) {
+		cert.NonSignerWitnesses[1].OperatorIndex = 4
+	}), ErrInvalidOperatorIndex)
+
+	assert.ErrorIs(t, verify(func(cert *IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254Certificate) {
+		cert.NonSignerWitnesses[0].OperatorInfo.Weights = []*big.Int{big.NewInt(1), big.NewInt(1)}
+	}), ErrVerificationFailed, "weights not in the tree")
+
+	assert.ErrorIs(t, verify(func(cert *IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254Certificate) {
+		cert.NonSignerWitnesses = cert.NonSignerWitnesses[:1]
+	}), ErrVerificationFailed, "signers' key no longer matches the APK")
+
+	assert.ErrorIs(t, verify(func(cert *IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254Certificate) {
+		cert.MessageHash = [32]byte{1}
+	}), ErrVerificationFailed, "signature over another digest")
+
+	assert.ErrorIs(t, verify(func(cert *IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254Certificate) {
+		cert.Apk.X[0] = new(big.Int).Add(cert.Apk.X[0], big.NewInt(1))
+	}), ErrVerificationFailed, "APK off the curve")
+}
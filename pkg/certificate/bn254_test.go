@@ -0,0 +1,160 @@
+package certificate
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/crypto-libs/pkg/bn254"
+	"github.com/Layr-Labs/multichain-go/pkg/blsSigner"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorInfoTree"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testReferenceTimestamp = uint32(1700000000)
+
+var testMessageHash = common.HexToHash("0x7a5c")
+
+type bn254TestOperatorSet struct {
+	signers    []*blsSigner.InMemoryBLSSigner
+	publicKeys []*bn254.PublicKey
+	operators  []operatorTable.BN254OperatorInfo
+	info       *operatorTable.BN254OperatorSetInfo
+}
+
+// newBN254TestOperatorSet builds a table of n operators with weights 100, 200, ...
+func newBN254TestOperatorSet(t *testing.T, n int) *bn254TestOperatorSet {
+	set := &bn254TestOperatorSet{}
+	aggregate := bn254.NewZeroG1Point()
+	totalWeight := new(big.Int)
+	for i := 0; i < n; i++ {
+		privateKey, publicKey, err := bn254.GenerateKeyPair()
+		require.NoError(t, err)
+		signer, err := blsSigner.NewInMemoryBLSSigner(privateKey)
+		require.NoError(t, err)
+		weight := big.NewInt(int64(100 * (i + 1)))
+		g1 := publicKey.GetG1Point()
+
+		set.signers = append(set.signers, signer)
+		set.publicKeys = append(set.publicKeys, publicKey)
+		set.operators = append(set.operators, operatorTable.BN254OperatorInfo{
+			Pubkey:  operatorTable.G1Point{X: g1.X.BigInt(new(big.Int)), Y: g1.Y.BigInt(new(big.Int))},
+			Weights: []*big.Int{weight},
+		})
+		aggregate.AddPublicKey(publicKey)
+		totalWeight.Add(totalWeight, weight)
+	}
+	tree, err := operatorInfoTree.New(set.operators)
+	require.NoError(t, err)
+	set.info = &operatorTable.BN254OperatorSetInfo{
+		OperatorInfoTreeRoot: tree.Root(),
+		NumOperators:         big.NewInt(int64(n)),
+		AggregatePubkey:      operatorTable.G1Point{X: aggregate.X.BigInt(new(big.Int)), Y: aggregate.Y.BigInt(new(big.Int))},
+		TotalWeights:         []*big.Int{totalWeight},
+	}
+	return set
+}
+
+func (s *bn254TestOperatorSet) sign(t *testing.T, indices ...int) []BN254Signature {
+	var signatures []BN254Signature
+	for _, i := range indices {
+		sig, err := SignBN254(s.signers[i], testReferenceTimestamp, testMessageHash)
+		require.NoError(t, err)
+		signatures = append(signatures, *sig)
+	}
+	return signatures
+}
+
+func (s *bn254TestOperatorSet) request(signatures []BN254Signature) *BN254CertificateRequest {
+	return &BN254CertificateRequest{
+		ReferenceTimestamp: testReferenceTimestamp,
+		MessageHash:        testMessageHash,
+		OperatorSetInfo:    s.info,
+		Operators:          s.operators,
+		Signatures:         signatures,
+	}
+}
+
+func TestBN254CertificateDigest(t *testing.T) {
+	typeHash := crypto.Keccak256([]byte("BN254Certificate(uint32 referenceTimestamp,bytes32 messageHash)"))
+	expected := crypto.Keccak256Hash(typeHash, math.U256Bytes(big.NewInt(int64(testReferenceTimestamp))), testMessageHash[:])
+	assert.Equal(t, [32]byte(expected), BN254CertificateDigest(testReferenceTimestamp, testMessageHash))
+}
+
+func TestBuildBN254Certificate(t *testing.T) {
+	set := newBN254TestOperatorSet(t, 5)
+	// Signatures may arrive in any order
+	signatures := set.sign(t, 3, 0, 2)
+
+	cert, err := BuildBN254Certificate(set.request(signatures))
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{0, 2, 3}, cert.SignerIndices)
+	assert.Equal(t, []uint32{1, 4}, cert.NonSignerIndices)
+	assert.Equal(t, testReferenceTimestamp, cert.Certificate.ReferenceTimestamp)
+	assert.Equal(t, [32]byte(testMessageHash), cert.Certificate.MessageHash)
+
+	aggregateSignature, err := bn254.AggregateSignatures([]*bn254.Signature{signatures[0].Signature, signatures[1].Signature, signatures[2].Signature})
+	require.NoError(t, err)
+	expectedSignature, err := toBindingG1(&bn254.G1Point{G1Affine: aggregateSignature.GetG1Point()})
+	require.NoError(t, err)
+	assert.Equal(t, expectedSignature, cert.Certificate.Signature)
+
+	aggregateKey, err := bn254.AggregatePublicKeys([]*bn254.PublicKey{set.publicKeys[0], set.publicKeys[2], set.publicKeys[3]})
+	require.NoError(t, err)
+	expectedApk, err := toBindingG2(&bn254.G2Point{G2Affine: aggregateKey.GetG2Point()})
+	require.NoError(t, err)
+	assert.Equal(t, expectedApk, cert.Certificate.Apk)
+	ok, err := aggregateSignature.VerifySolidityCompatible(aggregateKey, BN254CertificateDigest(testReferenceTimestamp, testMessageHash))
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	require.Len(t, cert.Certificate.NonSignerWitnesses, 2)
+	for i, index := range cert.NonSignerIndices {
+		witness := cert.Certificate.NonSignerWitnesses[i]
+		assert.Equal(t, index, witness.OperatorIndex)
+		assert.Equal(t, set.operators[index].Pubkey.X, witness.OperatorInfo.Pubkey.X)
+		assert.Equal(t, set.operators[index].Weights, witness.OperatorInfo.Weights)
+		proof := &operatorInfoTree.OperatorProof{Index: index, OperatorInfo: set.operators[index], Proof: witness.OperatorInfoProof}
+		assert.True(t, proof.Verify(set.info.OperatorInfoTreeRoot), "non-signer %d", index)
+	}
+}
+
+func TestBuildBN254Certificate_AllSigned(t *testing.T) {
+	set := newBN254TestOperatorSet(t, 3)
+
+	cert, err := BuildBN254Certificate(set.request(set.sign(t, 0, 1, 2)))
+	require.NoError(t, err)
+	assert.Empty(t, cert.NonSignerIndices)
+	assert.NotNil(t, cert.Certificate.NonSignerWitnesses, "ABI-encodes as an empty array")
+}
+
+func TestBuildBN254Certificate_Rejects(t *testing.T) {
+	set := newBN254TestOperatorSet(t, 3)
+	other := newBN254TestOperatorSet(t, 1)
+
+	_, err := BuildBN254Certificate(set.request(nil))
+	assert.ErrorIs(t, err, ErrNoSigners)
+
+	_, err = BuildBN254Certificate(set.request(other.sign(t, 0)))
+	assert.ErrorIs(t, err, ErrUnknownSigner)
+
+	_, err = BuildBN254Certificate(set.request(set.sign(t, 1, 1)))
+	assert.ErrorIs(t, err, ErrDuplicateSigner)
+
+	// Signed for a different reference timestamp
+	wrongDigest, err := SignBN254(set.signers[2], testReferenceTimestamp+1, testMessageHash)
+	require.NoError(t, err)
+	_, err = BuildBN254Certificate(set.request([]BN254Signature{*wrongDigest}))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	assert.ErrorContains(t, err, "operator 2")
+
+	// Operator infos that do not make up the table
+	req := set.request(set.sign(t, 0))
+	req.Operators = []operatorTable.BN254OperatorInfo{set.operators[1], set.operators[0], set.operators[2]}
+	_, err = BuildBN254Certificate(req)
+	assert.ErrorIs(t, err, operatorInfoTree.ErrRootMismatch)
+}
@@ -0,0 +1,75 @@
+// Package certificate builds certificates that the destination chain's certificate verifiers
+// accept for AVS task responses, from the operator tables this library calculates and
+// transports.
+//
+// A BN254 certificate carries the aggregate signature of the signing operators, their
+// aggregate G2 public key, and a witness with an operator info tree proof for every operator
+// that did not sign. The verifier subtracts the non-signers from the table's aggregate G1 key
+// and total weights, so the certificate must be built against the exact operator table stored
+// for its reference timestamp.
+//
+// An ECDSA certificate is the concatenation of the signing operators' signatures over an
+// EIP-712 digest, ordered by signer address. The verifier sums the signers' weights from the
+// operator table stored for the reference timestamp.
+package certificate
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// BPS_DENOMINATOR is the denominator of proportional stake thresholds, which are in basis points.
+const BPS_DENOMINATOR = 10000
+
+var (
+	// ErrNoSigners is returned when a certificate is built without any signatures
+	ErrNoSigners = errors.New("certificate has no signers")
+	// ErrUnknownSigner is returned for a signature whose key is not in the operator table
+	ErrUnknownSigner = errors.New("signer is not in the operator table")
+	// ErrDuplicateSigner is returned when an operator signs more than once
+	ErrDuplicateSigner = errors.New("duplicate signer")
+	// ErrInvalidSignature is returned for a signature that does not verify against its key
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrNonSignerIndicesNotSorted mirrors the verifier's NonSignerIndicesNotSorted revert
+	ErrNonSignerIndicesNotSorted = errors.New("non-signer indices not sorted")
+	// ErrInvalidOperatorIndex mirrors the verifier's InvalidOperatorIndex revert
+	ErrInvalidOperatorIndex = errors.New("invalid operator index")
+	// ErrVerificationFailed mirrors the verifier's VerificationFailed revert, for a bad
+	// non-signer proof or signature
+	ErrVerificationFailed = errors.New("certificate verification failed")
+	// ErrArrayLengthMismatch mirrors the verifier's ArrayLengthMismatch revert for thresholds
+	// that do not cover every weight type
+	ErrArrayLengthMismatch = errors.New("array length mismatch")
+)
+
+// meetsProportionThresholds mirrors the verifiers' verifyCertificateProportion: each weight
+// type's signed weight must reach totalWeight * threshold / BPS_DENOMINATOR.
+func meetsProportionThresholds(signedWeights, totalWeights []*big.Int, thresholds []uint16) (bool, error) {
+	if len(thresholds) != len(signedWeights) {
+		return false, fmt.Errorf("%w: %d thresholds for %d weight types", ErrArrayLengthMismatch, len(thresholds), len(signedWeights))
+	}
+	for i, signed := range signedWeights {
+		threshold := new(big.Int).Mul(totalWeights[i], big.NewInt(int64(thresholds[i])))
+		threshold.Div(threshold, big.NewInt(BPS_DENOMINATOR))
+		if signed.Cmp(threshold) < 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// meetsNominalThresholds mirrors the verifiers' verifyCertificateNominal: each weight type's
+// signed weight must reach its threshold.
+func meetsNominalThresholds(signedWeights, thresholds []*big.Int) (bool, error) {
+	if len(thresholds) != len(signedWeights) {
+		return false, fmt.Errorf("%w: %d thresholds for %d weight types", ErrArrayLengthMismatch, len(thresholds), len(signedWeights))
+	}
+	for i, signed := range signedWeights {
+		if signed.Cmp(thresholds[i]) < 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
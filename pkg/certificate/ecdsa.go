@@ -0,0 +1,436 @@
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IECDSACertificateVerifier"
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/txSigner"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ECDSASignatureLength is the length of one signature in an ECDSA certificate: r, s and v.
+const ECDSASignatureLength = 65
+
+var (
+	// ECDSA_CERTIFICATE_TYPEHASH is the type hash of ECDSA certificate digests, matching
+	// ECDSACertificateVerifierStorage.ECDSA_CERTIFICATE_TYPEHASH
+	ECDSA_CERTIFICATE_TYPEHASH = crypto.Keccak256Hash([]byte("ECDSACertificate(uint32 referenceTimestamp,bytes32 messageHash)"))
+	// EIP712_DOMAIN_TYPEHASH_NO_CHAINID is the ECDSACertificateVerifier's EIP-712 domain type
+	// hash, which leaves out the chain ID so certificates are valid on every destination chain
+	EIP712_DOMAIN_TYPEHASH_NO_CHAINID = crypto.Keccak256Hash([]byte("EIP712Domain(string name,string version,address verifyingContract)"))
+)
+
+var (
+	// ErrNotECDSATable is returned when table bytes are not an ECDSA operator table
+	ErrNotECDSATable = errors.New("operator table is not an ECDSA table")
+	// ErrInvalidSignatureLength mirrors the ECDSA verifier's InvalidSignatureLength revert
+	ErrInvalidSignatureLength = errors.New("invalid signature length")
+	// ErrSignersNotOrdered mirrors the ECDSA verifier's SignersNotOrdered revert
+	ErrSignersNotOrdered = errors.New("signers not ordered")
+	// ErrOperatorCountZero mirrors the ECDSA verifier's OperatorCountZero revert
+	ErrOperatorCountZero = errors.New("operator count zero")
+)
+
+var secp256k1N = crypto.S256().Params().N
+var secp256k1HalfN = new(big.Int).Div(secp256k1N, big.NewInt(2))
+
+// ECDSADomain is the EIP-712 domain of an ECDSACertificateVerifier. Its digests do not
+// depend on the chain, but do depend on the verifier's address and major version.
+type ECDSADomain struct {
+	// Verifier is the ECDSACertificateVerifier's address, the same on every destination chain
+	Verifier common.Address
+	// Version is the verifier's version(), such as "1.0.0"
+	Version string
+}
+
+// Separator returns the domain separator, matching ECDSACertificateVerifier.domainSeparator.
+// Like the contract's _majorVersion, only the first character of the version is used.
+func (d ECDSADomain) Separator() common.Hash {
+	majorVersion := d.Version
+	if len(majorVersion) > 1 {
+		majorVersion = majorVersion[:1]
+	}
+	encoded, err := ecdsaDomainArgs.Pack(
+		EIP712_DOMAIN_TYPEHASH_NO_CHAINID,
+		crypto.Keccak256Hash([]byte("EigenLayer")),
+		crypto.Keccak256Hash([]byte(majorVersion)),
+		d.Verifier,
+	)
+	if err != nil {
+		// Static types always pack
+		panic(fmt.Sprintf("failed to pack domain separator: %v", err))
+	}
+	return crypto.Keccak256Hash(encoded)
+}
+
+var ecdsaDomainArgs = abi.Arguments{
+	{Type: mustNewType("bytes32")},
+	{Type: mustNewType("bytes32")},
+	{Type: mustNewType("bytes32")},
+	{Type: mustNewType("address")},
+}
+
+// ECDSACertificateDigest returns the digest operators sign for an ECDSA certificate, matching
+// ECDSACertificateVerifier.calculateCertificateDigest.
+//
+// Parameters:
+//   - domain: The verifier's EIP-712 domain
+//   - referenceTimestamp: The reference timestamp of the operator table the certificate is for
+//   - messageHash: The hash of the task response
+//
+// Returns:
+//   - [32]byte: keccak256("\x19\x01" || domainSeparator || keccak256(abi.encode(ECDSA_CERTIFICATE_TYPEHASH, referenceTimestamp, messageHash)))
+func ECDSACertificateDigest(domain ECDSADomain, referenceTimestamp uint32, messageHash [32]byte) [32]byte {
+	encoded, err := certificateDigestArgs.Pack(ECDSA_CERTIFICATE_TYPEHASH, referenceTimestamp, messageHash)
+	if err != nil {
+		// Static types always pack
+		panic(fmt.Sprintf("failed to pack certificate digest: %v", err))
+	}
+	separator := domain.Separator()
+	return crypto.Keccak256Hash([]byte("\x19\x01"), separator[:], crypto.Keccak256(encoded))
+}
+
+// DecodeECDSAOperatorTable decodes the operators of an ECDSA operator table.
+//
+// Parameters:
+//   - tableBytes: The operator table bytes, as CrossChainRegistry.calculateOperatorTableBytes returns them
+//
+// Returns:
+//   - []operatorTable.ECDSAOperatorInfo: The operators in table order
+//   - error: An error wrapping ErrNotECDSATable for other curve types, or an error if the
+//     bytes cannot be decoded
+func DecodeECDSAOperatorTable(tableBytes []byte) ([]operatorTable.ECDSAOperatorInfo, error) {
+	table, err := operatorTable.Decode(tableBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode operator table: %w", err)
+	}
+	if table.CurveType != operatorTable.CurveTypeECDSA {
+		return nil, fmt.Errorf("%w: curve type %s", ErrNotECDSATable, table.CurveType)
+	}
+	return table.ECDSA, nil
+}
+
+// ECDSAOperatorTableFromDistribution decodes the operators of an operator set's ECDSA table
+// in a distribution.
+//
+// Parameters:
+//   - dist: The distribution the operator tables were transported from
+//   - opset: The operator set
+//
+// Returns:
+//   - []operatorTable.ECDSAOperatorInfo: The operators in table order
+//   - error: An error if the distribution has no table for the operator set or it is not an ECDSA table
+func ECDSAOperatorTableFromDistribution(dist *distribution.Distribution, opset distribution.OperatorSet) ([]operatorTable.ECDSAOperatorInfo, error) {
+	tableBytes, ok := dist.GetTableData(opset)
+	if !ok {
+		return nil, fmt.Errorf("no table data for operator set %s/%d", opset.Avs.Hex(), opset.Id)
+	}
+	return DecodeECDSAOperatorTable(tableBytes)
+}
+
+// ECDSASignature is one operator's signature over an ECDSA certificate digest.
+type ECDSASignature struct {
+	// Signer is the operator's ECDSA signing key address, as registered in the KeyRegistrar
+	Signer common.Address
+	// Signature is the 65-byte [R || S || V] signature, with V 0, 1, 27 or 28
+	Signature []byte
+}
+
+// SignECDSA signs an ECDSA certificate digest with an operator's signer, such as a
+// txSigner.PrivateKeySigner or txSigner.AWSKMSSigner.
+//
+// Parameters:
+//   - ctx: Context for the signer
+//   - signer: The operator's hash signer
+//   - domain: The verifier's EIP-712 domain
+//   - referenceTimestamp: The reference timestamp of the operator table the certificate is for
+//   - messageHash: The hash of the task response
+//
+// Returns:
+//   - *ECDSASignature: The operator's address and signature
+//   - error: An error if the signer fails
+func SignECDSA(ctx context.Context, signer txSigner.IHashSigner, domain ECDSADomain, referenceTimestamp uint32, messageHash [32]byte) (*ECDSASignature, error) {
+	address, err := signer.GetAddress()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signer address: %w", err)
+	}
+	signature, err := signer.SignHash(ctx, ECDSACertificateDigest(domain, referenceTimestamp, messageHash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign certificate digest: %w", err)
+	}
+	return &ECDSASignature{Signer: address, Signature: signature}, nil
+}
+
+// ECDSACertificateRequest holds what is needed to build an ECDSA certificate.
+type ECDSACertificateRequest struct {
+	// Domain is the verifier's EIP-712 domain
+	Domain ECDSADomain
+	// ReferenceTimestamp is the reference timestamp of the operator table on the destination chain
+	ReferenceTimestamp uint32
+	// MessageHash is the hash of the task response
+	MessageHash [32]byte
+	// Operators are the operators of the table at the reference timestamp
+	Operators []operatorTable.ECDSAOperatorInfo
+	// Signatures are the signing operators' signatures, in any order
+	Signatures []ECDSASignature
+}
+
+// ECDSACertificate is a certificate for the ECDSACertificateVerifier along with its signers
+// and the weight they signed with.
+type ECDSACertificate struct {
+	// Certificate is the certificate to pass to verifyCertificate
+	Certificate IECDSACertificateVerifier.IECDSACertificateVerifierTypesECDSACertificate
+	// Signers are the signers' addresses in certificate order, ascending
+	Signers []common.Address
+	// SignedWeights is the signers' total weight of each weight type
+	SignedWeights []*big.Int
+}
+
+// BuildECDSACertificate builds an ECDSA certificate from operator signatures. Each signature
+// is normalized to the form OpenZeppelin's ECDSA.tryRecover accepts, with V 27 or 28 and a
+// low S, checked to recover to its signer, and the signatures are concatenated in ascending
+// signer order as the verifier requires.
+//
+// Parameters:
+//   - req: The verifier domain, reference timestamp, message hash, operators and signatures
+//
+// Returns:
+//   - *ECDSACertificate: The certificate, its signers and their signed weights
+//   - error: An error wrapping ErrNoSigners, ErrUnknownSigner, ErrDuplicateSigner or
+//     ErrInvalidSignature for bad signatures, or ErrOperatorCountZero for an empty table
+func BuildECDSACertificate(req *ECDSACertificateRequest) (*ECDSACertificate, error) {
+	if len(req.Signatures) == 0 {
+		return nil, ErrNoSigners
+	}
+	if len(req.Operators) == 0 {
+		return nil, ErrOperatorCountZero
+	}
+	digest := ECDSACertificateDigest(req.Domain, req.ReferenceTimestamp, req.MessageHash)
+
+	type signed struct {
+		signer    common.Address
+		signature []byte
+	}
+	seen := make(map[common.Address]bool, len(req.Signatures))
+	signatures := make([]signed, 0, len(req.Signatures))
+	for _, sig := range req.Signatures {
+		if findECDSAOperator(req.Operators, sig.Signer) < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownSigner, sig.Signer.Hex())
+		}
+		if seen[sig.Signer] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateSigner, sig.Signer.Hex())
+		}
+		normalized, err := normalizeECDSASignature(sig.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidSignature, sig.Signer.Hex(), err)
+		}
+		recovered, err := recoverECDSASigner(digest, normalized)
+		if err != nil || recovered != sig.Signer {
+			return nil, fmt.Errorf("%w: %s: signature does not recover to the signer", ErrInvalidSignature, sig.Signer.Hex())
+		}
+		seen[sig.Signer] = true
+		signatures = append(signatures, signed{signer: sig.Signer, signature: normalized})
+	}
+	sort.Slice(signatures, func(i, j int) bool {
+		return bytes.Compare(signatures[i].signer[:], signatures[j].signer[:]) < 0
+	})
+
+	result := &ECDSACertificate{
+		Certificate: IECDSACertificateVerifier.IECDSACertificateVerifierTypesECDSACertificate{
+			ReferenceTimestamp: req.ReferenceTimestamp,
+			MessageHash:        req.MessageHash,
+			Sig:                make([]byte, 0, len(signatures)*ECDSASignatureLength),
+		},
+		Signers: make([]common.Address, 0, len(signatures)),
+	}
+	for _, s := range signatures {
+		result.Certificate.Sig = append(result.Certificate.Sig, s.signature...)
+		result.Signers = append(result.Signers, s.signer)
+	}
+	result.SignedWeights = ecdsaSignedWeights(req.Operators, result.Signers)
+	return result, nil
+}
+
+// ECDSAVerificationResult is the outcome of verifying an ECDSA certificate against a table.
+type ECDSAVerificationResult struct {
+	// SignedWeights is the signers' total weight of each weight type, as verifyCertificate returns it
+	SignedWeights []*big.Int
+	// TotalWeights is the table's total weight of each weight type, as getTotalStakeWeights returns it
+	TotalWeights []*big.Int
+	// Signers are the recovered signers, in certificate order
+	Signers []common.Address
+}
+
+// VerifyECDSACertificate verifies an ECDSA certificate against the operator table stored for
+// its reference timestamp, with the same checks as ECDSACertificateVerifier._verifyECDSACertificate:
+// the signatures must be whole 65-byte signatures that recover without error, in strictly
+// ascending signer order, and every signer must be an operator in the table.
+//
+// Staleness, whether the reference timestamp's table was transported and whether its root is
+// disabled depend on destination chain state and are not checked.
+//
+// Parameters:
+//   - domain: The verifier's EIP-712 domain
+//   - operators: The operators of the table at the certificate's reference timestamp
+//   - cert: The certificate
+//
+// Returns:
+//   - *ECDSAVerificationResult: The signers and their signed weights
+//   - error: An error wrapping ErrOperatorCountZero, ErrInvalidSignatureLength,
+//     ErrInvalidSignature, ErrSignersNotOrdered or ErrVerificationFailed where the contract
+//     would revert
+func VerifyECDSACertificate(
+	domain ECDSADomain,
+	operators []operatorTable.ECDSAOperatorInfo,
+	cert *IECDSACertificateVerifier.IECDSACertificateVerifierTypesECDSACertificate,
+) (*ECDSAVerificationResult, error) {
+	if len(operators) == 0 {
+		return nil, ErrOperatorCountZero
+	}
+	if len(cert.Sig) == 0 || len(cert.Sig)%ECDSASignatureLength != 0 {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidSignatureLength, len(cert.Sig))
+	}
+	digest := ECDSACertificateDigest(domain, cert.ReferenceTimestamp, cert.MessageHash)
+
+	result := &ECDSAVerificationResult{
+		TotalWeights: ecdsaTotalWeights(operators),
+		Signers:      make([]common.Address, 0, len(cert.Sig)/ECDSASignatureLength),
+	}
+	for i := 0; i < len(cert.Sig); i += ECDSASignatureLength {
+		recovered, err := recoverECDSASigner(digest, cert.Sig[i:i+ECDSASignatureLength])
+		if err != nil {
+			return nil, fmt.Errorf("%w: signature %d: %v", ErrInvalidSignature, i/ECDSASignatureLength, err)
+		}
+		if n := len(result.Signers); n > 0 && bytes.Compare(recovered[:], result.Signers[n-1][:]) <= 0 {
+			return nil, fmt.Errorf("%w: %s follows %s", ErrSignersNotOrdered, recovered.Hex(), result.Signers[n-1].Hex())
+		}
+		if findECDSAOperator(operators, recovered) < 0 {
+			return nil, fmt.Errorf("%w: signer %s is not an operator", ErrVerificationFailed, recovered.Hex())
+		}
+		result.Signers = append(result.Signers, recovered)
+	}
+	result.SignedWeights = ecdsaSignedWeights(operators, result.Signers)
+	return result, nil
+}
+
+// MeetsProportionThresholds reports whether each weight type's signed weight reaches its
+// threshold, as verifyCertificateProportion does.
+//
+// Parameters:
+//   - thresholds: The minimum signed proportion of each weight type's total, in basis points
+//
+// Returns:
+//   - bool: Whether every threshold is met
+//   - error: An error wrapping ErrArrayLengthMismatch if there is not one threshold per weight type
+func (r *ECDSAVerificationResult) MeetsProportionThresholds(thresholds []uint16) (bool, error) {
+	return meetsProportionThresholds(r.SignedWeights, r.TotalWeights, thresholds)
+}
+
+// MeetsNominalThresholds reports whether each weight type's signed weight reaches its
+// threshold, as verifyCertificateNominal does.
+//
+// Parameters:
+//   - thresholds: The minimum signed weight of each weight type
+//
+// Returns:
+//   - bool: Whether every threshold is met
+//   - error: An error wrapping ErrArrayLengthMismatch if there is not one threshold per weight type
+func (r *ECDSAVerificationResult) MeetsNominalThresholds(thresholds []*big.Int) (bool, error) {
+	return meetsNominalThresholds(r.SignedWeights, thresholds)
+}
+
+// findECDSAOperator returns the index of the first operator with the given signing key, or -1.
+func findECDSAOperator(operators []operatorTable.ECDSAOperatorInfo, signer common.Address) int {
+	for i, operator := range operators {
+		if operator.Pubkey == signer {
+			return i
+		}
+	}
+	return -1
+}
+
+// ecdsaTotalWeights mirrors getTotalStakeWeights: the number of weight types is taken from
+// the first operator, and longer weight lists are truncated to it.
+func ecdsaTotalWeights(operators []operatorTable.ECDSAOperatorInfo) []*big.Int {
+	totals := make([]*big.Int, len(operators[0].Weights))
+	for i := range totals {
+		totals[i] = new(big.Int)
+	}
+	for _, operator := range operators {
+		for j := 0; j < len(operator.Weights) && j < len(totals); j++ {
+			totals[j].Add(totals[j], operator.Weights[j])
+		}
+	}
+	return totals
+}
+
+// ecdsaSignedWeights mirrors _processSigners, summing the weights of each signer's first
+// matching operator.
+func ecdsaSignedWeights(operators []operatorTable.ECDSAOperatorInfo, signers []common.Address) []*big.Int {
+	signedWeights := make([]*big.Int, len(operators[0].Weights))
+	for i := range signedWeights {
+		signedWeights[i] = new(big.Int)
+	}
+	for _, signer := range signers {
+		operator := operators[findECDSAOperator(operators, signer)]
+		for j := 0; j < len(operator.Weights) && j < len(signedWeights); j++ {
+			signedWeights[j].Add(signedWeights[j], operator.Weights[j])
+		}
+	}
+	return signedWeights
+}
+
+// normalizeECDSASignature converts a 65-byte signature to the form ECDSA.tryRecover accepts:
+// V of 27 or 28 and S in the lower half of the curve order, flipping V when S is flipped.
+func normalizeECDSASignature(signature []byte) ([]byte, error) {
+	if len(signature) != ECDSASignatureLength {
+		return nil, fmt.Errorf("signature is %d bytes, expected %d", len(signature), ECDSASignatureLength)
+	}
+	normalized := bytes.Clone(signature)
+	v := normalized[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return nil, fmt.Errorf("invalid recovery id %d", normalized[64])
+	}
+	s := new(big.Int).SetBytes(normalized[32:64])
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s.Sub(secp256k1N, s)
+		s.FillBytes(normalized[32:64])
+		v ^= 1
+	}
+	normalized[64] = v + 27
+	return normalized, nil
+}
+
+// recoverECDSASigner mirrors ECDSA.tryRecover: V must be 27 or 28 and S must be in the lower
+// half of the curve order.
+func recoverECDSASigner(digest [32]byte, signature []byte) (common.Address, error) {
+	if len(signature) != ECDSASignatureLength {
+		return common.Address{}, fmt.Errorf("signature is %d bytes, expected %d", len(signature), ECDSASignatureLength)
+	}
+	if new(big.Int).SetBytes(signature[32:64]).Cmp(secp256k1HalfN) > 0 {
+		return common.Address{}, fmt.Errorf("signature s is in the upper half of the curve order")
+	}
+	v := signature[64]
+	if v != 27 && v != 28 {
+		return common.Address{}, fmt.Errorf("invalid signature v %d", v)
+	}
+	sig := bytes.Clone(signature)
+	sig[64] = v - 27
+	pubkey, err := crypto.SigToPub(digest[:], sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
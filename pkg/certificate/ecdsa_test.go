@@ -0,0 +1,284 @@
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IECDSACertificateVerifier"
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/txSigner"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testECDSADomain = ECDSADomain{
+	Verifier: common.HexToAddress("0x00000000000000000000000000000000000ec0a5"),
+	Version:  "1.0.0",
+}
+
+type ecdsaTestOperatorSet struct {
+	signers   []*txSigner.PrivateKeySigner
+	operators []operatorTable.ECDSAOperatorInfo
+}
+
+// newECDSATestOperatorSet builds a table of n operators with weights (100, 1000), (200, 2000), ...
+func newECDSATestOperatorSet(t *testing.T, n int) *ecdsaTestOperatorSet {
+	set := &ecdsaTestOperatorSet{}
+	for i := 0; i < n; i++ {
+		privateKey := crypto.Keccak256([]byte(fmt.Sprintf("ecdsa operator %d", i)))
+		signer, err := txSigner.NewPrivateKeySigner(common.Bytes2Hex(privateKey))
+		require.NoError(t, err)
+		address, err := signer.GetAddress()
+		require.NoError(t, err)
+
+		set.signers = append(set.signers, signer)
+		set.operators = append(set.operators, operatorTable.ECDSAOperatorInfo{
+			Pubkey:  address,
+			Weights: []*big.Int{big.NewInt(int64(100 * (i + 1))), big.NewInt(int64(1000 * (i + 1)))},
+		})
+	}
+	return set
+}
+
+func (s *ecdsaTestOperatorSet) sign(t *testing.T, indices ...int) []ECDSASignature {
+	signatures := make([]ECDSASignature, 0, len(indices))
+	for _, i := range indices {
+		sig, err := SignECDSA(context.Background(), s.signers[i], testECDSADomain, testReferenceTimestamp, testMessageHash)
+		require.NoError(t, err)
+		signatures = append(signatures, *sig)
+	}
+	return signatures
+}
+
+func TestECDSACertificateDigest(t *testing.T) {
+	// Encoded by hand as EIP712._hashTypedDataV4 over the chain-less domain does
+	separator := crypto.Keccak256(
+		crypto.Keccak256([]byte("EIP712Domain(string name,string version,address verifyingContract)")),
+		crypto.Keccak256([]byte("EigenLayer")),
+		crypto.Keccak256([]byte("1")),
+		common.LeftPadBytes(testECDSADomain.Verifier[:], 32),
+	)
+	structHash := crypto.Keccak256(
+		crypto.Keccak256([]byte("ECDSACertificate(uint32 referenceTimestamp,bytes32 messageHash)")),
+		math.U256Bytes(big.NewInt(int64(testReferenceTimestamp))),
+		testMessageHash[:],
+	)
+	expected := crypto.Keccak256Hash([]byte{0x19, 0x01}, separator, structHash)
+
+	assert.Equal(t, common.BytesToHash(separator), testECDSADomain.Separator())
+	assert.Equal(t, [32]byte(expected), ECDSACertificateDigest(testECDSADomain, testReferenceTimestamp, testMessageHash))
+
+	// Only the major version is part of the domain
+	assert.Equal(t, testECDSADomain.Separator(), ECDSADomain{Verifier: testECDSADomain.Verifier, Version: "1.2.3"}.Separator())
+	assert.NotEqual(t, testECDSADomain.Separator(), ECDSADomain{Verifier: testECDSADomain.Verifier, Version: "2.0.0"}.Separator())
+	assert.NotEqual(t, expected, common.Hash(ECDSACertificateDigest(testECDSADomain, testReferenceTimestamp+1, testMessageHash)))
+}
+
+func TestBuildECDSACertificate(t *testing.T) {
+	set := newECDSATestOperatorSet(t, 5)
+
+	cert, err := BuildECDSACertificate(&ECDSACertificateRequest{
+		Domain:             testECDSADomain,
+		ReferenceTimestamp: testReferenceTimestamp,
+		MessageHash:        testMessageHash,
+		Operators:          set.operators,
+		Signatures:         set.sign(t, 4, 0, 2, 3),
+	})
+	require.NoError(t, err)
+
+	expectedSigners := []common.Address{set.operators[0].Pubkey, set.operators[2].Pubkey, set.operators[3].Pubkey, set.operators[4].Pubkey}
+	sort.Slice(expectedSigners, func(i, j int) bool {
+		return bytes.Compare(expectedSigners[i][:], expectedSigners[j][:]) < 0
+	})
+	assert.Equal(t, expectedSigners, cert.Signers)
+	assert.Equal(t, testReferenceTimestamp, cert.Certificate.ReferenceTimestamp)
+	assert.Equal(t, [32]byte(testMessageHash), cert.Certificate.MessageHash)
+	assert.Len(t, cert.Certificate.Sig, 4*ECDSASignatureLength)
+	assert.Equal(t, []*big.Int{big.NewInt(1300), big.NewInt(13000)}, cert.SignedWeights)
+
+	result, err := VerifyECDSACertificate(testECDSADomain, set.operators, &cert.Certificate)
+	require.NoError(t, err)
+	assert.Equal(t, cert.Signers, result.Signers)
+	assert.Equal(t, cert.SignedWeights, result.SignedWeights)
+	assert.Equal(t, []*big.Int{big.NewInt(1500), big.NewInt(15000)}, result.TotalWeights)
+
+	meets, err := result.MeetsProportionThresholds([]uint16{8666, 8666})
+	require.NoError(t, err)
+	assert.True(t, meets)
+	meets, err = result.MeetsProportionThresholds([]uint16{8700, 0})
+	require.NoError(t, err)
+	assert.False(t, meets)
+	meets, err = result.MeetsNominalThresholds([]*big.Int{big.NewInt(1300), big.NewInt(13001)})
+	require.NoError(t, err)
+	assert.False(t, meets)
+	_, err = result.MeetsNominalThresholds([]*big.Int{big.NewInt(1)})
+	assert.ErrorIs(t, err, ErrArrayLengthMismatch)
+
+	// The certificate is for one domain only
+	_, err = VerifyECDSACertificate(ECDSADomain{Verifier: common.HexToAddress("0x01"), Version: "1.0.0"}, set.operators, &cert.Certificate)
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+}
+
+func TestBuildECDSACertificate_NormalizesSignatures(t *testing.T) {
+	set := newECDSATestOperatorSet(t, 2)
+	signatures := set.sign(t, 0, 1)
+
+	// V of 27/28 is accepted as is
+	signatures[0].Signature[64] += 27
+	// A high-s signature is flipped to its low-s form
+	s := new(big.Int).SetBytes(signatures[1].Signature[32:64])
+	new(big.Int).Sub(secp256k1N, s).FillBytes(signatures[1].Signature[32:64])
+	signatures[1].Signature[64] ^= 1
+
+	cert, err := BuildECDSACertificate(&ECDSACertificateRequest{
+		Domain:             testECDSADomain,
+		ReferenceTimestamp: testReferenceTimestamp,
+		MessageHash:        testMessageHash,
+		Operators:          set.operators,
+		Signatures:         signatures,
+	})
+	require.NoError(t, err)
+	for i := 0; i < len(cert.Certificate.Sig); i += ECDSASignatureLength {
+		sig := cert.Certificate.Sig[i : i+ECDSASignatureLength]
+		assert.Contains(t, []byte{27, 28}, sig[64])
+		assert.LessOrEqual(t, new(big.Int).SetBytes(sig[32:64]).Cmp(secp256k1HalfN), 0)
+	}
+	_, err = VerifyECDSACertificate(testECDSADomain, set.operators, &cert.Certificate)
+	require.NoError(t, err)
+}
+
+func TestBuildECDSACertificate_Rejects(t *testing.T) {
+	set := newECDSATestOperatorSet(t, 3)
+	outsider := newECDSATestOperatorSet(t, 4)
+
+	request := func(signatures []ECDSASignature) *ECDSACertificateRequest {
+		return &ECDSACertificateRequest{
+			Domain:             testECDSADomain,
+			ReferenceTimestamp: testReferenceTimestamp,
+			MessageHash:        testMessageHash,
+			Operators:          set.operators,
+			Signatures:         signatures,
+		}
+	}
+
+	_, err := BuildECDSACertificate(request(nil))
+	assert.ErrorIs(t, err, ErrNoSigners)
+
+	_, err = BuildECDSACertificate(request(outsider.sign(t, 3)))
+	assert.ErrorIs(t, err, ErrUnknownSigner)
+
+	_, err = BuildECDSACertificate(request(set.sign(t, 1, 1)))
+	assert.ErrorIs(t, err, ErrDuplicateSigner)
+
+	// A signature by another key, claimed for an operator
+	forged := set.sign(t, 0)
+	forged[0].Signature = set.sign(t, 1)[0].Signature
+	_, err = BuildECDSACertificate(request(forged))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// A signature over another message
+	wrongMessage, err := SignECDSA(context.Background(), set.signers[0], testECDSADomain, testReferenceTimestamp+1, testMessageHash)
+	require.NoError(t, err)
+	_, err = BuildECDSACertificate(request([]ECDSASignature{*wrongMessage}))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	truncated := set.sign(t, 0)
+	truncated[0].Signature = truncated[0].Signature[:64]
+	_, err = BuildECDSACertificate(request(truncated))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	badV := set.sign(t, 0)
+	badV[0].Signature[64] = 29
+	_, err = BuildECDSACertificate(request(badV))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = BuildECDSACertificate(&ECDSACertificateRequest{Domain: testECDSADomain, Signatures: set.sign(t, 0)})
+	assert.ErrorIs(t, err, ErrOperatorCountZero)
+}
+
+func TestVerifyECDSACertificate_Rejects(t *testing.T) {
+	set := newECDSATestOperatorSet(t, 3)
+	cert, err := BuildECDSACertificate(&ECDSACertificateRequest{
+		Domain:             testECDSADomain,
+		ReferenceTimestamp: testReferenceTimestamp,
+		MessageHash:        testMessageHash,
+		Operators:          set.operators,
+		Signatures:         set.sign(t, 0, 1, 2),
+	})
+	require.NoError(t, err)
+
+	withSig := func(sig []byte) *IECDSACertificateVerifier.IECDSACertificateVerifierTypesECDSACertificate {
+		c := cert.Certificate
+		c.Sig = sig
+		return &c
+	}
+	first := cert.Certificate.Sig[:ECDSASignatureLength]
+	second := cert.Certificate.Sig[ECDSASignatureLength : 2*ECDSASignatureLength]
+
+	_, err = VerifyECDSACertificate(testECDSADomain, nil, &cert.Certificate)
+	assert.ErrorIs(t, err, ErrOperatorCountZero)
+
+	_, err = VerifyECDSACertificate(testECDSADomain, set.operators, withSig(nil))
+	assert.ErrorIs(t, err, ErrInvalidSignatureLength)
+	_, err = VerifyECDSACertificate(testECDSADomain, set.operators, withSig(cert.Certificate.Sig[:100]))
+	assert.ErrorIs(t, err, ErrInvalidSignatureLength)
+
+	_, err = VerifyECDSACertificate(testECDSADomain, set.operators, withSig(append(bytes.Clone(second), first...)))
+	assert.ErrorIs(t, err, ErrSignersNotOrdered)
+	_, err = VerifyECDSACertificate(testECDSADomain, set.operators, withSig(append(bytes.Clone(first), first...)))
+	assert.ErrorIs(t, err, ErrSignersNotOrdered)
+
+	// The contract only accepts V of 27 or 28 and low S
+	rawV := bytes.Clone(first)
+	rawV[64] -= 27
+	_, err = VerifyECDSACertificate(testECDSADomain, set.operators, withSig(rawV))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	highS := bytes.Clone(first)
+	new(big.Int).Sub(secp256k1N, new(big.Int).SetBytes(highS[32:64])).FillBytes(highS[32:64])
+	highS[64] ^= 1
+	_, err = VerifyECDSACertificate(testECDSADomain, set.operators, withSig(highS))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// A valid signature by a key that is not in the table
+	outsider := newECDSATestOperatorSet(t, 4)
+	_, err = VerifyECDSACertificate(testECDSADomain, outsider.operators[3:], withSig(first))
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+}
+
+func TestECDSAOperatorTableFromDistribution(t *testing.T) {
+	set := newECDSATestOperatorSet(t, 2)
+	opset := distribution.OperatorSet{Id: 1, Avs: common.HexToAddress("0xa5")}
+	tableBytes, err := operatorTable.Encode(&operatorTable.OperatorTable{
+		OperatorSet: operatorTable.OperatorSet{Avs: opset.Avs, Id: opset.Id},
+		CurveType:   operatorTable.CurveTypeECDSA,
+		ECDSA:       set.operators,
+	})
+	require.NoError(t, err)
+
+	dist := distribution.NewDistributionWithOperatorSets([]distribution.OperatorSet{opset})
+	require.NoError(t, dist.SetTableData(opset, tableBytes))
+
+	operators, err := ECDSAOperatorTableFromDistribution(dist, opset)
+	require.NoError(t, err)
+	assert.Equal(t, set.operators, operators)
+
+	_, err = ECDSAOperatorTableFromDistribution(dist, distribution.OperatorSet{Id: 2, Avs: opset.Avs})
+	assert.Error(t, err)
+
+	bn254Bytes, err := operatorTable.Encode(&operatorTable.OperatorTable{
+		OperatorSet: operatorTable.OperatorSet{Avs: opset.Avs, Id: opset.Id},
+		CurveType:   operatorTable.CurveTypeBN254,
+		BN254:       newBN254TestOperatorSet(t, 1).info,
+	})
+	require.NoError(t, err)
+	_, err = DecodeECDSAOperatorTable(bn254Bytes)
+	assert.ErrorIs(t, err, ErrNotECDSATable)
+}
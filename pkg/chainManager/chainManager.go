@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel/trace"
 	"sync"
 )
 
@@ -27,12 +28,20 @@ type IChainManager interface {
 }
 
 // ChainConfig holds the configuration for connecting to a blockchain.
-// This includes the chain ID and the RPC URL for establishing connections.
+// This includes the chain ID and the RPC URLs for establishing connections.
 type ChainConfig struct {
 	// ChainID is the unique identifier for the blockchain network
 	ChainID uint64
 	// RPCUrl is the URL endpoint for connecting to the blockchain RPC
 	RPCUrl string
+	// RPCUrls are further endpoints, ranked after RPCUrl from most to least preferred. With more
+	// than one endpoint, the chain's client fails over between them (see FailoverEthClient)
+	RPCUrls []string
+	// Failover configures failover between the endpoints. When set, a FailoverEthClient is used
+	// even for a single endpoint
+	Failover *FailoverConfig
+	// TracerProvider, when set, wraps the RPC client so every call is recorded as a span
+	TracerProvider trace.TracerProvider
 }
 
 // Chain represents an active connection to a blockchain.
@@ -59,27 +68,68 @@ func NewChainManager() *ChainManager {
 	return &ChainManager{}
 }
 
+// Endpoints returns the chain's RPC URLs in rank order: RPCUrl, if set, then RPCUrls,
+// without duplicates.
+//
+// Returns:
+//   - []string: The RPC URLs, most preferred first
+func (c *ChainConfig) Endpoints() []string {
+	seen := make(map[string]bool)
+	urls := []string{}
+	for _, u := range append([]string{c.RPCUrl}, c.RPCUrls...) {
+		if u == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		urls = append(urls, u)
+	}
+	return urls
+}
+
 // AddChain adds a new blockchain connection to the manager.
-// This method establishes a connection to the specified RPC URL and
-// stores the resulting chain connection for future use.
+// This method establishes a connection to the specified RPC URLs and
+// stores the resulting chain connection for future use. A chain with several
+// endpoints, or a Failover configuration, gets a FailoverEthClient.
 // This method is thread-safe and can be called concurrently.
 //
 // Parameters:
-//   - cfg: The chain configuration containing chain ID and RPC URL
+//   - cfg: The chain configuration containing chain ID and RPC URLs
 //
 // Returns:
-//   - error: An error if the chain already exists or connection fails
+//   - error: An error if the chain already exists, has no RPC URL, or connection fails
 func (cm *ChainManager) AddChain(cfg *ChainConfig) error {
 	if _, exists := cm.Chains.Load(cfg.ChainID); exists {
 		return fmt.Errorf("chain with ID %d already exists", cfg.ChainID)
 	}
-	client, err := ethclient.Dial(cfg.RPCUrl)
-	if err != nil {
-		return fmt.Errorf("failed to connect to RPC URL %s: %w", cfg.RPCUrl, err)
+	urls := cfg.Endpoints()
+	if len(urls) == 0 {
+		return fmt.Errorf("chain with ID %d: %w", cfg.ChainID, ErrNoEndpoints)
+	}
+
+	var rpcClient EthClientInterface
+	if len(urls) == 1 && cfg.Failover == nil {
+		client, err := ethclient.Dial(urls[0])
+		if err != nil {
+			return fmt.Errorf("failed to connect to RPC URL %s: %w", urls[0], err)
+		}
+		rpcClient = client
+	} else {
+		var failoverCfg FailoverConfig
+		if cfg.Failover != nil {
+			failoverCfg = *cfg.Failover
+		}
+		client, err := DialFailoverEthClient(urls, failoverCfg)
+		if err != nil {
+			return err
+		}
+		rpcClient = client
+	}
+	if cfg.TracerProvider != nil {
+		rpcClient = NewTracedEthClient(rpcClient, cfg.ChainID, cfg.TracerProvider)
 	}
 	cm.Chains.Store(cfg.ChainID, &Chain{
 		config:    cfg,
-		RPCClient: client,
+		RPCClient: rpcClient,
 	})
 	return nil
 }
@@ -28,6 +28,8 @@ type EthClientInterface interface {
 	// Contract binding support (required for go-ethereum's bind package)
 	bind.ContractBackend
 	bind.ContractCaller
+	// Block-hash-pinned reads (EIP-1898), used by snapshot reads
+	bind.BlockHashContractCaller
 	bind.ContractTransactor
 	bind.ContractFilterer
 	bind.DeployBackend
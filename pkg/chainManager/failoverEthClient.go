@@ -0,0 +1,526 @@
+package chainManager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultCallTimeout is the per-endpoint timeout of a call when FailoverConfig.CallTimeout is unset
+	DefaultCallTimeout = 10 * time.Second
+	// DefaultCooldown is the cooldown after an endpoint's first failure when FailoverConfig.Cooldown is unset
+	DefaultCooldown = 5 * time.Second
+	// DefaultMaxCooldown caps the doubling cooldown when FailoverConfig.MaxCooldown is unset
+	DefaultMaxCooldown = 2 * time.Minute
+
+	// scoreWeight is the weight of the latest call in an endpoint's health score
+	scoreWeight = 0.3
+)
+
+var (
+	// ErrNoEndpoints is returned when a failover client is created without endpoints
+	ErrNoEndpoints = errors.New("no RPC endpoints configured")
+	// ErrAllEndpointsFailed is returned when a call failed on every endpoint
+	ErrAllEndpointsFailed = errors.New("all RPC endpoints failed")
+)
+
+// FailoverConfig configures how a FailoverEthClient moves calls between endpoints.
+type FailoverConfig struct {
+	// CallTimeout bounds each attempt on a single endpoint; a timed out attempt fails over
+	CallTimeout time.Duration
+	// Cooldown is how long an endpoint is passed over after a failure. It doubles with each
+	// consecutive failure, up to MaxCooldown
+	Cooldown time.Duration
+	// MaxCooldown caps the cooldown of an endpoint that keeps failing
+	MaxCooldown time.Duration
+	// StickyNonce routes PendingNonceAt and SendTransaction to the endpoint that last served
+	// one of them, until it fails, so nonces are read from the node that saw the last transaction
+	StickyNonce bool
+	// BroadcastTransactions sends each transaction to every available endpoint at once, and
+	// succeeds if any of them accepts it
+	BroadcastTransactions bool
+	// Logger logs failovers. Endpoint URLs are logged without their path or credentials
+	Logger *zap.Logger
+}
+
+// Endpoint is one RPC endpoint of a FailoverEthClient.
+type Endpoint struct {
+	// URL identifies the endpoint in logs and health reports
+	URL string
+	// Client is the client connected to the endpoint
+	Client EthClientInterface
+}
+
+// EndpointHealth reports the health of one endpoint.
+type EndpointHealth struct {
+	// Endpoint is the endpoint's URL without its path or credentials
+	Endpoint string
+	// Score is a moving average of the endpoint's call outcomes, from 0 (failing) to 1 (healthy)
+	Score float64
+	// ConsecutiveFailures is the number of calls that failed on the endpoint since its last success
+	ConsecutiveFailures int
+	// CooldownUntil is when the endpoint is next preferred again, or zero if it is available
+	CooldownUntil time.Time
+	// LastError is the endpoint's last failure, or nil
+	LastError error
+}
+
+// FailoverEthClient is an EthClientInterface over a ranked list of endpoints. Each call goes to
+// the most preferred available endpoint and fails over to the next one on transport errors,
+// timeouts, HTTP errors, rate limits and node-state errors such as a missing block. Errors that
+// any node would return, such as reverts or a receipt that is not found, are returned without
+// failing over.
+//
+// An endpoint that fails is passed over for a cooldown that doubles with each consecutive
+// failure. Available endpoints are tried in rank order, then endpoints in cooldown by health
+// score, so a call is only refused when every endpoint has failed it. A FailoverEthClient is
+// safe for concurrent use.
+type FailoverEthClient struct {
+	endpoints []*endpoint
+	config    FailoverConfig
+	logger    *zap.Logger
+	now       func() time.Time
+
+	mu sync.Mutex
+	// sticky is the index of the endpoint serving nonce-sensitive calls, or -1
+	sticky int
+}
+
+type endpoint struct {
+	rank     int
+	redacted string
+	client   EthClientInterface
+
+	// guarded by FailoverEthClient.mu
+	score         float64
+	failures      int
+	cooldownUntil time.Time
+	lastErr       error
+}
+
+// NewFailoverEthClient creates a failover client over connected endpoints.
+//
+// Parameters:
+//   - endpoints: The endpoints, most preferred first
+//   - cfg: The failover configuration; zero durations use the defaults
+//
+// Returns:
+//   - *FailoverEthClient: The client
+//   - error: ErrNoEndpoints if endpoints is empty
+func NewFailoverEthClient(endpoints []Endpoint, cfg FailoverConfig) (*FailoverEthClient, error) {
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	if cfg.CallTimeout <= 0 {
+		cfg.CallTimeout = DefaultCallTimeout
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = DefaultCooldown
+	}
+	if cfg.MaxCooldown <= 0 {
+		cfg.MaxCooldown = DefaultMaxCooldown
+	}
+	l := cfg.Logger
+	if l == nil {
+		l = zap.NewNop()
+	}
+
+	f := &FailoverEthClient{
+		config: cfg,
+		logger: l,
+		now:    time.Now,
+		sticky: -1,
+	}
+	for i, e := range endpoints {
+		f.endpoints = append(f.endpoints, &endpoint{
+			rank:     i,
+			redacted: redactURL(e.URL),
+			client:   e.Client,
+			score:    1,
+		})
+	}
+	return f, nil
+}
+
+// DialFailoverEthClient connects to each URL and creates a failover client over them.
+//
+// Parameters:
+//   - urls: The RPC URLs, most preferred first
+//   - cfg: The failover configuration; zero durations use the defaults
+//
+// Returns:
+//   - *FailoverEthClient: The client
+//   - error: An error if there are no URLs or a URL cannot be dialed
+func DialFailoverEthClient(urls []string, cfg FailoverConfig) (*FailoverEthClient, error) {
+	endpoints := make([]Endpoint, 0, len(urls))
+	for _, u := range urls {
+		client, err := ethclient.Dial(u)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to RPC URL %s: %w", redactURL(u), err)
+		}
+		endpoints = append(endpoints, Endpoint{URL: u, Client: client})
+	}
+	return NewFailoverEthClient(endpoints, cfg)
+}
+
+// Health reports the health of every endpoint, in rank order.
+//
+// Returns:
+//   - []EndpointHealth: The health of each endpoint
+func (f *FailoverEthClient) Health() []EndpointHealth {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := f.now()
+	health := make([]EndpointHealth, len(f.endpoints))
+	for i, e := range f.endpoints {
+		health[i] = EndpointHealth{
+			Endpoint:            e.redacted,
+			Score:               e.score,
+			ConsecutiveFailures: e.failures,
+			LastError:           e.lastErr,
+		}
+		if now.Before(e.cooldownUntil) {
+			health[i].CooldownUntil = e.cooldownUntil
+		}
+	}
+	return health
+}
+
+// order returns the endpoints in the order a call tries them: available endpoints in rank
+// order, then endpoints in cooldown by descending health score. Sticky calls try the sticky
+// endpoint first while it is available.
+//
+// Returns:
+//   - []*endpoint: The endpoints in the order to try them
+//   - int: The number of available endpoints at the front
+func (f *FailoverEthClient) order(sticky bool) ([]*endpoint, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := f.now()
+
+	var available, cooling []*endpoint
+	for _, e := range f.endpoints {
+		if now.Before(e.cooldownUntil) {
+			cooling = append(cooling, e)
+			continue
+		}
+		if sticky && e.rank == f.sticky {
+			available = append([]*endpoint{e}, available...)
+			continue
+		}
+		available = append(available, e)
+	}
+	sort.SliceStable(cooling, func(i, j int) bool { return cooling[i].score > cooling[j].score })
+	return append(available, cooling...), len(available)
+}
+
+// recordSuccess marks a call an endpoint answered, including with an error any node would return.
+func (f *FailoverEthClient) recordSuccess(e *endpoint, sticky bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e.score += scoreWeight * (1 - e.score)
+	e.failures = 0
+	e.cooldownUntil = time.Time{}
+	if sticky {
+		f.sticky = e.rank
+	}
+}
+
+// recordFailure marks an endpoint failure and starts its cooldown.
+func (f *FailoverEthClient) recordFailure(e *endpoint, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e.score -= scoreWeight * e.score
+	e.failures++
+	e.lastErr = err
+	cooldown := f.config.Cooldown
+	for i := 1; i < e.failures && cooldown < f.config.MaxCooldown; i++ {
+		cooldown *= 2
+	}
+	e.cooldownUntil = f.now().Add(min(cooldown, f.config.MaxCooldown))
+	if f.sticky == e.rank {
+		f.sticky = -1
+	}
+}
+
+// failoverCall runs fn on each endpoint in order until one answers.
+func failoverCall[T any](ctx context.Context, f *FailoverEthClient, method string, sticky bool, fn func(context.Context, EthClientInterface) (T, error)) (T, error) {
+	var zero T
+	var errs []error
+	endpoints, _ := f.order(sticky)
+	for _, e := range endpoints {
+		attemptCtx, cancel := context.WithTimeout(ctx, f.config.CallTimeout)
+		v, err := fn(attemptCtx, e.client)
+		cancel()
+		if err == nil {
+			f.recordSuccess(e, sticky)
+			return v, nil
+		}
+		if ctx.Err() != nil {
+			return zero, err
+		}
+		if !isEndpointError(err) {
+			f.recordSuccess(e, sticky)
+			return zero, err
+		}
+		f.recordFailure(e, err)
+		f.logger.Sugar().Warnw("RPC endpoint failed, failing over",
+			zap.String("method", method),
+			zap.String("endpoint", e.redacted),
+			zap.Error(err),
+		)
+		errs = append(errs, fmt.Errorf("%s: %w", e.redacted, err))
+	}
+	return zero, fmt.Errorf("%w: %s: %w", ErrAllEndpointsFailed, method, errors.Join(errs...))
+}
+
+// nodeStateErrorMarkers are the messages geth returns for reads at a block the node does not
+// have: by number ("header not found"), by hash ("header for hash not found", "hash is not
+// currently canonical") or with pruned state ("missing trie node").
+var nodeStateErrorMarkers = []string{
+	"header not found",
+	"header for hash not found",
+	"hash is not currently canonical",
+	"unknown block",
+	"missing trie node",
+}
+
+// isEndpointError reports whether an error is specific to the endpoint that returned it, so
+// another endpoint may succeed.
+func isEndpointError(err error) bool {
+	if errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return true
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.ErrorCode() {
+		// method not found, request timeout, limit exceeded, internal error
+		case -32601, -32002, -32005, -32603:
+			return true
+		}
+		// Nodes that are behind, pruned or on another fork report missing state with the
+		// generic error code
+		msg := rpcErr.Error()
+		for _, marker := range nodeStateErrorMarkers {
+			if strings.Contains(msg, marker) {
+				return true
+			}
+		}
+		return false
+	}
+	// Transport errors, timeouts and malformed responses
+	return true
+}
+
+// isAlreadyKnown reports whether a node rejected a transaction because it already has it.
+func isAlreadyKnown(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "already known")
+}
+
+// redactURL strips everything but the scheme and host from a URL, as paths and query strings
+// of hosted RPC URLs often carry API keys.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "<invalid url>"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// BlockNumber implements EthClientInterface.
+func (f *FailoverEthClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return failoverCall(ctx, f, "eth_blockNumber", false, func(ctx context.Context, c EthClientInterface) (uint64, error) {
+		return c.BlockNumber(ctx)
+	})
+}
+
+// BlockByNumber implements EthClientInterface.
+func (f *FailoverEthClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return failoverCall(ctx, f, "eth_getBlockByNumber", false, func(ctx context.Context, c EthClientInterface) (*types.Block, error) {
+		return c.BlockByNumber(ctx, number)
+	})
+}
+
+// HeaderByNumber implements EthClientInterface.
+func (f *FailoverEthClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return failoverCall(ctx, f, "eth_getBlockByNumber", false, func(ctx context.Context, c EthClientInterface) (*types.Header, error) {
+		return c.HeaderByNumber(ctx, number)
+	})
+}
+
+// EstimateGas implements EthClientInterface.
+func (f *FailoverEthClient) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return failoverCall(ctx, f, "eth_estimateGas", false, func(ctx context.Context, c EthClientInterface) (uint64, error) {
+		return c.EstimateGas(ctx, msg)
+	})
+}
+
+// SuggestGasTipCap implements EthClientInterface.
+func (f *FailoverEthClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return failoverCall(ctx, f, "eth_maxPriorityFeePerGas", false, func(ctx context.Context, c EthClientInterface) (*big.Int, error) {
+		return c.SuggestGasTipCap(ctx)
+	})
+}
+
+// SuggestGasPrice implements EthClientInterface.
+func (f *FailoverEthClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return failoverCall(ctx, f, "eth_gasPrice", false, func(ctx context.Context, c EthClientInterface) (*big.Int, error) {
+		return c.SuggestGasPrice(ctx)
+	})
+}
+
+// TransactionReceipt implements EthClientInterface.
+func (f *FailoverEthClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return failoverCall(ctx, f, "eth_getTransactionReceipt", false, func(ctx context.Context, c EthClientInterface) (*types.Receipt, error) {
+		return c.TransactionReceipt(ctx, txHash)
+	})
+}
+
+// CallContract implements EthClientInterface.
+func (f *FailoverEthClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return failoverCall(ctx, f, "eth_call", false, func(ctx context.Context, c EthClientInterface) ([]byte, error) {
+		return c.CallContract(ctx, call, blockNumber)
+	})
+}
+
+// CodeAt implements EthClientInterface.
+func (f *FailoverEthClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return failoverCall(ctx, f, "eth_getCode", false, func(ctx context.Context, c EthClientInterface) ([]byte, error) {
+		return c.CodeAt(ctx, contract, blockNumber)
+	})
+}
+
+// CallContractAtHash implements EthClientInterface.
+func (f *FailoverEthClient) CallContractAtHash(ctx context.Context, call ethereum.CallMsg, blockHash common.Hash) ([]byte, error) {
+	return failoverCall(ctx, f, "eth_call", false, func(ctx context.Context, c EthClientInterface) ([]byte, error) {
+		return c.CallContractAtHash(ctx, call, blockHash)
+	})
+}
+
+// CodeAtHash implements EthClientInterface.
+func (f *FailoverEthClient) CodeAtHash(ctx context.Context, contract common.Address, blockHash common.Hash) ([]byte, error) {
+	return failoverCall(ctx, f, "eth_getCode", false, func(ctx context.Context, c EthClientInterface) ([]byte, error) {
+		return c.CodeAtHash(ctx, contract, blockHash)
+	})
+}
+
+// PendingCodeAt implements EthClientInterface.
+func (f *FailoverEthClient) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return failoverCall(ctx, f, "eth_getCode", false, func(ctx context.Context, c EthClientInterface) ([]byte, error) {
+		return c.PendingCodeAt(ctx, account)
+	})
+}
+
+// PendingNonceAt implements EthClientInterface. It is routed to the sticky endpoint when
+// FailoverConfig.StickyNonce is set.
+func (f *FailoverEthClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return failoverCall(ctx, f, "eth_getTransactionCount", f.config.StickyNonce, func(ctx context.Context, c EthClientInterface) (uint64, error) {
+		return c.PendingNonceAt(ctx, account)
+	})
+}
+
+// SendTransaction implements EthClientInterface. It is routed to the sticky endpoint when
+// FailoverConfig.StickyNonce is set, and sent to every available endpoint when
+// FailoverConfig.BroadcastTransactions is set. A node that already has the transaction counts
+// as accepting it.
+func (f *FailoverEthClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	send := func(ctx context.Context, c EthClientInterface) (struct{}, error) {
+		err := c.SendTransaction(ctx, tx)
+		if isAlreadyKnown(err) {
+			return struct{}{}, nil
+		}
+		return struct{}{}, err
+	}
+	if !f.config.BroadcastTransactions {
+		_, err := failoverCall(ctx, f, "eth_sendRawTransaction", f.config.StickyNonce, send)
+		return err
+	}
+	return f.broadcast(ctx, tx, send)
+}
+
+// broadcast sends a transaction to every available endpoint at once, or to every endpoint if
+// none is available. It returns the error of the most preferred endpoint if none accepted it.
+func (f *FailoverEthClient) broadcast(ctx context.Context, tx *types.Transaction, send func(context.Context, EthClientInterface) (struct{}, error)) error {
+	targets, available := f.order(f.config.StickyNonce)
+	if available > 0 {
+		targets = targets[:available]
+	}
+
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for i, e := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attemptCtx, cancel := context.WithTimeout(ctx, f.config.CallTimeout)
+			defer cancel()
+			_, errs[i] = send(attemptCtx, e.client)
+		}()
+	}
+	wg.Wait()
+
+	// Results are recorded in order, so the sticky endpoint is the most preferred one that accepted
+	accepted := false
+	for i := len(targets) - 1; i >= 0; i-- {
+		e, err := targets[i], errs[i]
+		switch {
+		case err == nil:
+			accepted = true
+			f.recordSuccess(e, f.config.StickyNonce)
+		case ctx.Err() != nil:
+		case isEndpointError(err):
+			f.recordFailure(e, err)
+			f.logger.Sugar().Warnw("RPC endpoint failed to accept broadcast transaction",
+				zap.String("endpoint", e.redacted),
+				zap.String("txHash", tx.Hash().Hex()),
+				zap.Error(err),
+			)
+		default:
+			f.recordSuccess(e, false)
+		}
+	}
+	if accepted {
+		return nil
+	}
+	if ctx.Err() != nil || !isEndpointError(errs[0]) {
+		return errs[0]
+	}
+	wrapped := make([]error, len(targets))
+	for i, e := range targets {
+		wrapped[i] = fmt.Errorf("%s: %w", e.redacted, errs[i])
+	}
+	return fmt.Errorf("%w: eth_sendRawTransaction: %w", ErrAllEndpointsFailed, errors.Join(wrapped...))
+}
+
+// FilterLogs implements EthClientInterface.
+func (f *FailoverEthClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return failoverCall(ctx, f, "eth_getLogs", false, func(ctx context.Context, c EthClientInterface) ([]types.Log, error) {
+		return c.FilterLogs(ctx, q)
+	})
+}
+
+// SubscribeFilterLogs implements EthClientInterface. Only creating the subscription fails over;
+// an established subscription stays on its endpoint.
+func (f *FailoverEthClient) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return failoverCall(ctx, f, "eth_subscribe", false, func(ctx context.Context, c EthClientInterface) (ethereum.Subscription, error) {
+		return c.SubscribeFilterLogs(ctx, q, ch)
+	})
+}
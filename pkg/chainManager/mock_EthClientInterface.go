@@ -108,6 +108,36 @@ func (_m *MockEthClientInterface) CallContract(ctx context.Context, call ethereu
 	return r0, r1
 }
 
+// CallContractAtHash provides a mock function with given fields: ctx, call, blockHash
+func (_m *MockEthClientInterface) CallContractAtHash(ctx context.Context, call ethereum.CallMsg, blockHash common.Hash) ([]byte, error) {
+	ret := _m.Called(ctx, call, blockHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CallContractAtHash")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ethereum.CallMsg, common.Hash) ([]byte, error)); ok {
+		return rf(ctx, call, blockHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ethereum.CallMsg, common.Hash) []byte); ok {
+		r0 = rf(ctx, call, blockHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ethereum.CallMsg, common.Hash) error); ok {
+		r1 = rf(ctx, call, blockHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CodeAt provides a mock function with given fields: ctx, contract, blockNumber
 func (_m *MockEthClientInterface) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
 	ret := _m.Called(ctx, contract, blockNumber)
@@ -138,6 +168,36 @@ func (_m *MockEthClientInterface) CodeAt(ctx context.Context, contract common.Ad
 	return r0, r1
 }
 
+// CodeAtHash provides a mock function with given fields: ctx, contract, blockHash
+func (_m *MockEthClientInterface) CodeAtHash(ctx context.Context, contract common.Address, blockHash common.Hash) ([]byte, error) {
+	ret := _m.Called(ctx, contract, blockHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CodeAtHash")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, common.Hash) ([]byte, error)); ok {
+		return rf(ctx, contract, blockHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, common.Hash) []byte); ok {
+		r0 = rf(ctx, contract, blockHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, common.Hash) error); ok {
+		r1 = rf(ctx, contract, blockHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EstimateGas provides a mock function with given fields: ctx, msg
 func (_m *MockEthClientInterface) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
 	ret := _m.Called(ctx, msg)
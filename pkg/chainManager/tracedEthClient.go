@@ -0,0 +1,191 @@
+package chainManager
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Layr-Labs/multichain-go/pkg/tracing"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracedEthClient wraps an EthClientInterface and records an OpenTelemetry span
+// for every RPC call made through it. Spans are named after the JSON-RPC method
+// and become children of whatever span is active in the call's context.
+type TracedEthClient struct {
+	client  EthClientInterface
+	tracer  trace.Tracer
+	chainId uint64
+}
+
+// NewTracedEthClient creates a new TracedEthClient around an existing client.
+//
+// Parameters:
+//   - client: The client to instrument
+//   - chainId: The chain ID reported on every span
+//   - tp: The tracer provider used to create spans
+//
+// Returns:
+//   - *TracedEthClient: The instrumented client
+func NewTracedEthClient(client EthClientInterface, chainId uint64, tp trace.TracerProvider) *TracedEthClient {
+	return &TracedEthClient{
+		client:  client,
+		tracer:  tracing.NewTracer(tp),
+		chainId: chainId,
+	}
+}
+
+func (t *TracedEthClient) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.method", method),
+		attribute.Int64("chainId", int64(t.chainId)),
+	)
+	return tracing.Start(ctx, t.tracer, method, attrs...)
+}
+
+func blockAttr(number *big.Int) attribute.KeyValue {
+	if number == nil {
+		return attribute.String("blockNumber", "latest")
+	}
+	return attribute.String("blockNumber", number.String())
+}
+
+// BlockNumber implements EthClientInterface.
+func (t *TracedEthClient) BlockNumber(ctx context.Context) (uint64, error) {
+	ctx, span := t.start(ctx, "eth_blockNumber")
+	n, err := t.client.BlockNumber(ctx)
+	tracing.End(span, err)
+	return n, err
+}
+
+// BlockByNumber implements EthClientInterface.
+func (t *TracedEthClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	ctx, span := t.start(ctx, "eth_getBlockByNumber", blockAttr(number))
+	b, err := t.client.BlockByNumber(ctx, number)
+	tracing.End(span, err)
+	return b, err
+}
+
+// HeaderByNumber implements EthClientInterface.
+func (t *TracedEthClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	ctx, span := t.start(ctx, "eth_getBlockByNumber", blockAttr(number))
+	h, err := t.client.HeaderByNumber(ctx, number)
+	tracing.End(span, err)
+	return h, err
+}
+
+// EstimateGas implements EthClientInterface.
+func (t *TracedEthClient) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	ctx, span := t.start(ctx, "eth_estimateGas")
+	g, err := t.client.EstimateGas(ctx, msg)
+	tracing.End(span, err)
+	return g, err
+}
+
+// SuggestGasTipCap implements EthClientInterface.
+func (t *TracedEthClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	ctx, span := t.start(ctx, "eth_maxPriorityFeePerGas")
+	tip, err := t.client.SuggestGasTipCap(ctx)
+	tracing.End(span, err)
+	return tip, err
+}
+
+// SuggestGasPrice implements EthClientInterface.
+func (t *TracedEthClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	ctx, span := t.start(ctx, "eth_gasPrice")
+	price, err := t.client.SuggestGasPrice(ctx)
+	tracing.End(span, err)
+	return price, err
+}
+
+// TransactionReceipt implements EthClientInterface.
+func (t *TracedEthClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	ctx, span := t.start(ctx, "eth_getTransactionReceipt", attribute.String("txHash", txHash.Hex()))
+	r, err := t.client.TransactionReceipt(ctx, txHash)
+	tracing.End(span, err)
+	return r, err
+}
+
+// CallContract implements EthClientInterface.
+func (t *TracedEthClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	attrs := []attribute.KeyValue{blockAttr(blockNumber)}
+	if call.To != nil {
+		attrs = append(attrs, attribute.String("to", call.To.Hex()))
+	}
+	ctx, span := t.start(ctx, "eth_call", attrs...)
+	out, err := t.client.CallContract(ctx, call, blockNumber)
+	tracing.End(span, err)
+	return out, err
+}
+
+// CodeAt implements EthClientInterface.
+func (t *TracedEthClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	ctx, span := t.start(ctx, "eth_getCode", attribute.String("address", contract.Hex()), blockAttr(blockNumber))
+	code, err := t.client.CodeAt(ctx, contract, blockNumber)
+	tracing.End(span, err)
+	return code, err
+}
+
+// CallContractAtHash implements EthClientInterface.
+func (t *TracedEthClient) CallContractAtHash(ctx context.Context, call ethereum.CallMsg, blockHash common.Hash) ([]byte, error) {
+	attrs := []attribute.KeyValue{attribute.String("blockHash", blockHash.Hex())}
+	if call.To != nil {
+		attrs = append(attrs, attribute.String("to", call.To.Hex()))
+	}
+	ctx, span := t.start(ctx, "eth_call", attrs...)
+	out, err := t.client.CallContractAtHash(ctx, call, blockHash)
+	tracing.End(span, err)
+	return out, err
+}
+
+// CodeAtHash implements EthClientInterface.
+func (t *TracedEthClient) CodeAtHash(ctx context.Context, contract common.Address, blockHash common.Hash) ([]byte, error) {
+	ctx, span := t.start(ctx, "eth_getCode", attribute.String("address", contract.Hex()), attribute.String("blockHash", blockHash.Hex()))
+	code, err := t.client.CodeAtHash(ctx, contract, blockHash)
+	tracing.End(span, err)
+	return code, err
+}
+
+// PendingCodeAt implements EthClientInterface.
+func (t *TracedEthClient) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	ctx, span := t.start(ctx, "eth_getCode", attribute.String("address", account.Hex()), attribute.String("blockNumber", "pending"))
+	code, err := t.client.PendingCodeAt(ctx, account)
+	tracing.End(span, err)
+	return code, err
+}
+
+// PendingNonceAt implements EthClientInterface.
+func (t *TracedEthClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	ctx, span := t.start(ctx, "eth_getTransactionCount", attribute.String("address", account.Hex()))
+	nonce, err := t.client.PendingNonceAt(ctx, account)
+	tracing.End(span, err)
+	return nonce, err
+}
+
+// SendTransaction implements EthClientInterface.
+func (t *TracedEthClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	ctx, span := t.start(ctx, "eth_sendRawTransaction", attribute.String("txHash", tx.Hash().Hex()))
+	err := t.client.SendTransaction(ctx, tx)
+	tracing.End(span, err)
+	return err
+}
+
+// FilterLogs implements EthClientInterface.
+func (t *TracedEthClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	ctx, span := t.start(ctx, "eth_getLogs")
+	logs, err := t.client.FilterLogs(ctx, q)
+	tracing.End(span, err)
+	return logs, err
+}
+
+// SubscribeFilterLogs implements EthClientInterface.
+func (t *TracedEthClient) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	ctx, span := t.start(ctx, "eth_subscribe")
+	sub, err := t.client.SubscribeFilterLogs(ctx, q, ch)
+	tracing.End(span, err)
+	return sub, err
+}
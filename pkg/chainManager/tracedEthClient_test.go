@@ -0,0 +1,90 @@
+package chainManager
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/multichain-go/pkg/logger"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func setupTracedClient(t *testing.T) (*TracedEthClient, *MockEthClientInterface, *tracetest.InMemoryExporter, *sdktrace.TracerProvider) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	mockClient := NewMockEthClientInterface(t)
+	return NewTracedEthClient(mockClient, 17000, tp), mockClient, exporter, tp
+}
+
+func TestTracedEthClient_CallContractRecordsChildSpan(t *testing.T) {
+	client, mockClient, exporter, tp := setupTracedClient(t)
+
+	to := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	msg := ethereum.CallMsg{To: &to}
+	mockClient.On("CallContract", mock.Anything, msg, big.NewInt(100)).Return([]byte{0x01}, nil)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	out, err := client.CallContract(ctx, msg, big.NewInt(100))
+	parent.End()
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x01}, out)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	call := spans[0]
+	assert.Equal(t, "eth_call", call.Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), call.Parent.SpanID())
+	assert.Contains(t, call.Attributes, attribute.String("to", to.Hex()))
+	assert.Contains(t, call.Attributes, attribute.String("blockNumber", "100"))
+}
+
+func TestTracedEthClient_ErrorMarksSpan(t *testing.T) {
+	client, mockClient, exporter, _ := setupTracedClient(t)
+
+	mockClient.On("BlockNumber", mock.Anything).Return(uint64(0), errors.New("connection refused"))
+
+	_, err := client.BlockNumber(context.Background())
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "eth_blockNumber", spans[0].Name)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, "connection refused", spans[0].Status.Description)
+}
+
+func TestTracedEthClient_TraceIdsInjectedIntoLogs(t *testing.T) {
+	client, mockClient, _, tp := setupTracedClient(t)
+
+	core, logs := observer.New(zap.InfoLevel)
+	l := zap.New(core)
+
+	mockClient.On("BlockNumber", mock.Anything).Run(func(args mock.Arguments) {
+		logger.WithTraceContext(args.Get(0).(context.Context), l).Info("inside rpc")
+	}).Return(uint64(42), nil)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	_, err := client.BlockNumber(ctx)
+	parent.End()
+	require.NoError(t, err)
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, parent.SpanContext().TraceID().String(), fields["traceId"])
+	assert.NotEqual(t, parent.SpanContext().SpanID().String(), fields["spanId"], "log should carry the RPC span, not the parent")
+}
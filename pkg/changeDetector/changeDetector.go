@@ -0,0 +1,162 @@
+// Package changeDetector decides whether an operator table needs to be transported to a
+// destination chain. A table is sent when it differs from the one the destination last
+// received, or when the destination's copy is approaching its max staleness period. The
+// destination's copy is looked up in a local Store of previous transports, falling back to
+// rebuilding it from the destination chain's certificate verifier.
+package changeDetector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/policy"
+)
+
+// DefaultStalenessThreshold is the fraction of an operator set's max staleness period after
+// which an unchanged table is transported again.
+const DefaultStalenessThreshold = 0.8
+
+// Config holds the configuration for a Detector.
+type Config struct {
+	// Store, when set, records transported tables and is consulted before the destination chain
+	Store Store
+	// Reader, when set, reads the destination's table when the store has no record of it
+	Reader DestinationReader
+	// StalenessThreshold is the fraction of the max staleness period after which an unchanged
+	// table is refreshed. Defaults to DefaultStalenessThreshold.
+	StalenessThreshold float64
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Detector implements policy.ChangeDetector.
+type Detector struct {
+	store              Store
+	reader             DestinationReader
+	stalenessThreshold float64
+	now                func() time.Time
+}
+
+var _ policy.ChangeDetector = (*Detector)(nil)
+
+// NewDetector creates a new Detector.
+//
+// Parameters:
+//   - cfg: The detector configuration
+//
+// Returns:
+//   - *Detector: The detector
+//   - error: An error if the staleness threshold is out of range
+func NewDetector(cfg *Config) (*Detector, error) {
+	threshold := cfg.StalenessThreshold
+	if threshold == 0 {
+		threshold = DefaultStalenessThreshold
+	}
+	if threshold < 0 || threshold > 1 {
+		return nil, fmt.Errorf("staleness threshold must be between 0 and 1, got %v", threshold)
+	}
+	now := cfg.Now
+	if now == nil {
+		now = time.Now
+	}
+	return &Detector{
+		store:              cfg.Store,
+		reader:             cfg.Reader,
+		stalenessThreshold: threshold,
+		now:                now,
+	}, nil
+}
+
+// DetectTableChange implements policy.ChangeDetector. The table is reported as changed when
+// the destination has no previous table, when the previous table's leaf hash differs, or when
+// the previous table has used up the staleness threshold of its max staleness period.
+func (d *Detector) DetectTableChange(
+	ctx context.Context,
+	dest policy.Destination,
+	opset distribution.OperatorSet,
+	tableBytes []byte,
+) (policy.TableChange, error) {
+	table, err := operatorTable.Decode(tableBytes)
+	if err != nil {
+		return policy.TableChange{}, err
+	}
+
+	previous, source, err := d.previousRecord(ctx, dest, opset, table.CurveType)
+	if err != nil {
+		return policy.TableChange{}, err
+	}
+	if previous == nil {
+		return policy.TableChange{Changed: true, Reason: "no previous table on destination"}, nil
+	}
+	if previous.LeafHash != LeafHash(tableBytes) {
+		return policy.TableChange{
+			Changed: true,
+			Reason:  fmt.Sprintf("table changed since reference timestamp %d (%s)", previous.ReferenceTimestamp, source),
+		}, nil
+	}
+
+	maxStaleness := table.Config.MaxStalenessPeriod
+	if maxStaleness > 0 {
+		elapsed := d.now().Unix() - int64(previous.ReferenceTimestamp)
+		if float64(elapsed) >= d.stalenessThreshold*float64(maxStaleness) {
+			return policy.TableChange{
+				Changed: true,
+				Reason:  fmt.Sprintf("table approaching max staleness (%ds of %ds elapsed)", elapsed, maxStaleness),
+			}, nil
+		}
+	}
+	return policy.TableChange{
+		Changed: false,
+		Reason:  fmt.Sprintf("table unchanged since reference timestamp %d (%s)", previous.ReferenceTimestamp, source),
+	}, nil
+}
+
+// RecordTableTransported implements policy.ChangeDetector by saving the table to the store.
+func (d *Detector) RecordTableTransported(
+	_ context.Context,
+	dest policy.Destination,
+	opset distribution.OperatorSet,
+	tableBytes []byte,
+	referenceTimestamp uint32,
+) error {
+	if d.store == nil {
+		return nil
+	}
+	if err := d.store.Put(dest.ChainId, opset, Record{
+		LeafHash:           LeafHash(tableBytes),
+		ReferenceTimestamp: referenceTimestamp,
+	}); err != nil {
+		return fmt.Errorf("failed to record transported table: %w", err)
+	}
+	return nil
+}
+
+// previousRecord looks up the destination's last table, first in the store and then on chain.
+// It also returns where the record came from.
+func (d *Detector) previousRecord(
+	ctx context.Context,
+	dest policy.Destination,
+	opset distribution.OperatorSet,
+	curveType operatorTable.CurveType,
+) (*Record, string, error) {
+	if d.store != nil {
+		record, err := d.store.Get(dest.ChainId, opset)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read table store: %w", err)
+		}
+		if record != nil {
+			return record, "local store", nil
+		}
+	}
+	if d.reader != nil {
+		record, err := d.reader.ReadTable(ctx, dest, opset, curveType)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read destination table: %w", err)
+		}
+		return record, "destination state", nil
+	}
+	return nil, "", nil
+}
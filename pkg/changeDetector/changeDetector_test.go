@@ -0,0 +1,186 @@
+package changeDetector
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/policy"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReader struct {
+	record *Record
+	err    error
+	calls  int
+}
+
+func (f *fakeReader) ReadTable(context.Context, policy.Destination, distribution.OperatorSet, operatorTable.CurveType) (*Record, error) {
+	f.calls++
+	return f.record, f.err
+}
+
+var (
+	testDest  = policy.Destination{ChainId: 8453, OperatorTableUpdater: common.HexToAddress("0xbeef")}
+	testOpset = distribution.OperatorSet{Id: 1, Avs: common.HexToAddress("0xa1")}
+	testNow   = time.Unix(1700010000, 0)
+)
+
+func encodeTestTable(t *testing.T, maxStaleness uint32, weight int64) []byte {
+	tableBytes, err := operatorTable.Encode(&operatorTable.OperatorTable{
+		OperatorSet: operatorTable.OperatorSet{Avs: testOpset.Avs, Id: testOpset.Id},
+		CurveType:   operatorTable.CurveTypeECDSA,
+		Config:      operatorTable.OperatorSetConfig{MaxStalenessPeriod: maxStaleness},
+		ECDSA: []operatorTable.ECDSAOperatorInfo{
+			{Pubkey: common.HexToAddress("0x01"), Weights: []*big.Int{big.NewInt(weight)}},
+		},
+	})
+	require.NoError(t, err)
+	return tableBytes
+}
+
+func newTestDetector(t *testing.T, store Store, reader DestinationReader) *Detector {
+	detector, err := NewDetector(&Config{
+		Store:  store,
+		Reader: reader,
+		Now:    func() time.Time { return testNow },
+	})
+	require.NoError(t, err)
+	return detector
+}
+
+func TestDetectTableChange(t *testing.T) {
+	const maxStaleness = 10000
+	table := encodeTestTable(t, maxStaleness, 100)
+
+	tests := []struct {
+		name        string
+		record      *Record
+		wantChanged bool
+		wantReason  string
+	}{
+		{
+			name:        "no previous table",
+			record:      nil,
+			wantChanged: true,
+			wantReason:  "no previous table",
+		},
+		{
+			name:        "table changed",
+			record:      &Record{LeafHash: LeafHash(encodeTestTable(t, maxStaleness, 99)), ReferenceTimestamp: uint32(testNow.Unix()) - 100},
+			wantChanged: true,
+			wantReason:  "table changed",
+		},
+		{
+			name:        "unchanged and fresh",
+			record:      &Record{LeafHash: LeafHash(table), ReferenceTimestamp: uint32(testNow.Unix()) - 7999},
+			wantChanged: false,
+			wantReason:  "table unchanged",
+		},
+		{
+			name:        "unchanged but approaching max staleness",
+			record:      &Record{LeafHash: LeafHash(table), ReferenceTimestamp: uint32(testNow.Unix()) - 8000},
+			wantChanged: true,
+			wantReason:  "approaching max staleness (8000s of 10000s elapsed)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := newTestDetector(t, nil, &fakeReader{record: tt.record})
+			change, err := detector.DetectTableChange(context.Background(), testDest, testOpset, table)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantChanged, change.Changed)
+			assert.Contains(t, change.Reason, tt.wantReason)
+		})
+	}
+}
+
+func TestDetectTableChange_NoStalenessPeriod(t *testing.T) {
+	table := encodeTestTable(t, 0, 100)
+	detector := newTestDetector(t, nil, &fakeReader{record: &Record{LeafHash: LeafHash(table), ReferenceTimestamp: 1}})
+
+	change, err := detector.DetectTableChange(context.Background(), testDest, testOpset, table)
+	require.NoError(t, err)
+	assert.False(t, change.Changed)
+}
+
+func TestDetectTableChange_StoreTakesPrecedence(t *testing.T) {
+	table := encodeTestTable(t, 0, 100)
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "tables.json"))
+	require.NoError(t, err)
+	reader := &fakeReader{err: errors.New("rpc down")}
+	detector := newTestDetector(t, store, reader)
+
+	// Nothing recorded yet, so the destination is read
+	_, err = detector.DetectTableChange(context.Background(), testDest, testOpset, table)
+	require.ErrorContains(t, err, "rpc down")
+	assert.Equal(t, 1, reader.calls)
+
+	require.NoError(t, detector.RecordTableTransported(context.Background(), testDest, testOpset, table, 1700000000))
+
+	change, err := detector.DetectTableChange(context.Background(), testDest, testOpset, table)
+	require.NoError(t, err)
+	assert.False(t, change.Changed)
+	assert.Contains(t, change.Reason, "local store")
+	assert.Equal(t, 1, reader.calls)
+
+	// Another chain has no record
+	otherDest := policy.Destination{ChainId: 10}
+	reader.err = nil
+	change, err = detector.DetectTableChange(context.Background(), otherDest, testOpset, table)
+	require.NoError(t, err)
+	assert.True(t, change.Changed)
+}
+
+func TestDetectTableChange_InvalidTable(t *testing.T) {
+	detector := newTestDetector(t, nil, nil)
+	_, err := detector.DetectTableChange(context.Background(), testDest, testOpset, []byte{0x01})
+	assert.Error(t, err)
+}
+
+func TestNewDetector_InvalidThreshold(t *testing.T) {
+	_, err := NewDetector(&Config{StalenessThreshold: 1.5})
+	assert.Error(t, err)
+}
+
+func TestFileStore_Persists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tables.json")
+	store, err := NewFileStore(path)
+	require.NoError(t, err)
+
+	record, err := store.Get(1, testOpset)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	want := Record{LeafHash: common.HexToHash("0x1234"), ReferenceTimestamp: 42}
+	require.NoError(t, store.Put(1, testOpset, want))
+	require.NoError(t, store.Put(2, testOpset, Record{ReferenceTimestamp: 7}))
+
+	reopened, err := NewFileStore(path)
+	require.NoError(t, err)
+	record, err = reopened.Get(1, testOpset)
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, want, *record)
+
+	record, err = reopened.Get(2, testOpset)
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, uint32(7), record.ReferenceTimestamp)
+}
+
+func TestTableTimestamp(t *testing.T) {
+	assert.Equal(t, uint32(0), tableTimestamp(0, 0), "never received")
+	assert.Equal(t, uint32(200), tableTimestamp(200, 0), "latest")
+	assert.Equal(t, uint32(150), tableTimestamp(200, 150), "historical")
+	assert.Equal(t, uint32(200), tableTimestamp(200, 200))
+	assert.Equal(t, uint32(0), tableTimestamp(100, 150), "latest is older")
+}
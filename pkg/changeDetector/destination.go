@@ -0,0 +1,253 @@
+package changeDetector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IBN254CertificateVerifier"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IECDSACertificateVerifier"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IOperatorTableUpdater"
+	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/policy"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DestinationReader reads the operator table a destination chain currently holds.
+type DestinationReader interface {
+	// ReadTable returns the record of the destination's latest table for the operator set,
+	// or nil if the destination has never received one
+	ReadTable(ctx context.Context, dest policy.Destination, opset distribution.OperatorSet, curveType operatorTable.CurveType) (*Record, error)
+}
+
+// ChainReader is a DestinationReader that rebuilds the destination's latest table from the
+// curve's certificate verifier: the operator set owner and max staleness period, and the
+// BN254 operator set info or ECDSA operator infos at the latest reference timestamp. The
+// table is re-encoded the way the CrossChainRegistry encodes it, so its leaf hash can be
+// compared with a freshly calculated table.
+type ChainReader struct {
+	chainManager chainManager.IChainManager
+}
+
+// NewChainReader creates a ChainReader.
+//
+// Parameters:
+//   - cm: The chain manager holding a client for every destination chain
+//
+// Returns:
+//   - *ChainReader: The reader
+func NewChainReader(cm chainManager.IChainManager) *ChainReader {
+	return &ChainReader{chainManager: cm}
+}
+
+// ReadTable implements DestinationReader.
+func (r *ChainReader) ReadTable(
+	ctx context.Context,
+	dest policy.Destination,
+	opset distribution.OperatorSet,
+	curveType operatorTable.CurveType,
+) (*Record, error) {
+	table, referenceTimestamp, err := r.readTable(ctx, dest, opset, curveType, 0)
+	if err != nil || table == nil {
+		return nil, err
+	}
+	tableBytes, err := operatorTable.Encode(table)
+	if err != nil {
+		return nil, err
+	}
+	return &Record{
+		LeafHash:           LeafHash(tableBytes),
+		ReferenceTimestamp: referenceTimestamp,
+	}, nil
+}
+
+// ReadTableAt rebuilds the table the destination stored at exactly referenceTimestamp rather
+// than its latest one. Certificate verifiers only store operator data for the reference
+// timestamps a table was sent with, so an operator set whose table was unchanged at
+// referenceTimestamp, and therefore not sent, has no table there. The operator set owner and
+// max staleness period are current values, as the verifiers do not keep their history.
+//
+// Parameters:
+//   - ctx: Context for the destination reads
+//   - dest: The destination chain
+//   - opset: The operator set
+//   - curveType: The operator set's key type
+//   - referenceTimestamp: The reference timestamp to read the table at
+//
+// Returns:
+//   - *operatorTable.OperatorTable: The table sent at referenceTimestamp, or nil if none was
+//   - error: An error if the destination cannot be read
+func (r *ChainReader) ReadTableAt(
+	ctx context.Context,
+	dest policy.Destination,
+	opset distribution.OperatorSet,
+	curveType operatorTable.CurveType,
+	referenceTimestamp uint32,
+) (*operatorTable.OperatorTable, error) {
+	table, _, err := r.readTable(ctx, dest, opset, curveType, referenceTimestamp)
+	return table, err
+}
+
+// readTable rebuilds the destination's table at the given reference timestamp, or at its
+// latest reference timestamp when at is 0. It returns a nil table when there is none.
+func (r *ChainReader) readTable(
+	ctx context.Context,
+	dest policy.Destination,
+	opset distribution.OperatorSet,
+	curveType operatorTable.CurveType,
+	at uint32,
+) (*operatorTable.OperatorTable, uint32, error) {
+	if curveType != operatorTable.CurveTypeBN254 && curveType != operatorTable.CurveTypeECDSA {
+		return nil, 0, fmt.Errorf("unsupported curve type %s", curveType)
+	}
+	chain, err := r.chainManager.GetChainForId(dest.ChainId)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get chain for ID %d: %w", dest.ChainId, err)
+	}
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	updater, err := IOperatorTableUpdater.NewIOperatorTableUpdaterCaller(dest.OperatorTableUpdater, chain.RPCClient)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to bind OperatorTableUpdater: %w", err)
+	}
+	verifierAddress, err := updater.GetCertificateVerifier(callOpts, uint8(curveType))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get %s certificate verifier: %w", curveType, err)
+	}
+
+	table := &operatorTable.OperatorTable{
+		OperatorSet: operatorTable.OperatorSet{Avs: opset.Avs, Id: opset.Id},
+		CurveType:   curveType,
+	}
+	var referenceTimestamp uint32
+	switch curveType {
+	case operatorTable.CurveTypeBN254:
+		referenceTimestamp, err = readBN254Table(callOpts, verifierAddress, chain.RPCClient, table, at)
+	case operatorTable.CurveTypeECDSA:
+		referenceTimestamp, err = readECDSATable(callOpts, verifierAddress, chain.RPCClient, table, at)
+	}
+	if err != nil || referenceTimestamp == 0 {
+		return nil, 0, err
+	}
+	return table, referenceTimestamp, nil
+}
+
+// tableTimestamp picks the reference timestamp to read a table at: at when it is set and the
+// destination's latest table is not older, the latest otherwise. It returns 0 when there is no
+// such table.
+func tableTimestamp(latest uint32, at uint32) uint32 {
+	if at == 0 {
+		return latest
+	}
+	if latest < at {
+		return 0
+	}
+	return at
+}
+
+func readBN254Table(
+	callOpts *bind.CallOpts,
+	verifierAddress common.Address,
+	client bind.ContractCaller,
+	table *operatorTable.OperatorTable,
+	at uint32,
+) (uint32, error) {
+	verifier, err := IBN254CertificateVerifier.NewIBN254CertificateVerifierCaller(verifierAddress, client)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bind BN254CertificateVerifier: %w", err)
+	}
+	opset := IBN254CertificateVerifier.OperatorSet{Avs: table.OperatorSet.Avs, Id: table.OperatorSet.Id}
+
+	latest, err := verifier.LatestReferenceTimestamp(callOpts, opset)
+	if err != nil {
+		return 0, wrapReadError("latest reference timestamp", err)
+	}
+	referenceTimestamp := tableTimestamp(latest, at)
+	if referenceTimestamp == 0 {
+		return 0, nil
+	}
+	info, err := verifier.GetOperatorSetInfo(callOpts, opset, referenceTimestamp)
+	if err != nil {
+		return 0, wrapReadError("BN254 operator set info", err)
+	}
+	if at != 0 && info.NumOperators.Sign() == 0 && info.OperatorInfoTreeRoot == ([32]byte{}) && len(info.TotalWeights) == 0 {
+		// Nothing was stored at this timestamp
+		return 0, nil
+	}
+	if table.Config.Owner, err = verifier.GetOperatorSetOwner(callOpts, opset); err != nil {
+		return 0, wrapReadError("operator set owner", err)
+	}
+	if table.Config.MaxStalenessPeriod, err = verifier.MaxOperatorTableStaleness(callOpts, opset); err != nil {
+		return 0, wrapReadError("max operator table staleness", err)
+	}
+	table.BN254 = &operatorTable.BN254OperatorSetInfo{
+		OperatorInfoTreeRoot: info.OperatorInfoTreeRoot,
+		NumOperators:         info.NumOperators,
+		AggregatePubkey:      operatorTable.G1Point{X: info.AggregatePubkey.X, Y: info.AggregatePubkey.Y},
+		TotalWeights:         info.TotalWeights,
+	}
+	return referenceTimestamp, nil
+}
+
+func readECDSATable(
+	callOpts *bind.CallOpts,
+	verifierAddress common.Address,
+	client bind.ContractCaller,
+	table *operatorTable.OperatorTable,
+	at uint32,
+) (uint32, error) {
+	verifier, err := IECDSACertificateVerifier.NewIECDSACertificateVerifierCaller(verifierAddress, client)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bind ECDSACertificateVerifier: %w", err)
+	}
+	opset := IECDSACertificateVerifier.OperatorSet{Avs: table.OperatorSet.Avs, Id: table.OperatorSet.Id}
+
+	latest, err := verifier.LatestReferenceTimestamp(callOpts, opset)
+	if err != nil {
+		return 0, wrapReadError("latest reference timestamp", err)
+	}
+	referenceTimestamp := tableTimestamp(latest, at)
+	if referenceTimestamp == 0 {
+		return 0, nil
+	}
+	infos, err := verifier.GetOperatorInfos(callOpts, opset, referenceTimestamp)
+	if err != nil {
+		return 0, wrapReadError("ECDSA operator infos", err)
+	}
+	if at != 0 && len(infos) == 0 {
+		// Nothing was stored at this timestamp
+		return 0, nil
+	}
+	if table.Config.Owner, err = verifier.GetOperatorSetOwner(callOpts, opset); err != nil {
+		return 0, wrapReadError("operator set owner", err)
+	}
+	if table.Config.MaxStalenessPeriod, err = verifier.MaxOperatorTableStaleness(callOpts, opset); err != nil {
+		return 0, wrapReadError("max operator table staleness", err)
+	}
+	table.ECDSA = make([]operatorTable.ECDSAOperatorInfo, len(infos))
+	for i, info := range infos {
+		table.ECDSA[i] = operatorTable.ECDSAOperatorInfo{Pubkey: info.Pubkey, Weights: info.Weights}
+	}
+	return referenceTimestamp, nil
+}
+
+func wrapReadError(what string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("failed to read %s: %w", what, err)
+}
+
+// LeafHash returns the keccak256 hash of an operator table's salted leaf, which is what the
+// global table root commits to for the operator set.
+//
+// Parameters:
+//   - tableBytes: The operator table bytes
+//
+// Returns:
+//   - common.Hash: The leaf hash
+func LeafHash(tableBytes []byte) common.Hash {
+	return distribution.OperatorTableLeafHash(tableBytes)
+}
@@ -0,0 +1,142 @@
+package changeDetector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Record describes the operator table a destination chain last received for an operator set.
+type Record struct {
+	// LeafHash is the keccak256 hash of the table's salted leaf (see distribution.EncodeOperatorTableLeaf)
+	LeafHash common.Hash `json:"leafHash"`
+	// ReferenceTimestamp is the reference timestamp the table was transported with
+	ReferenceTimestamp uint32 `json:"referenceTimestamp"`
+}
+
+// Store persists the tables transported to each destination chain.
+type Store interface {
+	// Get returns the last record for the operator set on the chain, or nil if there is none
+	Get(chainId uint64, opset distribution.OperatorSet) (*Record, error)
+	// Put replaces the record for the operator set on the chain
+	Put(chainId uint64, opset distribution.OperatorSet, record Record) error
+}
+
+type storeKey struct {
+	chainId uint64
+	opset   distribution.OperatorSet
+}
+
+type storeEntry struct {
+	ChainId uint64         `json:"chainId"`
+	Avs     common.Address `json:"avs"`
+	Id      uint32         `json:"id"`
+	Record
+}
+
+// FileStore is a Store backed by a JSON file. Every Put rewrites the file atomically.
+// It is safe for concurrent use within a process.
+type FileStore struct {
+	path string
+
+	mu      sync.Mutex
+	records map[storeKey]Record
+}
+
+// NewFileStore opens the store at path, creating it on the first Put if it does not exist.
+//
+// Parameters:
+//   - path: Path to the JSON store file
+//
+// Returns:
+//   - *FileStore: The store
+//   - error: An error if an existing file cannot be read or parsed
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		path:    path,
+		records: make(map[storeKey]Record),
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read table store %s: %w", path, err)
+	}
+	var entries []storeEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse table store %s: %w", path, err)
+	}
+	for _, e := range entries {
+		s.records[storeKey{chainId: e.ChainId, opset: distribution.OperatorSet{Id: e.Id, Avs: e.Avs}}] = e.Record
+	}
+	return s, nil
+}
+
+// Get implements Store.
+func (s *FileStore) Get(chainId uint64, opset distribution.OperatorSet) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[storeKey{chainId: chainId, opset: opset}]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+// Put implements Store.
+func (s *FileStore) Put(chainId uint64, opset distribution.OperatorSet, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[storeKey{chainId: chainId, opset: opset}] = record
+	return s.flush()
+}
+
+func (s *FileStore) flush() error {
+	entries := make([]storeEntry, 0, len(s.records))
+	for key, record := range s.records {
+		entries = append(entries, storeEntry{
+			ChainId: key.chainId,
+			Avs:     key.opset.Avs,
+			Id:      key.opset.Id,
+			Record:  record,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].ChainId != entries[j].ChainId {
+			return entries[i].ChainId < entries[j].ChainId
+		}
+		if entries[i].Avs != entries[j].Avs {
+			return entries[i].Avs.Cmp(entries[j].Avs) < 0
+		}
+		return entries[i].Id < entries[j].Id
+	})
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode table store: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write table store %s: %w", s.path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write table store %s: %w", s.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write table store %s: %w", s.path, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write table store %s: %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package distribution
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ArtifactVersion is the current version of the distribution artifact format.
+// Readers reject artifacts with a different version.
+const ArtifactVersion = 1
+
+// Artifact is a self-contained, serializable snapshot of a calculated distribution and
+// its Merkle tree. It carries everything needed to re-transport any operator set's
+// table for the root it was calculated against, without recalculating.
+type Artifact struct {
+	// Version is the artifact format version
+	Version int `json:"version"`
+	// BlockNumber is the reference block the distribution was calculated at
+	BlockNumber uint64 `json:"blockNumber"`
+	// BlockHash is the hash of the reference block
+	BlockHash common.Hash `json:"blockHash"`
+	// ReferenceTimestamp is the reference timestamp the root was confirmed with
+	ReferenceTimestamp uint32 `json:"referenceTimestamp"`
+	// Root is the global table root
+	Root common.Hash `json:"root"`
+	// OperatorSets are the operator sets in leaf order
+	OperatorSets []ArtifactOperatorSet `json:"operatorSets"`
+}
+
+// ArtifactOperatorSet is a single operator set entry in an Artifact.
+type ArtifactOperatorSet struct {
+	// Id is the operator set ID
+	Id uint32 `json:"id"`
+	// Avs is the address of the AVS owning the operator set
+	Avs common.Address `json:"avs"`
+	// TableBytes are the operator table bytes returned by the calculator
+	TableBytes hexutil.Bytes `json:"tableBytes"`
+	// Leaf is the salted leaf data (see EncodeOperatorTableLeaf)
+	Leaf hexutil.Bytes `json:"leaf"`
+}
+
+// NewArtifact builds an artifact from a calculated distribution.
+//
+// Parameters:
+//   - blockNumber: The reference block number
+//   - blockHash: The reference block hash
+//   - referenceTimestamp: The reference timestamp
+//   - root: The global table root calculated from the distribution
+//   - dist: The distribution to serialize
+//
+// Returns:
+//   - *Artifact: The artifact
+//   - error: An error if an operator set in the distribution has no table data
+func NewArtifact(blockNumber uint64, blockHash common.Hash, referenceTimestamp uint32, root [32]byte, dist *Distribution) (*Artifact, error) {
+	distEntries := dist.Entries()
+	entries := make([]ArtifactOperatorSet, 0, len(distEntries))
+	for _, entry := range distEntries {
+		opset := entry.OperatorSet
+		if entry.TableData == nil {
+			return nil, fmt.Errorf("operator set %s with ID %d has no table data", opset.Avs.String(), opset.Id)
+		}
+		entries = append(entries, ArtifactOperatorSet{
+			Id:         opset.Id,
+			Avs:        opset.Avs,
+			TableBytes: entry.TableData,
+			Leaf:       EncodeOperatorTableLeaf(entry.TableData),
+		})
+	}
+	return &Artifact{
+		Version:            ArtifactVersion,
+		BlockNumber:        blockNumber,
+		BlockHash:          blockHash,
+		ReferenceTimestamp: referenceTimestamp,
+		Root:               root,
+		OperatorSets:       entries,
+	}, nil
+}
+
+// Distribution reconstructs the distribution described by the artifact.
+//
+// Returns:
+//   - *Distribution: The distribution with indices and table data restored
+//   - error: An error if the artifact is invalid
+func (a *Artifact) Distribution() (*Distribution, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	opsets := make([]OperatorSet, len(a.OperatorSets))
+	for i, entry := range a.OperatorSets {
+		opsets[i] = OperatorSet{Id: entry.Id, Avs: entry.Avs}
+	}
+	dist := NewDistributionWithOperatorSets(opsets)
+	for i, entry := range a.OperatorSets {
+		if err := dist.SetTableData(opsets[i], entry.TableBytes); err != nil {
+			return nil, err
+		}
+	}
+	return dist, nil
+}
+
+// Tree rebuilds the Merkle tree from the artifact's leaves and checks it against the stored root.
+//
+// Returns:
+//   - *MerkleTree: The rebuilt tree
+//   - error: An error if the artifact is invalid or the rebuilt root differs
+func (a *Artifact) Tree() (*MerkleTree, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	leaves := make([][]byte, len(a.OperatorSets))
+	for i, entry := range a.OperatorSets {
+		leaves[i] = entry.Leaf
+	}
+	tree, err := NewMerkleTree(leaves)
+	if err != nil {
+		return nil, err
+	}
+	if tree.Root() != a.Root {
+		return nil, fmt.Errorf("artifact root %s does not match rebuilt root %s", a.Root.Hex(), tree.Root().Hex())
+	}
+	return tree, nil
+}
+
+// Snapshot returns the reference block the artifact was calculated at.
+func (a *Artifact) Snapshot() *snapshot.Snapshot {
+	return &snapshot.Snapshot{
+		Number:    a.BlockNumber,
+		Hash:      a.BlockHash,
+		Timestamp: uint64(a.ReferenceTimestamp),
+	}
+}
+
+// Validate checks the artifact version and that every leaf matches its table bytes.
+//
+// Returns:
+//   - error: An error describing the first inconsistency, or nil
+func (a *Artifact) Validate() error {
+	if a.Version != ArtifactVersion {
+		return fmt.Errorf("unsupported artifact version %d (expected %d)", a.Version, ArtifactVersion)
+	}
+	if len(a.OperatorSets) == 0 {
+		return fmt.Errorf("artifact contains no operator sets")
+	}
+	seen := make(map[OperatorSet]struct{}, len(a.OperatorSets))
+	for i, entry := range a.OperatorSets {
+		opset := OperatorSet{Id: entry.Id, Avs: entry.Avs}
+		if _, dup := seen[opset]; dup {
+			return fmt.Errorf("operator set %s with ID %d appears more than once", opset.Avs.String(), opset.Id)
+		}
+		seen[opset] = struct{}{}
+		if !bytes.Equal(entry.Leaf, EncodeOperatorTableLeaf(entry.TableBytes)) {
+			return fmt.Errorf("leaf %d does not match the encoded table bytes", i)
+		}
+	}
+	return nil
+}
+
+// WriteArtifact writes the artifact as indented JSON to path.
+//
+// Parameters:
+//   - path: The file to write
+//   - a: The artifact to write
+//
+// Returns:
+//   - error: An error if encoding or writing fails
+func WriteArtifact(path string, a *Artifact) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode artifact: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write artifact to %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadArtifact reads and validates a JSON artifact from path.
+//
+// Parameters:
+//   - path: The file to read
+//
+// Returns:
+//   - *Artifact: The decoded artifact
+//   - error: An error if the file cannot be read, decoded, or is invalid
+func ReadArtifact(path string) (*Artifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact from %s: %w", path, err)
+	}
+	var a Artifact
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("failed to decode artifact: %w", err)
+	}
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
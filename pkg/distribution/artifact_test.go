@@ -0,0 +1,121 @@
+package distribution
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDistribution(t *testing.T) (*Distribution, [32]byte) {
+	opsets := []OperatorSet{
+		{Id: 1, Avs: common.HexToAddress("0x1111111111111111111111111111111111111111")},
+		{Id: 2, Avs: common.HexToAddress("0x2222222222222222222222222222222222222222")},
+		{Id: 0, Avs: common.HexToAddress("0x3333333333333333333333333333333333333333")},
+	}
+	dist := NewDistributionWithOperatorSets(opsets)
+	leaves := make([][]byte, len(opsets))
+	for i, opset := range opsets {
+		data := []byte{byte(i + 1), 0xaa, 0xbb}
+		require.NoError(t, dist.SetTableData(opset, data))
+		leaves[i] = EncodeOperatorTableLeaf(data)
+	}
+	tree, err := NewMerkleTree(leaves)
+	require.NoError(t, err)
+	return dist, [32]byte(tree.Root())
+}
+
+func TestArtifact_RoundTrip(t *testing.T) {
+	dist, root := newTestDistribution(t)
+	blockHash := common.HexToHash("0xabc")
+
+	artifact, err := NewArtifact(100, blockHash, 1700000000, root, dist)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "dist.json")
+	require.NoError(t, WriteArtifact(path, artifact))
+
+	loaded, err := ReadArtifact(path)
+	require.NoError(t, err)
+	assert.Equal(t, artifact, loaded)
+	assert.Equal(t, uint64(100), loaded.BlockNumber)
+	assert.Equal(t, blockHash, loaded.BlockHash)
+	assert.Equal(t, uint32(1700000000), loaded.ReferenceTimestamp)
+
+	loadedDist, err := loaded.Distribution()
+	require.NoError(t, err)
+	assert.Equal(t, dist.GetOrderedOperatorSets(), loadedDist.GetOrderedOperatorSets())
+	for _, opset := range dist.GetOrderedOperatorSets() {
+		wantIndex, _ := dist.GetTableIndex(opset)
+		gotIndex, ok := loadedDist.GetTableIndex(opset)
+		require.True(t, ok)
+		assert.Equal(t, wantIndex, gotIndex)
+		wantData, _ := dist.GetTableData(opset)
+		gotData, ok := loadedDist.GetTableData(opset)
+		require.True(t, ok)
+		assert.Equal(t, wantData, gotData)
+	}
+
+	tree, err := loaded.Tree()
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash(root), tree.Root())
+}
+
+func TestArtifact_Invalid(t *testing.T) {
+	dist, root := newTestDistribution(t)
+
+	tests := []struct {
+		name    string
+		mutate  func(a *Artifact)
+		wantErr string
+	}{
+		{
+			name:    "unsupported version",
+			mutate:  func(a *Artifact) { a.Version = ArtifactVersion + 1 },
+			wantErr: "unsupported artifact version",
+		},
+		{
+			name:    "tampered table bytes",
+			mutate:  func(a *Artifact) { a.OperatorSets[1].TableBytes = []byte{0xff} },
+			wantErr: "leaf 1 does not match",
+		},
+		{
+			name:    "duplicate operator set",
+			mutate:  func(a *Artifact) { a.OperatorSets[2] = a.OperatorSets[0] },
+			wantErr: "appears more than once",
+		},
+		{
+			name:    "empty",
+			mutate:  func(a *Artifact) { a.OperatorSets = nil },
+			wantErr: "no operator sets",
+		},
+		{
+			name: "reordered leaves",
+			mutate: func(a *Artifact) {
+				a.OperatorSets[0], a.OperatorSets[1] = a.OperatorSets[1], a.OperatorSets[0]
+			},
+			wantErr: "does not match rebuilt root",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifact, err := NewArtifact(100, common.Hash{}, 1, root, dist)
+			require.NoError(t, err)
+			tt.mutate(artifact)
+
+			_, err = artifact.Tree()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestNewArtifact_MissingTableData(t *testing.T) {
+	dist := NewDistributionWithOperatorSets([]OperatorSet{{Id: 1, Avs: common.HexToAddress("0x1")}})
+	_, err := NewArtifact(1, common.Hash{}, 1, [32]byte{}, dist)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has no table data")
+}
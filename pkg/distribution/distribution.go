@@ -4,9 +4,13 @@
 package distribution
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 // OPERATOR_TABLE_LEAF_SALT is the salt used for encoding operator table leaves
@@ -22,26 +26,59 @@ type OperatorSet struct {
 	Avs common.Address
 }
 
+// Entry is an operator set at its leaf index, with its table data.
+type Entry struct {
+	// Index is the operator set's leaf index
+	Index uint64
+	// OperatorSet is the operator set
+	OperatorSet OperatorSet
+	// TableData is the operator set's table bytes, or nil if none have been set
+	TableData []byte
+}
+
 // Distribution manages the organization and storage of operator set data.
 // It maintains both the ordering of operator sets (for Merkle tree construction)
 // and the associated table data for each operator set.
+//
+// A Distribution is safe for concurrent use. Every method that returns several operator sets
+// returns them in leaf index order. Use Snapshot for a consistent view across several reads,
+// such as for the duration of a transport.
 type Distribution struct {
-	// TableIndices maps operator sets to their index positions in ordered structures
-	TableIndices map[OperatorSet]uint64
-	// tableData stores the table bytes for each operator set
+	mu sync.RWMutex
+	t  table
+}
+
+// Snapshot is an immutable copy of a distribution at the time Distribution.Snapshot was
+// called. Later changes to the distribution do not affect it, and it is safe for concurrent use.
+type Snapshot struct {
+	t table
+}
+
+// table holds the operator sets in leaf order and their table data.
+type table struct {
+	// operatorSets are the operator sets in leaf index order
+	operatorSets []OperatorSet
+	// indices maps operator sets to their leaf index
+	indices map[OperatorSet]uint64
+	// tableData stores the table bytes for each operator set. Stored slices are never
+	// modified, only replaced, so snapshots can share them
 	tableData map[OperatorSet][]byte
 }
 
+func newTable() table {
+	return table{
+		indices:   make(map[OperatorSet]uint64),
+		tableData: make(map[OperatorSet][]byte),
+	}
+}
+
 // NewDistribution creates a new empty Distribution instance.
 // The distribution is initialized with empty maps for both indices and data.
 //
 // Returns:
 //   - *Distribution: A new empty distribution instance
 func NewDistribution() *Distribution {
-	return &Distribution{
-		TableIndices: make(map[OperatorSet]uint64),
-		tableData:    make(map[OperatorSet][]byte),
-	}
+	return &Distribution{t: newTable()}
 }
 
 // NewDistributionWithOperatorSets creates a new Distribution with pre-defined operator sets.
@@ -58,34 +95,59 @@ func NewDistributionWithOperatorSets(operatorSets []OperatorSet) *Distribution {
 	return dist
 }
 
-// SetOperatorSets assigns indices to the provided operator sets.
-// Each operator set is assigned an index based on its position in the slice,
-// which determines its position in Merkle tree structures.
+// SetOperatorSets replaces the distribution's operator sets and assigns each the index of
+// its position in the slice, which determines its position in Merkle tree structures.
+// Table data is kept for operator sets that remain and dropped for the others. An operator set
+// listed more than once keeps its first position.
 //
 // Parameters:
 //   - operatorSets: A slice of operator sets to assign indices to
 func (d *Distribution) SetOperatorSets(operatorSets []OperatorSet) {
-	for i, opset := range operatorSets {
-		d.TableIndices[opset] = uint64(i)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	next := newTable()
+	for _, opset := range operatorSets {
+		if _, dup := next.indices[opset]; dup {
+			continue
+		}
+		next.indices[opset] = uint64(len(next.operatorSets))
+		next.operatorSets = append(next.operatorSets, opset)
+		if data, ok := d.t.tableData[opset]; ok {
+			next.tableData[opset] = data
+		}
 	}
+	d.t = next
 }
 
-// GetTableIndex retrieves the index position for a given operator set.
-// This index is used for Merkle tree construction and proof generation.
+// RemoveOperatorSet removes an operator set and its table data. The operator sets after it
+// move down one index.
 //
 // Parameters:
-//   - opset: The operator set to look up
+//   - opset: The operator set to remove
 //
 // Returns:
-//   - uint64: The index position of the operator set
-//   - bool: True if the operator set exists, false otherwise
-func (d *Distribution) GetTableIndex(opset OperatorSet) (uint64, bool) {
-	index, ok := d.TableIndices[opset]
-	return index, ok
+//   - bool: True if the operator set was in the distribution
+func (d *Distribution) RemoveOperatorSet(opset OperatorSet) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	index, ok := d.t.indices[opset]
+	if !ok {
+		return false
+	}
+	d.t.operatorSets = append(d.t.operatorSets[:index:index], d.t.operatorSets[index+1:]...)
+	delete(d.t.indices, opset)
+	delete(d.t.tableData, opset)
+	for i := index; i < uint64(len(d.t.operatorSets)); i++ {
+		d.t.indices[d.t.operatorSets[i]] = i
+	}
+	return true
 }
 
 // SetTableData stores table data for a specific operator set.
 // The operator set must already exist in the distribution before data can be set.
+// The data is copied, so the caller may reuse its buffer.
 //
 // Parameters:
 //   - opset: The operator set to store data for
@@ -94,14 +156,97 @@ func (d *Distribution) GetTableIndex(opset OperatorSet) (uint64, bool) {
 // Returns:
 //   - error: An error if the operator set doesn't exist in the distribution
 func (d *Distribution) SetTableData(opset OperatorSet, data []byte) error {
-	if _, exists := d.TableIndices[opset]; !exists {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, exists := d.t.indices[opset]; !exists {
 		return fmt.Errorf("operator set %s with ID %d does not exist in the distribution", opset.Avs.String(), opset.Id)
 	}
-	d.tableData[opset] = data
+	d.t.tableData[opset] = append([]byte{}, data...)
 	return nil
 }
 
+// DeleteTableData removes the table data of an operator set, which keeps its index.
+//
+// Parameters:
+//   - opset: The operator set whose table data to remove
+//
+// Returns:
+//   - bool: True if the operator set had table data
+func (d *Distribution) DeleteTableData(opset OperatorSet) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, ok := d.t.tableData[opset]
+	delete(d.t.tableData, opset)
+	return ok
+}
+
+// Snapshot returns an immutable copy of the distribution. A nil distribution has an empty
+// snapshot.
+//
+// Returns:
+//   - *Snapshot: The operator sets, indices and table data as they are now
+func (d *Distribution) Snapshot() *Snapshot {
+	if d == nil {
+		return &Snapshot{}
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	c := table{
+		operatorSets: append([]OperatorSet{}, d.t.operatorSets...),
+		indices:      make(map[OperatorSet]uint64, len(d.t.indices)),
+		tableData:    make(map[OperatorSet][]byte, len(d.t.tableData)),
+	}
+	for opset, index := range d.t.indices {
+		c.indices[opset] = index
+	}
+	for opset, data := range d.t.tableData {
+		c.tableData[opset] = data
+	}
+	return &Snapshot{t: c}
+}
+
+// Len returns the number of operator sets in the distribution.
+func (d *Distribution) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.t.operatorSets)
+}
+
+// GetTableIndex retrieves the index position for a given operator set.
+// This index is used for Merkle tree construction and proof generation.
+//
+// Parameters:
+//   - opset: The operator set to look up
+//
+// Returns:
+//   - uint64: The index position of the operator set
+//   - bool: True if the operator set exists, false otherwise
+func (d *Distribution) GetTableIndex(opset OperatorSet) (uint64, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	index, ok := d.t.indices[opset]
+	return index, ok
+}
+
+// GetOperatorSetAt returns the operator set at a leaf index.
+//
+// Parameters:
+//   - index: The leaf index
+//
+// Returns:
+//   - OperatorSet: The operator set at index
+//   - bool: True if index is in range
+func (d *Distribution) GetOperatorSetAt(index uint64) (OperatorSet, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.t.operatorSetAt(index)
+}
+
 // GetTableData retrieves the stored table data for a given operator set.
+// The returned slice must not be modified.
 //
 // Parameters:
 //   - opset: The operator set to retrieve data for
@@ -110,7 +255,9 @@ func (d *Distribution) SetTableData(opset OperatorSet, data []byte) error {
 //   - []byte: The table data bytes for the operator set
 //   - bool: True if data exists for the operator set, false otherwise
 func (d *Distribution) GetTableData(opset OperatorSet) ([]byte, bool) {
-	data, ok := d.tableData[opset]
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	data, ok := d.t.tableData[opset]
 	return data, ok
 }
 
@@ -121,26 +268,286 @@ func (d *Distribution) GetTableData(opset OperatorSet) ([]byte, bool) {
 // Returns:
 //   - []OperatorSet: A slice of operator sets ordered by their indices
 func (d *Distribution) GetOrderedOperatorSets() []OperatorSet {
-	sets := make([]OperatorSet, len(d.TableIndices))
-	// iterate over the map and set the opset at the index stored as the value in the array
-	for opset, index := range d.TableIndices {
-		sets[index] = opset
-	}
-	return sets
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append([]OperatorSet{}, d.t.operatorSets...)
 }
 
-// GetOperatorSets returns all operator sets in the distribution.
-// This method returns operator sets in arbitrary order, suitable for
-// iteration when order doesn't matter.
+// GetOperatorSets returns all operator sets in the distribution, in index order like
+// GetOrderedOperatorSets, so output and transport order are the same on every run.
 //
 // Returns:
 //   - []OperatorSet: A slice of all operator sets in the distribution
 func (d *Distribution) GetOperatorSets() []OperatorSet {
-	sets := make([]OperatorSet, 0, len(d.TableIndices))
-	for opset := range d.TableIndices {
-		sets = append(sets, opset)
+	return d.GetOrderedOperatorSets()
+}
+
+// Entries returns every operator set with its index and table data, in index order.
+//
+// Returns:
+//   - []Entry: The entries, with a nil TableData for operator sets without table data
+func (d *Distribution) Entries() []Entry {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.t.entries()
+}
+
+// Equal reports whether two distributions have the same operator sets at the same indices
+// with the same table data. A nil distribution equals an empty one.
+//
+// Parameters:
+//   - other: The distribution to compare with
+//
+// Returns:
+//   - bool: True if the distributions are equal
+func (d *Distribution) Equal(other *Distribution) bool {
+	return d.Snapshot().Equal(other.Snapshot())
+}
+
+// Diff compares the distribution with a newer one by operator set. A nil distribution is
+// treated as empty.
+//
+// Parameters:
+//   - to: The newer distribution
+//
+// Returns:
+//   - *Diff: The operator sets added, removed, changed and moved between the two
+func (d *Distribution) Diff(to *Distribution) *Diff {
+	return d.Snapshot().Diff(to.Snapshot())
+}
+
+// MarshalJSON encodes the distribution as its operator sets and table data in index order.
+func (d *Distribution) MarshalJSON() ([]byte, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.t.marshalJSON()
+}
+
+// UnmarshalJSON replaces the distribution with one encoded by MarshalJSON.
+func (d *Distribution) UnmarshalJSON(data []byte) error {
+	t, err := unmarshalTable(data)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.t = t
+	return nil
+}
+
+// Len returns the number of operator sets in the snapshot.
+func (s *Snapshot) Len() int {
+	return len(s.t.operatorSets)
+}
+
+// GetTableIndex retrieves the index position for a given operator set.
+//
+// Parameters:
+//   - opset: The operator set to look up
+//
+// Returns:
+//   - uint64: The index position of the operator set
+//   - bool: True if the operator set exists, false otherwise
+func (s *Snapshot) GetTableIndex(opset OperatorSet) (uint64, bool) {
+	index, ok := s.t.indices[opset]
+	return index, ok
+}
+
+// GetOperatorSetAt returns the operator set at a leaf index.
+//
+// Parameters:
+//   - index: The leaf index
+//
+// Returns:
+//   - OperatorSet: The operator set at index
+//   - bool: True if index is in range
+func (s *Snapshot) GetOperatorSetAt(index uint64) (OperatorSet, bool) {
+	return s.t.operatorSetAt(index)
+}
+
+// GetTableData retrieves the stored table data for a given operator set.
+// The returned slice must not be modified.
+//
+// Parameters:
+//   - opset: The operator set to retrieve data for
+//
+// Returns:
+//   - []byte: The table data bytes for the operator set
+//   - bool: True if data exists for the operator set, false otherwise
+func (s *Snapshot) GetTableData(opset OperatorSet) ([]byte, bool) {
+	data, ok := s.t.tableData[opset]
+	return data, ok
+}
+
+// GetOrderedOperatorSets returns operator sets in their index order.
+//
+// Returns:
+//   - []OperatorSet: A slice of operator sets ordered by their indices
+func (s *Snapshot) GetOrderedOperatorSets() []OperatorSet {
+	return append([]OperatorSet{}, s.t.operatorSets...)
+}
+
+// Entries returns every operator set with its index and table data, in index order.
+//
+// Returns:
+//   - []Entry: The entries, with a nil TableData for operator sets without table data
+func (s *Snapshot) Entries() []Entry {
+	return s.t.entries()
+}
+
+// Equal reports whether two snapshots have the same operator sets at the same indices with
+// the same table data. A nil snapshot equals an empty one.
+//
+// Parameters:
+//   - other: The snapshot to compare with
+//
+// Returns:
+//   - bool: True if the snapshots are equal
+func (s *Snapshot) Equal(other *Snapshot) bool {
+	from, to := s.table(), other.table()
+	if len(from.operatorSets) != len(to.operatorSets) {
+		return false
+	}
+	for i, opset := range from.operatorSets {
+		if to.operatorSets[i] != opset {
+			return false
+		}
+		data, ok := from.tableData[opset]
+		otherData, otherOk := to.tableData[opset]
+		if ok != otherOk || !bytes.Equal(data, otherData) {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff compares the snapshot with a newer one by operator set. A nil snapshot is treated as
+// empty.
+//
+// Parameters:
+//   - to: The newer snapshot
+//
+// Returns:
+//   - *Diff: The operator sets added, removed, changed and moved between the two
+func (s *Snapshot) Diff(to *Snapshot) *Diff {
+	diff := &Diff{
+		Added:   []OperatorSet{},
+		Removed: []OperatorSet{},
+		Changed: []OperatorSet{},
+		Moved:   []OperatorSet{},
+	}
+	from, next := s.table(), to.table()
+	for i, opset := range next.operatorSets {
+		fromIndex, ok := from.indices[opset]
+		if !ok {
+			diff.Added = append(diff.Added, opset)
+			continue
+		}
+		if fromIndex != uint64(i) {
+			diff.Moved = append(diff.Moved, opset)
+		}
+		fromData, fromOk := from.tableData[opset]
+		toData, toOk := next.tableData[opset]
+		if fromOk != toOk || !bytes.Equal(fromData, toData) {
+			diff.Changed = append(diff.Changed, opset)
+		}
+	}
+	for _, opset := range from.operatorSets {
+		if _, ok := next.indices[opset]; !ok {
+			diff.Removed = append(diff.Removed, opset)
+		}
+	}
+	return diff
+}
+
+// table returns the snapshot's table, or an empty one for a nil snapshot.
+func (s *Snapshot) table() *table {
+	if s == nil {
+		return &table{}
+	}
+	return &s.t
+}
+
+// MarshalJSON encodes the snapshot in the same format as Distribution.MarshalJSON.
+func (s *Snapshot) MarshalJSON() ([]byte, error) {
+	return s.t.marshalJSON()
+}
+
+// Diff lists how a distribution changed, by operator set.
+type Diff struct {
+	// Added are the operator sets only in the newer distribution, in its index order
+	Added []OperatorSet
+	// Removed are the operator sets only in the older distribution, in its index order
+	Removed []OperatorSet
+	// Changed are the operator sets in both whose table data differs, in the newer index order
+	Changed []OperatorSet
+	// Moved are the operator sets in both whose index differs, in the newer index order
+	Moved []OperatorSet
+}
+
+// IsEmpty reports whether the two distributions are equal.
+func (d *Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && len(d.Moved) == 0
+}
+
+func (t *table) operatorSetAt(index uint64) (OperatorSet, bool) {
+	if index >= uint64(len(t.operatorSets)) {
+		return OperatorSet{}, false
+	}
+	return t.operatorSets[index], true
+}
+
+func (t *table) entries() []Entry {
+	entries := make([]Entry, len(t.operatorSets))
+	for i, opset := range t.operatorSets {
+		entries[i] = Entry{Index: uint64(i), OperatorSet: opset, TableData: t.tableData[opset]}
+	}
+	return entries
+}
+
+// jsonDistribution is the JSON encoding of a distribution.
+type jsonDistribution struct {
+	// OperatorSets are the operator sets in index order
+	OperatorSets []jsonOperatorSet `json:"operatorSets"`
+}
+
+type jsonOperatorSet struct {
+	Id  uint32         `json:"id"`
+	Avs common.Address `json:"avs"`
+	// TableData is omitted for operator sets without table data
+	TableData *hexutil.Bytes `json:"tableData,omitempty"`
+}
+
+func (t *table) marshalJSON() ([]byte, error) {
+	encoded := jsonDistribution{OperatorSets: make([]jsonOperatorSet, len(t.operatorSets))}
+	for i, opset := range t.operatorSets {
+		encoded.OperatorSets[i] = jsonOperatorSet{Id: opset.Id, Avs: opset.Avs}
+		if data, ok := t.tableData[opset]; ok {
+			tableData := hexutil.Bytes(data)
+			encoded.OperatorSets[i].TableData = &tableData
+		}
+	}
+	return json.Marshal(encoded)
+}
+
+func unmarshalTable(data []byte) (table, error) {
+	var decoded jsonDistribution
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return table{}, fmt.Errorf("failed to decode distribution: %w", err)
+	}
+	t := newTable()
+	for _, entry := range decoded.OperatorSets {
+		opset := OperatorSet{Id: entry.Id, Avs: entry.Avs}
+		if _, dup := t.indices[opset]; dup {
+			return table{}, fmt.Errorf("operator set %s with ID %d appears more than once", opset.Avs.String(), opset.Id)
+		}
+		t.indices[opset] = uint64(len(t.operatorSets))
+		t.operatorSets = append(t.operatorSets, opset)
+		if entry.TableData != nil {
+			t.tableData[opset] = append([]byte{}, *entry.TableData...)
+		}
 	}
-	return sets
+	return t, nil
 }
 
 // EncodeOperatorTableLeaf encodes an operator table leaf for merkleization.
@@ -0,0 +1,229 @@
+package distribution
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testOperatorSets(n int) []OperatorSet {
+	opsets := make([]OperatorSet, n)
+	for i := range opsets {
+		// Ids descend so map order and index order are unlikely to agree by chance
+		opsets[i] = OperatorSet{Id: uint32(n - i), Avs: common.BigToAddress(common.Big1)}
+	}
+	return opsets
+}
+
+func TestDistribution_Ordering(t *testing.T) {
+	opsets := testOperatorSets(32)
+	dist := NewDistributionWithOperatorSets(opsets)
+
+	assert.Equal(t, 32, dist.Len())
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, opsets, dist.GetOperatorSets())
+		assert.Equal(t, opsets, dist.GetOrderedOperatorSets())
+	}
+	for i, opset := range opsets {
+		index, ok := dist.GetTableIndex(opset)
+		assert.True(t, ok)
+		assert.Equal(t, uint64(i), index)
+		at, ok := dist.GetOperatorSetAt(uint64(i))
+		assert.True(t, ok)
+		assert.Equal(t, opset, at)
+	}
+	_, ok := dist.GetOperatorSetAt(32)
+	assert.False(t, ok)
+
+	// Duplicates keep their first index
+	dist = NewDistributionWithOperatorSets(append(opsets[:2:2], opsets[0], opsets[2]))
+	assert.Equal(t, opsets[:3], dist.GetOrderedOperatorSets())
+}
+
+func TestDistribution_SetOperatorSetsKeepsTableData(t *testing.T) {
+	opsets := testOperatorSets(3)
+	dist := NewDistributionWithOperatorSets(opsets)
+	for i, opset := range opsets {
+		require.NoError(t, dist.SetTableData(opset, []byte{byte(i)}))
+	}
+
+	dist.SetOperatorSets([]OperatorSet{opsets[2], opsets[0], {Id: 99}})
+	data, ok := dist.GetTableData(opsets[2])
+	assert.True(t, ok)
+	assert.Equal(t, []byte{2}, data)
+	_, ok = dist.GetTableData(opsets[1])
+	assert.False(t, ok, "data of removed operator sets is dropped")
+	_, ok = dist.GetTableData(OperatorSet{Id: 99})
+	assert.False(t, ok)
+
+	assert.Error(t, dist.SetTableData(opsets[1], []byte{1}))
+}
+
+func TestDistribution_RemoveAndDelete(t *testing.T) {
+	opsets := testOperatorSets(4)
+	dist := NewDistributionWithOperatorSets(opsets)
+	for i, opset := range opsets {
+		require.NoError(t, dist.SetTableData(opset, []byte{byte(i)}))
+	}
+
+	assert.True(t, dist.DeleteTableData(opsets[0]))
+	assert.False(t, dist.DeleteTableData(opsets[0]))
+	_, ok := dist.GetTableData(opsets[0])
+	assert.False(t, ok)
+	index, ok := dist.GetTableIndex(opsets[0])
+	assert.True(t, ok, "deleting table data keeps the index")
+	assert.Equal(t, uint64(0), index)
+
+	assert.True(t, dist.RemoveOperatorSet(opsets[1]))
+	assert.False(t, dist.RemoveOperatorSet(opsets[1]))
+	assert.Equal(t, []OperatorSet{opsets[0], opsets[2], opsets[3]}, dist.GetOrderedOperatorSets())
+	for i, opset := range dist.GetOrderedOperatorSets() {
+		index, ok := dist.GetTableIndex(opset)
+		assert.True(t, ok)
+		assert.Equal(t, uint64(i), index)
+	}
+	data, _ := dist.GetTableData(opsets[3])
+	assert.Equal(t, []byte{3}, data)
+}
+
+func TestDistribution_Snapshot(t *testing.T) {
+	opsets := testOperatorSets(3)
+	dist := NewDistributionWithOperatorSets(opsets)
+	buf := []byte{1, 2, 3}
+	require.NoError(t, dist.SetTableData(opsets[0], buf))
+	buf[0] = 0xff
+
+	snapshot := dist.Snapshot()
+	require.NoError(t, dist.SetTableData(opsets[0], []byte{9}))
+	require.NoError(t, dist.SetTableData(opsets[1], []byte{8}))
+	dist.RemoveOperatorSet(opsets[2])
+
+	assert.Equal(t, 3, snapshot.Len())
+	assert.Equal(t, opsets, snapshot.GetOrderedOperatorSets())
+	data, ok := snapshot.GetTableData(opsets[0])
+	assert.True(t, ok)
+	assert.Equal(t, []byte{1, 2, 3}, data, "table data is copied on write")
+	_, ok = snapshot.GetTableData(opsets[1])
+	assert.False(t, ok)
+	assert.Equal(t, []Entry{
+		{Index: 0, OperatorSet: opsets[0], TableData: []byte{1, 2, 3}},
+		{Index: 1, OperatorSet: opsets[1]},
+		{Index: 2, OperatorSet: opsets[2]},
+	}, snapshot.Entries())
+}
+
+func TestDistribution_ConcurrentAccess(t *testing.T) {
+	opsets := testOperatorSets(16)
+	dist := NewDistributionWithOperatorSets(opsets)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				opset := opsets[(i+w)%len(opsets)]
+				_ = dist.SetTableData(opset, []byte{byte(i)})
+				if i%50 == 0 {
+					dist.DeleteTableData(opset)
+					dist.SetOperatorSets(opsets)
+				}
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				snapshot := dist.Snapshot()
+				for j, entry := range snapshot.Entries() {
+					assert.Equal(t, uint64(j), entry.Index)
+				}
+				_, _ = dist.MarshalJSON()
+				_ = dist.Equal(dist)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, opsets, dist.GetOrderedOperatorSets())
+}
+
+func TestDistribution_EqualAndDiff(t *testing.T) {
+	opsets := testOperatorSets(4)
+	from := NewDistributionWithOperatorSets(opsets[:3])
+	to := NewDistributionWithOperatorSets([]OperatorSet{opsets[1], opsets[0], opsets[3]})
+	for i, opset := range opsets[:3] {
+		require.NoError(t, from.SetTableData(opset, []byte{byte(i)}))
+	}
+	require.NoError(t, to.SetTableData(opsets[0], []byte{0}))
+	require.NoError(t, to.SetTableData(opsets[1], []byte{0xff}))
+	require.NoError(t, to.SetTableData(opsets[3], []byte{3}))
+
+	assert.True(t, from.Equal(from))
+	assert.False(t, from.Equal(to))
+	assert.True(t, from.Diff(from).IsEmpty())
+
+	assert.Equal(t, &Diff{
+		Added:   []OperatorSet{opsets[3]},
+		Removed: []OperatorSet{opsets[2]},
+		Changed: []OperatorSet{opsets[1]},
+		Moved:   []OperatorSet{opsets[1], opsets[0]},
+	}, from.Diff(to))
+
+	// Missing table data differs from empty table data
+	a := NewDistributionWithOperatorSets(opsets[:1])
+	b := NewDistributionWithOperatorSets(opsets[:1])
+	require.NoError(t, b.SetTableData(opsets[0], nil))
+	assert.False(t, a.Equal(b))
+	assert.Equal(t, []OperatorSet{opsets[0]}, a.Diff(b).Changed)
+}
+
+func TestDistribution_EqualAndDiffNil(t *testing.T) {
+	opsets := testOperatorSets(2)
+	dist := NewDistributionWithOperatorSets(opsets)
+	var nilDist *Distribution
+	var nilSnapshot *Snapshot
+
+	assert.True(t, nilDist.Equal(nil))
+	assert.True(t, nilDist.Equal(NewDistribution()))
+	assert.True(t, NewDistribution().Equal(nilDist))
+	assert.False(t, nilDist.Equal(dist))
+	assert.False(t, dist.Equal(nil))
+	assert.True(t, nilSnapshot.Equal(NewDistribution().Snapshot()))
+	assert.False(t, dist.Snapshot().Equal(nilSnapshot))
+
+	assert.Equal(t, opsets, nilDist.Diff(dist).Added)
+	assert.Equal(t, opsets, dist.Diff(nil).Removed)
+	assert.True(t, nilDist.Diff(nil).IsEmpty())
+	assert.Equal(t, opsets, nilSnapshot.Diff(dist.Snapshot()).Added)
+	assert.Equal(t, opsets, dist.Snapshot().Diff(nilSnapshot).Removed)
+}
+
+func TestDistribution_JSON(t *testing.T) {
+	opsets := testOperatorSets(3)
+	dist := NewDistributionWithOperatorSets(opsets)
+	require.NoError(t, dist.SetTableData(opsets[0], []byte{0xab, 0xcd}))
+	require.NoError(t, dist.SetTableData(opsets[2], []byte{}))
+
+	encoded, err := json.Marshal(dist)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"operatorSets":[
+		{"id":3,"avs":"0x0000000000000000000000000000000000000001","tableData":"0xabcd"},
+		{"id":2,"avs":"0x0000000000000000000000000000000000000001"},
+		{"id":1,"avs":"0x0000000000000000000000000000000000000001","tableData":"0x"}
+	]}`, string(encoded))
+
+	snapshotEncoded, err := json.Marshal(dist.Snapshot())
+	require.NoError(t, err)
+	assert.Equal(t, encoded, snapshotEncoded)
+
+	decoded := NewDistribution()
+	require.NoError(t, json.Unmarshal(encoded, decoded))
+	assert.True(t, dist.Equal(decoded))
+
+	err = json.Unmarshal([]byte(`{"operatorSets":[{"id":1,"avs":"0x0000000000000000000000000000000000000001"},{"id":1,"avs":"0x0000000000000000000000000000000000000001"}]}`), decoded)
+	assert.Error(t, err, "duplicate operator sets")
+	assert.True(t, dist.Equal(decoded), "a failed decode leaves the distribution unchanged")
+}
@@ -0,0 +1,187 @@
+package distribution
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrNoLeaves mirrors Merkle.NoLeaves, as a tree needs at least one leaf
+var ErrNoLeaves = errors.New("merkle tree has no leaves")
+
+// MerkleTree is the keccak256 Merkle tree whose root is the global table root. It is built the
+// way Merkle.merkleizeKeccak builds it: each leaf is the keccak256 hash of the salted leaf data
+// (see EncodeOperatorTableLeaf), the leaves are padded with zero hashes to the next power of
+// two, and each node is keccak256(left || right). Proofs are the concatenated siblings from
+// leaf to root, as Merkle.getProofKeccak returns them and OperatorTableUpdater verifies them.
+//
+// Leaves can be updated and appended without rebuilding the tree. A MerkleTree is not safe for
+// concurrent use while it is being updated.
+type MerkleTree struct {
+	// layers[0] are the leaf hashes padded to a power of two and the last layer holds the root
+	layers [][]common.Hash
+	// size is the number of leaves, excluding padding
+	size int
+}
+
+// NewMerkleTree builds the keccak256 Merkle tree used for the global table root
+// from salted operator table leaves.
+//
+// Parameters:
+//   - leaves: The salted leaves in index order (see EncodeOperatorTableLeaf)
+//
+// Returns:
+//   - *MerkleTree: The tree
+//   - error: ErrNoLeaves if there are no leaves
+func NewMerkleTree(leaves [][]byte) (*MerkleTree, error) {
+	if len(leaves) == 0 {
+		return nil, ErrNoLeaves
+	}
+	width := 1
+	for width < len(leaves) {
+		width *= 2
+	}
+	hashes := make([]common.Hash, width)
+	for i, leaf := range leaves {
+		hashes[i] = crypto.Keccak256Hash(leaf)
+	}
+
+	t := &MerkleTree{layers: [][]common.Hash{hashes}, size: len(leaves)}
+	for layer := hashes; len(layer) > 1; {
+		next := make([]common.Hash, len(layer)/2)
+		for i := range next {
+			next[i] = crypto.Keccak256Hash(layer[2*i][:], layer[2*i+1][:])
+		}
+		t.layers = append(t.layers, next)
+		layer = next
+	}
+	return t, nil
+}
+
+// NewMerkleTreeFromDistribution builds the tree over a distribution's operator tables in index order.
+//
+// Parameters:
+//   - dist: The distribution
+//
+// Returns:
+//   - *MerkleTree: The tree
+//   - error: An error if an operator set has no table data, or ErrNoLeaves if there are no operator sets
+func NewMerkleTreeFromDistribution(dist *Distribution) (*MerkleTree, error) {
+	entries := dist.Entries()
+	leaves := make([][]byte, len(entries))
+	for i, entry := range entries {
+		if entry.TableData == nil {
+			return nil, fmt.Errorf("operator set %s with ID %d has no table data", entry.OperatorSet.Avs.String(), entry.OperatorSet.Id)
+		}
+		leaves[i] = EncodeOperatorTableLeaf(entry.TableData)
+	}
+	return NewMerkleTree(leaves)
+}
+
+// Root returns the root of the tree.
+func (t *MerkleTree) Root() common.Hash {
+	return t.layers[len(t.layers)-1][0]
+}
+
+// Len returns the number of leaves in the tree, excluding padding.
+func (t *MerkleTree) Len() int {
+	return t.size
+}
+
+// Proof returns the inclusion proof of the leaf at index, matching Merkle.getProofKeccak. A
+// tree with a single leaf has an empty proof, as its root is the leaf.
+//
+// Parameters:
+//   - index: The leaf index
+//
+// Returns:
+//   - []byte: The concatenated 32-byte siblings from leaf to root
+//   - error: An error if index is out of range
+func (t *MerkleTree) Proof(index uint64) ([]byte, error) {
+	if index >= uint64(t.size) {
+		return nil, fmt.Errorf("leaf index %d out of range for %d leaves", index, t.size)
+	}
+	proof := make([]byte, 0, common.HashLength*(len(t.layers)-1))
+	position := index
+	for _, layer := range t.layers[:len(t.layers)-1] {
+		sibling := layer[position^1]
+		proof = append(proof, sibling[:]...)
+		position /= 2
+	}
+	return proof, nil
+}
+
+// Verify checks a leaf's inclusion proof against the tree's root with VerifyOperatorSetProof.
+//
+// Parameters:
+//   - index: The leaf index
+//   - leaf: The salted leaf (see EncodeOperatorTableLeaf)
+//   - proof: The concatenated 32-byte siblings from leaf to root
+//
+// Returns:
+//   - error: nil if the proof is valid, or the reason the contract would reject it
+func (t *MerkleTree) Verify(index uint64, leaf []byte, proof []byte) error {
+	if index > math.MaxUint32 {
+		return fmt.Errorf("leaf index %d does not fit in uint32", index)
+	}
+	return VerifyOperatorSetProof(t.Root(), uint32(index), crypto.Keccak256Hash(leaf), proof)
+}
+
+// UpdateLeaf replaces the leaf at index and rehashes only the nodes on its path to the root.
+//
+// Parameters:
+//   - index: The leaf index
+//   - leaf: The new salted leaf (see EncodeOperatorTableLeaf)
+//
+// Returns:
+//   - error: An error if index is out of range
+func (t *MerkleTree) UpdateLeaf(index uint64, leaf []byte) error {
+	if index >= uint64(t.size) {
+		return fmt.Errorf("leaf index %d out of range for %d leaves", index, t.size)
+	}
+	t.setLeafHash(int(index), crypto.Keccak256Hash(leaf))
+	return nil
+}
+
+// AppendLeaf adds a leaf after the last one. It fills the next padding leaf, or, when the tree
+// is full, doubles its width by making the current tree the left subtree of a new root whose
+// right subtree is all zero leaves. Either way only the new leaf's path is hashed.
+//
+// Parameters:
+//   - leaf: The salted leaf (see EncodeOperatorTableLeaf)
+//
+// Returns:
+//   - uint64: The index of the new leaf
+func (t *MerkleTree) AppendLeaf(leaf []byte) uint64 {
+	if t.size == len(t.layers[0]) {
+		zero := common.Hash{}
+		for level, layer := range t.layers {
+			padded := make([]common.Hash, 2*len(layer))
+			copy(padded, layer)
+			for i := len(layer); i < len(padded); i++ {
+				padded[i] = zero
+			}
+			t.layers[level] = padded
+			zero = crypto.Keccak256Hash(zero[:], zero[:])
+		}
+		top := t.layers[len(t.layers)-1]
+		t.layers = append(t.layers, []common.Hash{crypto.Keccak256Hash(top[0][:], top[1][:])})
+	}
+	index := t.size
+	t.size++
+	t.setLeafHash(index, crypto.Keccak256Hash(leaf))
+	return uint64(index)
+}
+
+// setLeafHash sets a leaf hash and rehashes its path to the root.
+func (t *MerkleTree) setLeafHash(index int, hash common.Hash) {
+	t.layers[0][index] = hash
+	for level := 1; level < len(t.layers); level++ {
+		index /= 2
+		below := t.layers[level-1]
+		t.layers[level][index] = crypto.Keccak256Hash(below[2*index][:], below[2*index+1][:])
+	}
+}
@@ -0,0 +1,161 @@
+package distribution
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// goldenLeaves returns the salted leaves of n one-byte operator tables 0x00, 0x01, ...
+func goldenLeaves(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		leaves[i] = EncodeOperatorTableLeaf([]byte{byte(i)})
+	}
+	return leaves
+}
+
+// The golden roots and proofs are over leaves keccak256(0x8e || i). testdata/MerkleGoldenVectors.t.sol
+// asserts and logs the same values with the Solidity Merkle.merkleizeKeccak and
+// Merkle.getProofKeccak; regenerate them there if the library changes.
+func TestMerkleTree_GoldenRoots(t *testing.T) {
+	for _, tc := range []struct {
+		n    int
+		root string
+	}{
+		{1, "0xffe07cce5f6aaf5ddd99af560ed930baa958d7ee54a734a55e46012ac336e3f0"},
+		{2, "0x99764c3ed41fdf5d039bb0689c2292a5d0a11011c8214dcd253fb0fd1a72c52f"},
+		{3, "0xd6d2ffffc534352e336257b96388c81fe61e0cbba4d72d535c7acc3850510835"},
+		{4, "0xba00f2be393eb865fa4477258731a2bca10d92d93eb1ffffc864a3d886f7be5a"},
+		{5, "0xa2f059957f24983b0fa2f15d70f0435f944b8292171e49ae9d4d75ef980f2647"},
+		{6, "0x166fb9cfa8b1082baecb1e3405809c605ed445913e39b217d1be616f7d74ed00"},
+		{7, "0x452c342067fe564cc278b91a4a6bfdc2481ce62a0c76142aa2131675bc28b956"},
+		{8, "0x369a5157fa2a5836aff9daead7a85e898a79a0da4f748f5c68cf652c6b9c4488"},
+		{9, "0x40fa68aa9d3507de44a11c1e8e09dcbe2d706f363b12c77b11c0cb266e74d2d6"},
+	} {
+		tree, err := NewMerkleTree(goldenLeaves(tc.n))
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToHash(tc.root), tree.Root(), "%d leaves", tc.n)
+		assert.Equal(t, tc.n, tree.Len())
+	}
+}
+
+func TestMerkleTree_GoldenProofs(t *testing.T) {
+	for _, tc := range []struct {
+		n     int
+		index uint64
+		proof string
+	}{
+		// A single leaf is the root
+		{1, 0, "0x"},
+		// Leaf 2 of 3 is paired with a zero padding leaf
+		{3, 2, "0x0000000000000000000000000000000000000000000000000000000000000000" +
+			"99764c3ed41fdf5d039bb0689c2292a5d0a11011c8214dcd253fb0fd1a72c52f"},
+		{5, 4, "0x0000000000000000000000000000000000000000000000000000000000000000" +
+			"ad3228b676f7d3cd4284a5443f17f1962b36e491b30a40b2405849e597ba5fb5" +
+			"ba00f2be393eb865fa4477258731a2bca10d92d93eb1ffffc864a3d886f7be5a"},
+		{6, 1, "0xffe07cce5f6aaf5ddd99af560ed930baa958d7ee54a734a55e46012ac336e3f0" +
+			"9dc4df13c9c2b52d46598a2153b77c76cb0675b902c3ef6ec53cbbc75934708a" +
+			"90b8b43baeb68f7184ac34e6d49a72a8141d30cf3ce717060b41bb80c0c485b9"},
+	} {
+		leaves := goldenLeaves(tc.n)
+		tree, err := NewMerkleTree(leaves)
+		require.NoError(t, err)
+		proof, err := tree.Proof(tc.index)
+		require.NoError(t, err)
+		assert.Equal(t, hexutil.MustDecode(tc.proof), proof, "leaf %d of %d", tc.index, tc.n)
+		assert.NoError(t, tree.Verify(tc.index, leaves[tc.index], proof))
+	}
+}
+
+func TestMerkleTree_ProofsVerify(t *testing.T) {
+	for n := 1; n <= 17; n++ {
+		leaves := goldenLeaves(n)
+		tree, err := NewMerkleTree(leaves)
+		require.NoError(t, err)
+		for i := range leaves {
+			proof, err := tree.Proof(uint64(i))
+			require.NoError(t, err)
+			assert.NoError(t, tree.Verify(uint64(i), leaves[i], proof), "leaf %d of %d", i, n)
+			if n > 1 {
+				assert.Error(t, tree.Verify(uint64(i^1), leaves[i], proof), "wrong index %d of %d", i, n)
+				assert.Error(t, tree.Verify(uint64(i), leaves[(i+1)%n], proof), "wrong leaf %d of %d", i, n)
+			}
+		}
+	}
+}
+
+func TestMerkleTree_UpdateLeaf(t *testing.T) {
+	leaves := goldenLeaves(7)
+	tree, err := NewMerkleTree(leaves)
+	require.NoError(t, err)
+
+	for _, index := range []uint64{0, 3, 6} {
+		leaves[index] = EncodeOperatorTableLeaf([]byte{0xff, byte(index)})
+		require.NoError(t, tree.UpdateLeaf(index, leaves[index]))
+
+		rebuilt, err := NewMerkleTree(leaves)
+		require.NoError(t, err)
+		assert.Equal(t, rebuilt.Root(), tree.Root(), "after updating leaf %d", index)
+		for i := range leaves {
+			proof, err := tree.Proof(uint64(i))
+			require.NoError(t, err)
+			expected, err := rebuilt.Proof(uint64(i))
+			require.NoError(t, err)
+			assert.Equal(t, expected, proof)
+		}
+	}
+
+	assert.Error(t, tree.UpdateLeaf(7, leaves[0]), "padding leaves cannot be updated")
+}
+
+func TestMerkleTree_AppendLeaf(t *testing.T) {
+	leaves := goldenLeaves(17)
+	tree, err := NewMerkleTree(leaves[:1])
+	require.NoError(t, err)
+
+	// Appending fills the padding and doubles the width when the tree is full
+	for n := 2; n <= len(leaves); n++ {
+		assert.Equal(t, uint64(n-1), tree.AppendLeaf(leaves[n-1]))
+
+		rebuilt, err := NewMerkleTree(leaves[:n])
+		require.NoError(t, err)
+		assert.Equal(t, rebuilt.Root(), tree.Root(), "%d leaves", n)
+		assert.Equal(t, n, tree.Len())
+		for i := 0; i < n; i++ {
+			proof, err := tree.Proof(uint64(i))
+			require.NoError(t, err)
+			expected, err := rebuilt.Proof(uint64(i))
+			require.NoError(t, err)
+			assert.Equal(t, expected, proof, "leaf %d of %d", i, n)
+		}
+	}
+}
+
+func TestMerkleTree_Errors(t *testing.T) {
+	_, err := NewMerkleTree(nil)
+	assert.ErrorIs(t, err, ErrNoLeaves)
+
+	tree, err := NewMerkleTree(goldenLeaves(3))
+	require.NoError(t, err)
+	_, err = tree.Proof(3)
+	assert.Error(t, err, "padding leaves have no proof")
+	assert.Error(t, tree.Verify(1<<32, goldenLeaves(1)[0], nil))
+}
+
+func TestNewMerkleTreeFromDistribution(t *testing.T) {
+	dist, root := newTestDistribution(t)
+	tree, err := NewMerkleTreeFromDistribution(dist)
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash(root), tree.Root())
+
+	dist.SetOperatorSets(append(dist.GetOrderedOperatorSets(), OperatorSet{Id: 9}))
+	_, err = NewMerkleTreeFromDistribution(dist)
+	assert.Error(t, err, "operator set without table data")
+
+	_, err = NewMerkleTreeFromDistribution(NewDistribution())
+	assert.ErrorIs(t, err, ErrNoLeaves)
+}
@@ -0,0 +1,51 @@
+package distribution
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Layr-Labs/multichain-go/pkg/merkle"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrInvalidOperatorSetProof mirrors OperatorTableUpdater's InvalidOperatorSetProof revert, for
+// proofs that do not compute the global table root
+var ErrInvalidOperatorSetProof = errors.New("invalid operator set proof")
+
+// OperatorTableLeafHash returns the hash the global table root commits to for an operator
+// table, matching OperatorTableUpdater.calculateOperatorTableLeaf.
+//
+// Parameters:
+//   - operatorTableBytes: The operator table data bytes
+//
+// Returns:
+//   - common.Hash: keccak256(OPERATOR_TABLE_LEAF_SALT || operatorTableBytes)
+func OperatorTableLeafHash(operatorTableBytes []byte) common.Hash {
+	return crypto.Keccak256Hash(EncodeOperatorTableLeaf(operatorTableBytes))
+}
+
+// VerifyOperatorSetProof checks an operator set's inclusion proof against a global table root
+// exactly as OperatorTableUpdater.updateOperatorTable does with Merkle.verifyInclusionKeccak, so
+// a proof that passes here is not rejected on-chain with InvalidOperatorSetProof or one of the
+// Merkle library's reverts.
+//
+// Parameters:
+//   - root: The global table root
+//   - index: The operator set's index in the distribution
+//   - leaf: The operator set's leaf hash (see OperatorTableLeafHash)
+//   - proof: The concatenated 32-byte siblings from leaf to root
+//
+// Returns:
+//   - error: nil if the proof is valid, or an error wrapping merkle.ErrEmptyRoot,
+//     merkle.ErrInvalidProofLength, merkle.ErrInvalidIndex or ErrInvalidOperatorSetProof
+func VerifyOperatorSetProof(root [32]byte, index uint32, leaf [32]byte, proof []byte) error {
+	valid, err := merkle.VerifyInclusionKeccak(proof, root, leaf, uint64(index))
+	if err != nil {
+		return fmt.Errorf("failed to verify proof of operator set index %d: %w", index, err)
+	}
+	if !valid {
+		return fmt.Errorf("%w: index %d does not prove root %s", ErrInvalidOperatorSetProof, index, common.Hash(root).Hex())
+	}
+	return nil
+}
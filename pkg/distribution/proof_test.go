@@ -0,0 +1,123 @@
+package distribution
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/Layr-Labs/multichain-go/pkg/merkle"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	merkletree "github.com/wealdtech/go-merkletree/v2"
+	"github.com/wealdtech/go-merkletree/v2/keccak256"
+)
+
+func TestVerifyOperatorSetProof(t *testing.T) {
+	dist, root := newTestDistribution(t)
+	leaves := make([][]byte, 0, dist.Len())
+	for _, opset := range dist.GetOrderedOperatorSets() {
+		data, _ := dist.GetTableData(opset)
+		leaves = append(leaves, EncodeOperatorTableLeaf(data))
+	}
+	tree, err := NewMerkleTree(leaves)
+	require.NoError(t, err)
+
+	for _, opset := range dist.GetOrderedOperatorSets() {
+		index, _ := dist.GetTableIndex(opset)
+		data, _ := dist.GetTableData(opset)
+		proofBytes, err := tree.Proof(index)
+		require.NoError(t, err)
+
+		require.NoError(t, VerifyOperatorSetProof(root, uint32(index), OperatorTableLeafHash(data), proofBytes))
+
+		err = VerifyOperatorSetProof(root, uint32(index), OperatorTableLeafHash(append(data, 0)), proofBytes)
+		assert.ErrorIs(t, err, ErrInvalidOperatorSetProof)
+		err = VerifyOperatorSetProof(root, uint32(index^1), OperatorTableLeafHash(data), proofBytes)
+		assert.ErrorIs(t, err, ErrInvalidOperatorSetProof)
+		// The padded tree is 4 wide, so index 4 and up do not fit in a 2-level proof
+		err = VerifyOperatorSetProof(root, uint32(index+4), OperatorTableLeafHash(data), proofBytes)
+		assert.ErrorIs(t, err, merkle.ErrInvalidIndex)
+		err = VerifyOperatorSetProof(root, uint32(index), OperatorTableLeafHash(data), proofBytes[:40])
+		assert.ErrorIs(t, err, merkle.ErrInvalidProofLength)
+		err = VerifyOperatorSetProof([32]byte{}, uint32(index), OperatorTableLeafHash(data), proofBytes)
+		assert.ErrorIs(t, err, merkle.ErrEmptyRoot)
+	}
+}
+
+func TestOperatorTableLeafHash(t *testing.T) {
+	// keccak256(abi.encodePacked(uint8(0x8e), hex"0102"))
+	assert.Equal(t,
+		common.BytesToHash(keccak256.New().Hash([]byte{OPERATOR_TABLE_LEAF_SALT, 0x01, 0x02})),
+		OperatorTableLeafHash([]byte{0x01, 0x02}),
+	)
+}
+
+// FuzzVerifyOperatorSetProof checks that MerkleTree and go-merkletree build the same root and
+// proofs for random trees, that those proofs verify with the on-chain algorithm, and that
+// go-merkletree's verifier and the on-chain algorithm reject the same tampered proofs.
+func FuzzVerifyOperatorSetProof(f *testing.F) {
+	for _, seed := range []struct {
+		size  uint16
+		index uint16
+		seed  int64
+	}{
+		{1, 0, 1}, {2, 1, 2}, {3, 2, 3}, {5, 4, 4}, {8, 7, 5}, {17, 16, 6}, {100, 63, 7}, {255, 128, 8},
+	} {
+		f.Add(seed.size, seed.index, seed.seed)
+	}
+
+	f.Fuzz(func(t *testing.T, size uint16, index uint16, seed int64) {
+		n := int(size%512) + 1
+		i := uint64(index) % uint64(n)
+		r := rand.New(rand.NewSource(seed))
+
+		data := make([][]byte, n)
+		leaves := make([][]byte, n)
+		for j := range leaves {
+			data[j] = make([]byte, r.Intn(96))
+			r.Read(data[j])
+			leaves[j] = EncodeOperatorTableLeaf(data[j])
+		}
+		reference, err := merkletree.NewTree(merkletree.WithData(leaves), merkletree.WithHashType(keccak256.New()))
+		require.NoError(t, err)
+		root := [32]byte(reference.Root())
+		proof, err := reference.GenerateProofWithIndex(i, 0)
+		require.NoError(t, err)
+		proofBytes := bytes.Join(proof.Hashes, nil)
+		leaf := OperatorTableLeafHash(data[i])
+
+		tree, err := NewMerkleTree(leaves)
+		require.NoError(t, err)
+		require.Equal(t, common.Hash(root), tree.Root(), "size %d", n)
+		treeProof, err := tree.Proof(i)
+		require.NoError(t, err)
+		require.Equal(t, proofBytes, treeProof, "size %d index %d", n, i)
+
+		require.NoError(t, VerifyOperatorSetProof(root, uint32(i), leaf, proofBytes), "size %d index %d", n, i)
+
+		// Tampered proofs are rejected by both, or accepted by both where the tree allows it,
+		// such as any index with the empty proof of a single-leaf tree
+		agrees := func(proof *merkletree.Proof, proofBytes []byte, leafData []byte) {
+			expected, err := merkletree.VerifyProofUsing(leafData, false, proof, [][]byte{root[:]}, keccak256.New())
+			require.NoError(t, err)
+			err = VerifyOperatorSetProof(root, uint32(proof.Index), OperatorTableLeafHash(leafData[1:]), proofBytes)
+			assert.Equal(t, expected, err == nil, "size %d index %d: go-merkletree %v, on-chain %v", n, proof.Index, expected, err)
+		}
+		agrees(&merkletree.Proof{Hashes: proof.Hashes, Index: i ^ 1}, proofBytes, leaves[i])
+		agrees(proof, proofBytes, append(bytes.Clone(leaves[i]), 0xff))
+		if len(proof.Hashes) > 0 {
+			flipped := bytes.Clone(proofBytes)
+			flipped[r.Intn(len(flipped))] ^= 0x01
+			hashes := make([][]byte, len(proof.Hashes))
+			for j := range hashes {
+				hashes[j] = flipped[j*common.HashLength : (j+1)*common.HashLength]
+			}
+			agrees(&merkletree.Proof{Hashes: hashes, Index: i}, flipped, leaves[i])
+
+			// go-merkletree ignores index bits above the proof's depth; the contract does not
+			err = VerifyOperatorSetProof(root, uint32(i+uint64(1)<<len(proof.Hashes)), leaf, proofBytes)
+			assert.ErrorIs(t, err, merkle.ErrInvalidIndex)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: BUSL-1.1
+pragma solidity ^0.8.27;
+
+// Checks the golden roots and proofs in ../merkleTree_test.go against the Solidity Merkle
+// library and logs them for regeneration. Run from a checkout of eigenlayer-contracts at the
+// version in go.mod:
+//
+//   cp pkg/distribution/testdata/MerkleGoldenVectors.t.sol <eigenlayer-contracts>/src/test/
+//   cd <eigenlayer-contracts> && forge test --match-contract MerkleGoldenVectors -vv
+
+import "forge-std/Test.sol";
+import "src/contracts/libraries/Merkle.sol";
+
+contract MerkleGoldenVectors is Test {
+    uint8 constant OPERATOR_TABLE_LEAF_SALT = 0x8e;
+
+    /// @dev The leaves of n one-byte operator tables 0x00, 0x01, ..., as in goldenLeaves
+    function _leaves(uint256 n) internal pure returns (bytes32[] memory leaves) {
+        leaves = new bytes32[](n);
+        for (uint256 i = 0; i < n; i++) {
+            leaves[i] = keccak256(abi.encodePacked(OPERATOR_TABLE_LEAF_SALT, abi.encodePacked(uint8(i))));
+        }
+    }
+
+    function test_GoldenRoots() public {
+        bytes32[9] memory roots = [
+            bytes32(0xffe07cce5f6aaf5ddd99af560ed930baa958d7ee54a734a55e46012ac336e3f0),
+            bytes32(0x99764c3ed41fdf5d039bb0689c2292a5d0a11011c8214dcd253fb0fd1a72c52f),
+            bytes32(0xd6d2ffffc534352e336257b96388c81fe61e0cbba4d72d535c7acc3850510835),
+            bytes32(0xba00f2be393eb865fa4477258731a2bca10d92d93eb1ffffc864a3d886f7be5a),
+            bytes32(0xa2f059957f24983b0fa2f15d70f0435f944b8292171e49ae9d4d75ef980f2647),
+            bytes32(0x166fb9cfa8b1082baecb1e3405809c605ed445913e39b217d1be616f7d74ed00),
+            bytes32(0x452c342067fe564cc278b91a4a6bfdc2481ce62a0c76142aa2131675bc28b956),
+            bytes32(0x369a5157fa2a5836aff9daead7a85e898a79a0da4f748f5c68cf652c6b9c4488),
+            bytes32(0x40fa68aa9d3507de44a11c1e8e09dcbe2d706f363b12c77b11c0cb266e74d2d6)
+        ];
+        for (uint256 n = 1; n <= roots.length; n++) {
+            bytes32 root = Merkle.merkleizeKeccak(_leaves(n));
+            emit log_named_bytes32(string.concat("root of ", vm.toString(n), " leaves"), root);
+            assertEq(root, roots[n - 1]);
+        }
+    }
+
+    function test_GoldenProofs() public {
+        _checkProof(1, 0, hex"");
+        _checkProof(
+            3,
+            2,
+            hex"0000000000000000000000000000000000000000000000000000000000000000"
+            hex"99764c3ed41fdf5d039bb0689c2292a5d0a11011c8214dcd253fb0fd1a72c52f"
+        );
+        _checkProof(
+            5,
+            4,
+            hex"0000000000000000000000000000000000000000000000000000000000000000"
+            hex"ad3228b676f7d3cd4284a5443f17f1962b36e491b30a40b2405849e597ba5fb5"
+            hex"ba00f2be393eb865fa4477258731a2bca10d92d93eb1ffffc864a3d886f7be5a"
+        );
+        _checkProof(
+            6,
+            1,
+            hex"ffe07cce5f6aaf5ddd99af560ed930baa958d7ee54a734a55e46012ac336e3f0"
+            hex"9dc4df13c9c2b52d46598a2153b77c76cb0675b902c3ef6ec53cbbc75934708a"
+            hex"90b8b43baeb68f7184ac34e6d49a72a8141d30cf3ce717060b41bb80c0c485b9"
+        );
+    }
+
+    function _checkProof(uint256 n, uint256 index, bytes memory expected) internal {
+        bytes32[] memory leaves = _leaves(n);
+        bytes memory proof = Merkle.getProofKeccak(leaves, index);
+        emit log_named_bytes(string.concat("proof of leaf ", vm.toString(index), " of ", vm.toString(n)), proof);
+        assertEq(proof, expected);
+        assertTrue(Merkle.verifyInclusionKeccak(proof, Merkle.merkleizeKeccak(leaves), leaves[index], index));
+    }
+}
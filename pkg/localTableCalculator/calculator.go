@@ -0,0 +1,323 @@
+// Package localTableCalculator calculates operator table bytes in Go instead of through the
+// operator set's on-chain table calculator. It reads the same AllocationManager, KeyRegistrar
+// and CrossChainRegistry state at a pinned reference block and reproduces the standard
+// BN254TableCalculator and ECDSATableCalculator, which weigh each operator by its total
+// minimum slashable stake across the operator set's strategies. The result is byte-identical
+// to CrossChainRegistry.calculateOperatorTableBytes for operator sets using those calculators,
+// so it can cross-check the on-chain result or stand in for an eth_call that runs out of gas
+// (see operatorTableCalculator.Config.LocalFallback).
+//
+// Calculators with custom weighting cannot be detected from their immutables alone; a
+// cross-check against the on-chain result is what catches them.
+package localTableCalculator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/crypto-libs/pkg/bn254"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorInfoTree"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrUnsupportedCalculator is returned for operator sets whose table calculator is not a
+	// standard BN254 or ECDSA table calculator
+	ErrUnsupportedCalculator = errors.New("unsupported operator table calculator")
+	// ErrUnsupportedCurveType is returned for operator sets whose curve type is neither BN254 nor ECDSA
+	ErrUnsupportedCurveType = errors.New("unsupported curve type")
+)
+
+// Config holds the configuration for a Calculator.
+type Config struct {
+	CrossChainRegistryAddress common.Address
+}
+
+// Result is a locally calculated operator table.
+type Result struct {
+	// TableBytes are the operator table bytes, as CrossChainRegistry.calculateOperatorTableBytes returns them
+	TableBytes []byte
+	// Table is the decoded operator table
+	Table *operatorTable.OperatorTable
+	// Calculator is the operator set's table calculator
+	Calculator common.Address
+	// Operators are the operators in the table, in table order
+	Operators []common.Address
+	// BN254OperatorInfos are the operators' leaves in the operator info tree, in table order.
+	// Set for BN254 operator sets only.
+	BN254OperatorInfos []operatorTable.BN254OperatorInfo
+}
+
+// Calculator calculates operator tables from L1 state.
+type Calculator struct {
+	reader ContractReader
+	logger *zap.Logger
+}
+
+// NewCalculator creates a Calculator that reads L1 through the given client.
+//
+// Parameters:
+//   - cfg: The calculator configuration
+//   - ec: The L1 client
+//   - l: Logger
+//
+// Returns:
+//   - *Calculator: The calculator
+//   - error: An error if the CrossChainRegistry cannot be bound
+func NewCalculator(cfg *Config, ec chainManager.EthClientInterface, l *zap.Logger) (*Calculator, error) {
+	reader, err := NewChainReader(cfg.CrossChainRegistryAddress, ec)
+	if err != nil {
+		return nil, err
+	}
+	return NewCalculatorWithReader(reader, l), nil
+}
+
+// NewCalculatorWithReader creates a Calculator with a pre-built contract reader.
+//
+// Parameters:
+//   - reader: The contract reader
+//   - l: Logger
+//
+// Returns:
+//   - *Calculator: The calculator
+func NewCalculatorWithReader(reader ContractReader, l *zap.Logger) *Calculator {
+	return &Calculator{reader: reader, logger: l}
+}
+
+// CalculateOperatorTableBytes calculates the operator set's table bytes at the snapshot.
+//
+// Parameters:
+//   - ctx: Context for the L1 reads
+//   - snap: The reference block to read at
+//   - opset: The operator set
+//
+// Returns:
+//   - []byte: The operator table bytes
+//   - error: An error wrapping ErrUnsupportedCalculator or ErrUnsupportedCurveType if the
+//     operator set cannot be calculated locally, or an error if L1 cannot be read
+func (c *Calculator) CalculateOperatorTableBytes(ctx context.Context, snap *snapshot.Snapshot, opset ICrossChainRegistry.OperatorSet) ([]byte, error) {
+	result, err := c.CalculateOperatorTable(ctx, snap, opset)
+	if err != nil {
+		return nil, err
+	}
+	return result.TableBytes, nil
+}
+
+// CalculateOperatorTable calculates the operator set's table at the snapshot, along with the
+// operators it contains.
+//
+// Parameters:
+//   - ctx: Context for the L1 reads
+//   - snap: The reference block to read at
+//   - opset: The operator set
+//
+// Returns:
+//   - *Result: The table, its bytes and its operators
+//   - error: An error wrapping ErrUnsupportedCalculator or ErrUnsupportedCurveType if the
+//     operator set cannot be calculated locally, or an error if L1 cannot be read
+func (c *Calculator) CalculateOperatorTable(ctx context.Context, snap *snapshot.Snapshot, opset ICrossChainRegistry.OperatorSet) (*Result, error) {
+	opts := snap.CallOpts(ctx)
+
+	curveType, err := c.reader.OperatorSetCurveType(opts, opset)
+	if err != nil {
+		return nil, err
+	}
+	if curveType != operatorTable.CurveTypeBN254 && curveType != operatorTable.CurveTypeECDSA {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCurveType, curveType)
+	}
+	config, err := c.reader.OperatorSetConfig(opts, opset)
+	if err != nil {
+		return nil, err
+	}
+	calculator, err := c.reader.OperatorTableCalculator(opts, opset)
+	if err != nil {
+		return nil, err
+	}
+	if calculator == (common.Address{}) {
+		return nil, fmt.Errorf("%w: operator set has no table calculator", ErrUnsupportedCalculator)
+	}
+	params, err := c.reader.CalculatorParams(opts, calculator)
+	if err != nil {
+		return nil, err
+	}
+
+	operators, weights, err := c.operatorWeights(opts, snap, params, opset)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Calculator: calculator,
+		Operators:  []common.Address{},
+		Table: &operatorTable.OperatorTable{
+			OperatorSet: operatorTable.OperatorSet{Avs: opset.Avs, Id: opset.Id},
+			CurveType:   curveType,
+			Config:      config,
+		},
+	}
+	switch curveType {
+	case operatorTable.CurveTypeBN254:
+		err = c.calculateBN254(opts, params, opset, operators, weights, result)
+	case operatorTable.CurveTypeECDSA:
+		err = c.calculateECDSA(opts, params, opset, operators, weights, result)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if result.TableBytes, err = operatorTable.Encode(result.Table); err != nil {
+		return nil, err
+	}
+	c.logger.Sugar().Debugw("Calculated operator table locally",
+		zap.Uint32("opsetId", opset.Id),
+		zap.String("opsetAvs", opset.Avs.String()),
+		zap.String("curveType", curveType.String()),
+		zap.Int("operators", len(result.Operators)),
+	)
+	return result, nil
+}
+
+// operatorWeights mirrors the standard calculators' _getOperatorWeights: each member's weight
+// is its minimum slashable stake summed over the operator set's strategies, measured
+// LOOKAHEAD_BLOCKS after the reference block, and members with no weight are dropped.
+func (c *Calculator) operatorWeights(
+	opts *bind.CallOpts,
+	snap *snapshot.Snapshot,
+	params *CalculatorParams,
+	opset ICrossChainRegistry.OperatorSet,
+) ([]common.Address, [][]*big.Int, error) {
+	members, err := c.reader.Members(opts, params.AllocationManager, opset)
+	if err != nil {
+		return nil, nil, err
+	}
+	strategies, err := c.reader.Strategies(opts, params.AllocationManager, opset)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The calculator truncates block.number + LOOKAHEAD_BLOCKS to uint32
+	futureBlock := uint32(snap.Number + params.LookaheadBlocks)
+	stake, err := c.reader.MinimumSlashableStake(opts, params.AllocationManager, opset, members, strategies, futureBlock)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(stake) != len(members) {
+		return nil, nil, fmt.Errorf("got minimum slashable stake for %d operators, expected %d", len(stake), len(members))
+	}
+
+	var operators []common.Address
+	var weights [][]*big.Int
+	for i, member := range members {
+		if len(stake[i]) != len(strategies) {
+			return nil, nil, fmt.Errorf("got minimum slashable stake for %d strategies of operator %s, expected %d", len(stake[i]), member.Hex(), len(strategies))
+		}
+		total := new(big.Int)
+		for _, s := range stake[i] {
+			total.Add(total, s)
+		}
+		if total.Sign() > 0 {
+			operators = append(operators, member)
+			weights = append(weights, []*big.Int{total})
+		}
+	}
+	return operators, weights, nil
+}
+
+// calculateBN254 mirrors BN254TableCalculatorBase._calculateOperatorTable. Operators without
+// a registered key are skipped; if none remain, the table is the zero BN254OperatorSetInfo.
+func (c *Calculator) calculateBN254(
+	opts *bind.CallOpts,
+	params *CalculatorParams,
+	opset ICrossChainRegistry.OperatorSet,
+	operators []common.Address,
+	weights [][]*big.Int,
+	result *Result,
+) error {
+	info := &operatorTable.BN254OperatorSetInfo{
+		NumOperators:    new(big.Int),
+		AggregatePubkey: operatorTable.G1Point{X: new(big.Int), Y: new(big.Int)},
+		TotalWeights:    []*big.Int{},
+	}
+	result.Table.BN254 = info
+	result.BN254OperatorInfos = []operatorTable.BN254OperatorInfo{}
+	if len(operators) == 0 {
+		return nil
+	}
+
+	totalWeights := make([]*big.Int, len(weights[0]))
+	for i := range totalWeights {
+		totalWeights[i] = new(big.Int)
+	}
+	aggregate := bn254.NewZeroG1Point()
+	for i, operator := range operators {
+		registered, err := c.reader.IsRegistered(opts, params.KeyRegistrar, opset, operator)
+		if err != nil {
+			return err
+		}
+		if !registered {
+			continue
+		}
+		pubkey, err := c.reader.BN254Key(opts, params.KeyRegistrar, opset, operator)
+		if err != nil {
+			return err
+		}
+		for j := range totalWeights {
+			totalWeights[j].Add(totalWeights[j], weights[i][j])
+		}
+		aggregate.Add(bn254.NewG1Point(pubkey.X, pubkey.Y))
+		result.Operators = append(result.Operators, operator)
+		result.BN254OperatorInfos = append(result.BN254OperatorInfos, operatorTable.BN254OperatorInfo{Pubkey: pubkey, Weights: weights[i]})
+	}
+	if len(result.BN254OperatorInfos) == 0 {
+		return nil
+	}
+
+	tree, err := operatorInfoTree.New(result.BN254OperatorInfos)
+	if err != nil {
+		return err
+	}
+	info.OperatorInfoTreeRoot = tree.Root()
+	info.NumOperators.SetInt64(int64(tree.Len()))
+	info.AggregatePubkey = operatorTable.G1Point{
+		X: aggregate.X.BigInt(new(big.Int)),
+		Y: aggregate.Y.BigInt(new(big.Int)),
+	}
+	info.TotalWeights = totalWeights
+	return nil
+}
+
+// calculateECDSA mirrors ECDSATableCalculatorBase._calculateOperatorTable. Operators without
+// a registered key are skipped.
+func (c *Calculator) calculateECDSA(
+	opts *bind.CallOpts,
+	params *CalculatorParams,
+	opset ICrossChainRegistry.OperatorSet,
+	operators []common.Address,
+	weights [][]*big.Int,
+	result *Result,
+) error {
+	result.Table.ECDSA = []operatorTable.ECDSAOperatorInfo{}
+	for i, operator := range operators {
+		registered, err := c.reader.IsRegistered(opts, params.KeyRegistrar, opset, operator)
+		if err != nil {
+			return err
+		}
+		if !registered {
+			continue
+		}
+		signer, err := c.reader.ECDSAAddress(opts, params.KeyRegistrar, opset, operator)
+		if err != nil {
+			return err
+		}
+		result.Table.ECDSA = append(result.Table.ECDSA, operatorTable.ECDSAOperatorInfo{Pubkey: signer, Weights: weights[i]})
+		result.Operators = append(result.Operators, operator)
+	}
+	return nil
+}
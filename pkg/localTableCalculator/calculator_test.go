@@ -0,0 +1,225 @@
+package localTableCalculator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/crypto-libs/pkg/bn254"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeReader struct {
+	curveType   operatorTable.CurveType
+	config      operatorTable.OperatorSetConfig
+	calculator  common.Address
+	params      *CalculatorParams
+	paramsErr   error
+	members     []common.Address
+	strategies  []common.Address
+	stake       map[common.Address][]*big.Int
+	bn254Keys   map[common.Address]operatorTable.G1Point
+	ecdsaKeys   map[common.Address]common.Address
+	futureBlock uint32
+}
+
+func (f *fakeReader) OperatorSetCurveType(*bind.CallOpts, ICrossChainRegistry.OperatorSet) (operatorTable.CurveType, error) {
+	return f.curveType, nil
+}
+
+func (f *fakeReader) OperatorSetConfig(*bind.CallOpts, ICrossChainRegistry.OperatorSet) (operatorTable.OperatorSetConfig, error) {
+	return f.config, nil
+}
+
+func (f *fakeReader) OperatorTableCalculator(*bind.CallOpts, ICrossChainRegistry.OperatorSet) (common.Address, error) {
+	return f.calculator, nil
+}
+
+func (f *fakeReader) CalculatorParams(*bind.CallOpts, common.Address) (*CalculatorParams, error) {
+	return f.params, f.paramsErr
+}
+
+func (f *fakeReader) Members(*bind.CallOpts, common.Address, ICrossChainRegistry.OperatorSet) ([]common.Address, error) {
+	return f.members, nil
+}
+
+func (f *fakeReader) Strategies(*bind.CallOpts, common.Address, ICrossChainRegistry.OperatorSet) ([]common.Address, error) {
+	return f.strategies, nil
+}
+
+func (f *fakeReader) MinimumSlashableStake(_ *bind.CallOpts, _ common.Address, _ ICrossChainRegistry.OperatorSet, operators []common.Address, _ []common.Address, futureBlock uint32) ([][]*big.Int, error) {
+	f.futureBlock = futureBlock
+	stake := make([][]*big.Int, len(operators))
+	for i, operator := range operators {
+		stake[i] = f.stake[operator]
+	}
+	return stake, nil
+}
+
+func (f *fakeReader) IsRegistered(_ *bind.CallOpts, _ common.Address, _ ICrossChainRegistry.OperatorSet, operator common.Address) (bool, error) {
+	_, bn254Registered := f.bn254Keys[operator]
+	_, ecdsaRegistered := f.ecdsaKeys[operator]
+	return bn254Registered || ecdsaRegistered, nil
+}
+
+func (f *fakeReader) BN254Key(_ *bind.CallOpts, _ common.Address, _ ICrossChainRegistry.OperatorSet, operator common.Address) (operatorTable.G1Point, error) {
+	key, ok := f.bn254Keys[operator]
+	if !ok {
+		return operatorTable.G1Point{}, fmt.Errorf("no BN254 key for %s", operator.Hex())
+	}
+	return key, nil
+}
+
+func (f *fakeReader) ECDSAAddress(_ *bind.CallOpts, _ common.Address, _ ICrossChainRegistry.OperatorSet, operator common.Address) (common.Address, error) {
+	return f.ecdsaKeys[operator], nil
+}
+
+func (f *fakeReader) RegistryKeyRegistrar(*bind.CallOpts) (common.Address, error) {
+	return f.params.KeyRegistrar, nil
+}
+
+func (f *fakeReader) DelegationManager(*bind.CallOpts, common.Address) (common.Address, error) {
+	return common.HexToAddress("0xde1e"), nil
+}
+
+var (
+	testOpset  = ICrossChainRegistry.OperatorSet{Avs: common.HexToAddress("0xa1"), Id: 3}
+	testSnap   = &snapshot.Snapshot{Number: 1000, Hash: common.HexToHash("0xb1"), Timestamp: 12000}
+	testConfig = operatorTable.OperatorSetConfig{Owner: common.HexToAddress("0x0e"), MaxStalenessPeriod: 86400}
+	operatorA  = common.HexToAddress("0x0a")
+	operatorB  = common.HexToAddress("0x0b")
+	operatorC  = common.HexToAddress("0x0c")
+	operatorD  = common.HexToAddress("0x0d")
+)
+
+func newFakeReader(curveType operatorTable.CurveType) *fakeReader {
+	return &fakeReader{
+		curveType:  curveType,
+		config:     testConfig,
+		calculator: common.HexToAddress("0xca1c"),
+		params: &CalculatorParams{
+			AllocationManager: common.HexToAddress("0xa11c"),
+			KeyRegistrar:      common.HexToAddress("0x4e9"),
+			LookaheadBlocks:   10,
+		},
+		members:    []common.Address{operatorA, operatorB, operatorC, operatorD},
+		strategies: []common.Address{common.HexToAddress("0x51"), common.HexToAddress("0x52")},
+		stake: map[common.Address][]*big.Int{
+			operatorA: {big.NewInt(10), big.NewInt(5)},
+			// No slashable stake, so dropped
+			operatorB: {big.NewInt(0), big.NewInt(0)},
+			// Never registers a key, so skipped
+			operatorC: {big.NewInt(3), big.NewInt(0)},
+			operatorD: {big.NewInt(1), big.NewInt(1)},
+		},
+	}
+}
+
+func TestCalculateOperatorTable_ECDSA(t *testing.T) {
+	reader := newFakeReader(operatorTable.CurveTypeECDSA)
+	reader.ecdsaKeys = map[common.Address]common.Address{
+		operatorA: common.HexToAddress("0x5a"),
+		operatorB: common.HexToAddress("0x5b"),
+		operatorD: common.HexToAddress("0x5d"),
+	}
+
+	result, err := NewCalculatorWithReader(reader, zap.NewNop()).CalculateOperatorTable(context.Background(), testSnap, testOpset)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1010), reader.futureBlock)
+	assert.Equal(t, []common.Address{operatorA, operatorD}, result.Operators)
+	assert.Nil(t, result.BN254OperatorInfos)
+
+	expected, err := operatorTable.Encode(&operatorTable.OperatorTable{
+		OperatorSet: operatorTable.OperatorSet{Avs: testOpset.Avs, Id: testOpset.Id},
+		CurveType:   operatorTable.CurveTypeECDSA,
+		Config:      testConfig,
+		ECDSA: []operatorTable.ECDSAOperatorInfo{
+			{Pubkey: common.HexToAddress("0x5a"), Weights: []*big.Int{big.NewInt(15)}},
+			{Pubkey: common.HexToAddress("0x5d"), Weights: []*big.Int{big.NewInt(2)}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, expected, result.TableBytes)
+}
+
+func TestCalculateOperatorTable_BN254(t *testing.T) {
+	privateKeyA, pubkeyA, err := bn254.GenerateKeyPair()
+	require.NoError(t, err)
+	privateKeyD, pubkeyD, err := bn254.GenerateKeyPair()
+	require.NoError(t, err)
+	g1 := func(pk *bn254.PublicKey) operatorTable.G1Point {
+		return operatorTable.G1Point{X: pk.GetG1Point().X.BigInt(new(big.Int)), Y: pk.GetG1Point().Y.BigInt(new(big.Int))}
+	}
+
+	reader := newFakeReader(operatorTable.CurveTypeBN254)
+	reader.bn254Keys = map[common.Address]operatorTable.G1Point{operatorA: g1(pubkeyA), operatorD: g1(pubkeyD)}
+
+	tableBytes, err := NewCalculatorWithReader(reader, zap.NewNop()).CalculateOperatorTableBytes(context.Background(), testSnap, testOpset)
+	require.NoError(t, err)
+	table, err := operatorTable.Decode(tableBytes)
+	require.NoError(t, err)
+	require.NotNil(t, table.BN254)
+
+	leafA, err := operatorTable.BN254OperatorInfoLeaf(&operatorTable.BN254OperatorInfo{Pubkey: g1(pubkeyA), Weights: []*big.Int{big.NewInt(15)}})
+	require.NoError(t, err)
+	leafD, err := operatorTable.BN254OperatorInfoLeaf(&operatorTable.BN254OperatorInfo{Pubkey: g1(pubkeyD), Weights: []*big.Int{big.NewInt(2)}})
+	require.NoError(t, err)
+	// The aggregate G1 key is the public key of the summed private keys
+	curveOrder, _ := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	summed := new(big.Int).Add(new(big.Int).SetBytes(privateKeyA.Bytes()), new(big.Int).SetBytes(privateKeyD.Bytes()))
+	aggregateKey, err := bn254.NewPrivateKeyFromBytes(summed.Mod(summed, curveOrder).Bytes())
+	require.NoError(t, err)
+	aggregate := aggregateKey.Public()
+
+	assert.Equal(t, crypto.Keccak256Hash(leafA[:], leafD[:]), table.BN254.OperatorInfoTreeRoot)
+	assert.Equal(t, big.NewInt(2), table.BN254.NumOperators)
+	assert.Equal(t, g1(aggregate), table.BN254.AggregatePubkey)
+	assert.Equal(t, []*big.Int{big.NewInt(17)}, table.BN254.TotalWeights)
+	assert.Equal(t, testConfig, table.Config)
+}
+
+func TestCalculateOperatorTable_BN254NoRegisteredOperators(t *testing.T) {
+	reader := newFakeReader(operatorTable.CurveTypeBN254)
+
+	result, err := NewCalculatorWithReader(reader, zap.NewNop()).CalculateOperatorTable(context.Background(), testSnap, testOpset)
+	require.NoError(t, err)
+	assert.Empty(t, result.Operators)
+
+	expected, err := operatorTable.Encode(&operatorTable.OperatorTable{
+		OperatorSet: operatorTable.OperatorSet{Avs: testOpset.Avs, Id: testOpset.Id},
+		CurveType:   operatorTable.CurveTypeBN254,
+		Config:      testConfig,
+		BN254: &operatorTable.BN254OperatorSetInfo{
+			NumOperators:    big.NewInt(0),
+			AggregatePubkey: operatorTable.G1Point{X: big.NewInt(0), Y: big.NewInt(0)},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, expected, result.TableBytes)
+}
+
+func TestCalculateOperatorTable_Unsupported(t *testing.T) {
+	calc := func(reader *fakeReader) error {
+		_, err := NewCalculatorWithReader(reader, zap.NewNop()).CalculateOperatorTable(context.Background(), testSnap, testOpset)
+		return err
+	}
+
+	assert.ErrorIs(t, calc(newFakeReader(operatorTable.CurveTypeNone)), ErrUnsupportedCurveType)
+
+	noCalculator := newFakeReader(operatorTable.CurveTypeECDSA)
+	noCalculator.calculator = common.Address{}
+	assert.ErrorIs(t, calc(noCalculator), ErrUnsupportedCalculator)
+
+	custom := newFakeReader(operatorTable.CurveTypeECDSA)
+	custom.paramsErr = fmt.Errorf("%w: no LOOKAHEAD_BLOCKS()", ErrUnsupportedCalculator)
+	assert.ErrorIs(t, calc(custom), ErrUnsupportedCalculator)
+}
@@ -0,0 +1,543 @@
+package localTableCalculator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IAllocationManager"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IDelegationManager"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IKeyRegistrar"
+	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
+	"github.com/Layr-Labs/multichain-go/pkg/multicall"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// DefaultLogRangeBlocks is the default number of blocks requested per eth_getLogs call.
+const DefaultLogRangeBlocks = 10_000
+
+// ChangeDetectorConfig holds the configuration for a ChangeDetector.
+type ChangeDetectorConfig struct {
+	CrossChainRegistryAddress common.Address
+	// HistoryFromBlock is the first block searched for allocation changes and key rotations
+	// that are still pending at the first compared block. Nothing scheduled before it is seen,
+	// so it should be at or before the block the core contracts were deployed at.
+	HistoryFromBlock uint64
+	// LogRangeBlocks is the number of blocks requested per eth_getLogs call. Defaults to
+	// DefaultLogRangeBlocks. Ranges the RPC rejects as too large are halved.
+	LogRangeBlocks uint64
+}
+
+// ChangeDetector finds the operator sets whose tables may differ between two blocks from the
+// events the CrossChainRegistry, AllocationManager, KeyRegistrar and DelegationManager emit in
+// between. It implements operatorTableCalculator.OperatorSetChangeSource for operator sets with
+// standard table calculators; operator sets with any other calculator are always reported as
+// changed.
+//
+// An operator set is changed when, in the blocks after from up to and including to:
+//   - an event names the operator set, such as a member, strategy, allocation, slashing, key,
+//     curve type, calculator or configuration change
+//   - an event names an operator that is a member, such as a change in delegated shares
+//   - an allocation change scheduled earlier takes effect, or comes within the calculator's
+//     lookahead of taking effect
+//   - a key rotation scheduled earlier activates
+//
+// Allocation changes and key rotations still pending at the first compared block are found by
+// searching the events from ChangeDetectorConfig.HistoryFromBlock.
+type ChangeDetector struct {
+	config    *ChangeDetectorConfig
+	reader    ContractReader
+	ethClient chainManager.EthClientInterface
+	logger    *zap.Logger
+	events    map[common.Hash]trackedEvent
+
+	mu sync.Mutex
+	// states are the detector's view at the last block each run reached, by block hash
+	states             map[common.Hash]*changeState
+	params             map[common.Address]*CalculatorParams
+	delegationManagers map[common.Address]common.Address
+
+	historyMu sync.Mutex
+	history   []scheduledChange
+	// historyTo is the next block the history search starts at
+	historyTo uint64
+}
+
+// trackedEvent is an event that can change an operator table.
+type trackedEvent struct {
+	event abi.Event
+	// hasOperatorSet is set when the event names an operator set
+	hasOperatorSet bool
+	// hasOperator is set when the event names an operator
+	hasOperator bool
+}
+
+// changeState is what the detector knows at one block.
+type changeState struct {
+	opsets    map[ICrossChainRegistry.OperatorSet]*operatorSetState
+	scheduled []scheduledChange
+}
+
+// operatorSetState is an operator set's calculator and members at one block.
+type operatorSetState struct {
+	// params are the calculator's immutables, nil if it is not a standard calculator
+	params  *CalculatorParams
+	members map[common.Address]bool
+}
+
+// scheduledChange is an allocation change or key rotation that takes effect after the block
+// that scheduled it.
+type scheduledChange struct {
+	// contract is the AllocationManager or KeyRegistrar that scheduled the change
+	contract    common.Address
+	opset       ICrossChainRegistry.OperatorSet
+	blockNumber uint64
+	// effectBlock is the block an allocation change takes effect at, zero for key rotations
+	effectBlock uint64
+	// activateAt is the timestamp a key rotation activates at, zero for allocation changes
+	activateAt uint64
+}
+
+var (
+	allocationUpdatedEvent    = mustEvent(IAllocationManager.IAllocationManagerMetaData, "AllocationUpdated")
+	keyRotationScheduledEvent = mustEvent(IKeyRegistrar.IKeyRegistrarMetaData, "KeyRotationScheduled")
+)
+
+// NewChangeDetector creates a ChangeDetector that reads L1 through the given client.
+//
+// Parameters:
+//   - cfg: The detector configuration
+//   - ec: The L1 client
+//   - l: Logger
+//
+// Returns:
+//   - *ChangeDetector: The detector
+//   - error: An error if the CrossChainRegistry cannot be bound
+func NewChangeDetector(cfg *ChangeDetectorConfig, ec chainManager.EthClientInterface, l *zap.Logger) (*ChangeDetector, error) {
+	reader, err := NewChainReader(cfg.CrossChainRegistryAddress, ec)
+	if err != nil {
+		return nil, err
+	}
+	return NewChangeDetectorWithReader(cfg, reader, ec, l)
+}
+
+// NewChangeDetectorWithReader creates a ChangeDetector with a pre-built contract reader.
+//
+// Parameters:
+//   - cfg: The detector configuration
+//   - reader: The contract reader
+//   - ec: The L1 client logs are read from
+//   - l: Logger
+//
+// Returns:
+//   - *ChangeDetector: The detector
+//   - error: An error if the contract ABIs cannot be parsed
+func NewChangeDetectorWithReader(cfg *ChangeDetectorConfig, reader ContractReader, ec chainManager.EthClientInterface, l *zap.Logger) (*ChangeDetector, error) {
+	events := make(map[common.Hash]trackedEvent)
+	for _, metaData := range []*bind.MetaData{
+		IAllocationManager.IAllocationManagerMetaData,
+		IKeyRegistrar.IKeyRegistrarMetaData,
+		ICrossChainRegistry.ICrossChainRegistryMetaData,
+		IDelegationManager.IDelegationManagerMetaData,
+	} {
+		parsed, err := metaData.GetAbi()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse contract ABI: %w", err)
+		}
+		for _, event := range parsed.Events {
+			tracked := trackedEvent{event: event}
+			for _, input := range event.Inputs {
+				switch {
+				case input.Name == "operatorSet" && input.Type.T == abi.TupleTy:
+					tracked.hasOperatorSet = true
+				case input.Name == "operator" && input.Type.T == abi.AddressTy:
+					tracked.hasOperator = true
+				}
+			}
+			if tracked.hasOperatorSet || tracked.hasOperator {
+				events[event.ID] = tracked
+			}
+		}
+	}
+	return &ChangeDetector{
+		config:             cfg,
+		reader:             reader,
+		ethClient:          ec,
+		logger:             l,
+		events:             events,
+		states:             make(map[common.Hash]*changeState),
+		params:             make(map[common.Address]*CalculatorParams),
+		delegationManagers: make(map[common.Address]common.Address),
+		historyTo:          cfg.HistoryFromBlock,
+	}, nil
+}
+
+// ChangedOperatorSets reports, for each opset, whether its table at to may differ from its
+// table at from. Calls for consecutive blocks carry the detector's view from one call to the
+// next; a call whose from block was not the to block of an earlier call reads the operator sets
+// at from first.
+//
+// Parameters:
+//   - ctx: Context for the L1 reads
+//   - from: The earlier block
+//   - to: The later block
+//   - opsets: The operator sets to check
+//
+// Returns:
+//   - []bool: Whether each operator set may have changed, in opsets order
+//   - error: An error if L1 cannot be read
+func (d *ChangeDetector) ChangedOperatorSets(
+	ctx context.Context,
+	from, to *snapshot.Snapshot,
+	opsets []ICrossChainRegistry.OperatorSet,
+) ([]bool, error) {
+	if to.Number <= from.Number {
+		return nil, fmt.Errorf("block %d is not after block %d", to.Number, from.Number)
+	}
+
+	d.mu.Lock()
+	state := d.states[from.Hash]
+	delete(d.states, from.Hash)
+	d.mu.Unlock()
+
+	if state == nil {
+		scheduled, err := d.scheduledBefore(ctx, from)
+		if err != nil {
+			return nil, err
+		}
+		state = &changeState{opsets: make(map[ICrossChainRegistry.OperatorSet]*operatorSetState), scheduled: scheduled}
+	}
+	for _, opset := range opsets {
+		if _, ok := state.opsets[opset]; ok {
+			continue
+		}
+		opsetState, err := d.loadOperatorSet(ctx, from, opset)
+		if err != nil {
+			return nil, err
+		}
+		state.opsets[opset] = opsetState
+	}
+
+	contracts, err := d.contracts(ctx, from, state)
+	if err != nil {
+		return nil, err
+	}
+	topics := make([]common.Hash, 0, len(d.events))
+	for id := range d.events {
+		topics = append(topics, id)
+	}
+	logs, err := d.filterLogs(ctx, ethereum.FilterQuery{Addresses: contracts, Topics: [][]common.Hash{topics}}, from.Number+1, to.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	marked := make(map[ICrossChainRegistry.OperatorSet]bool)
+	markedOperators := make(map[common.Address]bool)
+	for _, log := range logs {
+		opset, operator, change, ok, err := d.decodeLog(log)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if opset != nil {
+			marked[*opset] = true
+		} else if operator != nil {
+			markedOperators[*operator] = true
+		}
+		if change != nil {
+			state.scheduled = append(state.scheduled, *change)
+		}
+	}
+
+	changed := make([]bool, len(opsets))
+	next := &changeState{opsets: make(map[ICrossChainRegistry.OperatorSet]*operatorSetState)}
+	for i, opset := range opsets {
+		opsetState := state.opsets[opset]
+		changed[i] = opsetState.params == nil || marked[opset] || opsetState.hasMember(markedOperators)
+		for _, change := range state.scheduled {
+			changed[i] = changed[i] || change.affects(opset, opsetState.params, from, to)
+		}
+		if changed[i] {
+			if opsetState, err = d.loadOperatorSet(ctx, to, opset); err != nil {
+				return nil, err
+			}
+		}
+		next.opsets[opset] = opsetState
+	}
+	for _, change := range state.scheduled {
+		if change.pendingAfter(to) {
+			next.scheduled = append(next.scheduled, change)
+		}
+	}
+
+	d.mu.Lock()
+	d.states[to.Hash] = next
+	d.mu.Unlock()
+
+	d.logger.Sugar().Debugw("Found changed operator sets",
+		zap.Uint64("fromBlockNumber", from.Number),
+		zap.Uint64("toBlockNumber", to.Number),
+		zap.Int("logs", len(logs)),
+		zap.Int("pendingChanges", len(next.scheduled)),
+	)
+	return changed, nil
+}
+
+// loadOperatorSet reads an operator set's calculator and members at the snapshot.
+func (d *ChangeDetector) loadOperatorSet(ctx context.Context, snap *snapshot.Snapshot, opset ICrossChainRegistry.OperatorSet) (*operatorSetState, error) {
+	opts := snap.CallOpts(ctx)
+	calculator, err := d.reader.OperatorTableCalculator(opts, opset)
+	if err != nil {
+		return nil, err
+	}
+	params, err := d.calculatorParams(ctx, snap, calculator)
+	if err != nil {
+		return nil, err
+	}
+	state := &operatorSetState{params: params, members: make(map[common.Address]bool)}
+	if params == nil {
+		return state, nil
+	}
+	members, err := d.reader.Members(opts, params.AllocationManager, opset)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		state.members[member] = true
+	}
+	return state, nil
+}
+
+// calculatorParams returns a calculator's immutables, or nil if it is not a standard
+// calculator. They are immutable, so each calculator is read once.
+func (d *ChangeDetector) calculatorParams(ctx context.Context, snap *snapshot.Snapshot, calculator common.Address) (*CalculatorParams, error) {
+	d.mu.Lock()
+	params, ok := d.params[calculator]
+	d.mu.Unlock()
+	if ok {
+		return params, nil
+	}
+	params, err := d.reader.CalculatorParams(snap.CallOpts(ctx), calculator)
+	if errors.Is(err, ErrUnsupportedCalculator) {
+		params, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	d.params[calculator] = params
+	d.mu.Unlock()
+	return params, nil
+}
+
+// contracts returns the addresses whose events can change the tables of the state's operator sets.
+func (d *ChangeDetector) contracts(ctx context.Context, snap *snapshot.Snapshot, state *changeState) ([]common.Address, error) {
+	keyRegistrar, err := d.reader.RegistryKeyRegistrar(snap.CallOpts(ctx))
+	if err != nil {
+		return nil, err
+	}
+	set := map[common.Address]bool{d.config.CrossChainRegistryAddress: true, keyRegistrar: true}
+	for _, opsetState := range state.opsets {
+		if opsetState.params == nil {
+			continue
+		}
+		delegationManager, err := d.delegationManager(ctx, snap, opsetState.params.AllocationManager)
+		if err != nil {
+			return nil, err
+		}
+		set[opsetState.params.AllocationManager] = true
+		set[opsetState.params.KeyRegistrar] = true
+		set[delegationManager] = true
+	}
+	contracts := make([]common.Address, 0, len(set))
+	for address := range set {
+		contracts = append(contracts, address)
+	}
+	sort.Slice(contracts, func(i, j int) bool { return contracts[i].Cmp(contracts[j]) < 0 })
+	return contracts, nil
+}
+
+// delegationManager returns the AllocationManager's DelegationManager, which is immutable, so
+// each AllocationManager is read once.
+func (d *ChangeDetector) delegationManager(ctx context.Context, snap *snapshot.Snapshot, allocationManager common.Address) (common.Address, error) {
+	d.mu.Lock()
+	delegationManager, ok := d.delegationManagers[allocationManager]
+	d.mu.Unlock()
+	if ok {
+		return delegationManager, nil
+	}
+	delegationManager, err := d.reader.DelegationManager(snap.CallOpts(ctx), allocationManager)
+	if err != nil {
+		return common.Address{}, err
+	}
+	d.mu.Lock()
+	d.delegationManagers[allocationManager] = delegationManager
+	d.mu.Unlock()
+	return delegationManager, nil
+}
+
+// scheduledBefore returns the allocation changes and key rotations scheduled from
+// ChangeDetectorConfig.HistoryFromBlock up to and including the snapshot that are still
+// pending at it. The search is shared by every run, so each block is searched once.
+func (d *ChangeDetector) scheduledBefore(ctx context.Context, snap *snapshot.Snapshot) ([]scheduledChange, error) {
+	d.historyMu.Lock()
+	defer d.historyMu.Unlock()
+
+	if snap.Number >= d.historyTo {
+		logs, err := d.filterLogs(ctx, ethereum.FilterQuery{
+			Topics: [][]common.Hash{{allocationUpdatedEvent.ID, keyRotationScheduledEvent.ID}},
+		}, d.historyTo, snap.Number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search pending allocation changes and key rotations: %w", err)
+		}
+		for _, log := range logs {
+			// The search is not limited to known contracts, so logs of other contracts that
+			// happen to share an event signature may not decode
+			_, _, change, ok, err := d.decodeLog(log)
+			if err == nil && ok && change != nil {
+				d.history = append(d.history, *change)
+			}
+		}
+		d.historyTo = snap.Number + 1
+	}
+
+	var scheduled []scheduledChange
+	for _, change := range d.history {
+		if change.blockNumber <= snap.Number && change.pendingAfter(snap) {
+			scheduled = append(scheduled, change)
+		}
+	}
+	return scheduled, nil
+}
+
+// filterLogs returns the logs matching q in blocks from to to, inclusive, requesting at most
+// ChangeDetectorConfig.LogRangeBlocks blocks at a time and halving the range when the RPC
+// rejects it as too large.
+func (d *ChangeDetector) filterLogs(ctx context.Context, q ethereum.FilterQuery, from, to uint64) ([]types.Log, error) {
+	rangeBlocks := d.config.LogRangeBlocks
+	if rangeBlocks == 0 {
+		rangeBlocks = DefaultLogRangeBlocks
+	}
+	var logs []types.Log
+	for start := from; start <= to; {
+		end := to
+		if to-start >= rangeBlocks {
+			end = start + rangeBlocks - 1
+		}
+		q.FromBlock = new(big.Int).SetUint64(start)
+		q.ToBlock = new(big.Int).SetUint64(end)
+		page, err := d.ethClient.FilterLogs(ctx, q)
+		if err != nil {
+			if multicall.IsLimitError(err) && end > start {
+				rangeBlocks = (end - start + 1) / 2
+				continue
+			}
+			return nil, fmt.Errorf("failed to filter logs in blocks %d to %d: %w", start, end, err)
+		}
+		logs = append(logs, page...)
+		if end == to {
+			break
+		}
+		start = end + 1
+	}
+	return logs, nil
+}
+
+// decodeLog returns the operator set or operator a tracked event names, and the change it
+// schedules, if any. ok is false for logs of events that are not tracked.
+func (d *ChangeDetector) decodeLog(log types.Log) (
+	opset *ICrossChainRegistry.OperatorSet,
+	operator *common.Address,
+	change *scheduledChange,
+	ok bool,
+	err error,
+) {
+	if len(log.Topics) == 0 {
+		return nil, nil, nil, false, nil
+	}
+	tracked, ok := d.events[log.Topics[0]]
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+
+	values := make(map[string]interface{})
+	var indexed abi.Arguments
+	for _, input := range tracked.event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, log.Topics[1:]); err != nil {
+		return nil, nil, nil, false, fmt.Errorf("failed to decode %s topics in transaction %s: %w", tracked.event.Name, log.TxHash.Hex(), err)
+	}
+	if err := tracked.event.Inputs.NonIndexed().UnpackIntoMap(values, log.Data); err != nil {
+		return nil, nil, nil, false, fmt.Errorf("failed to decode %s data in transaction %s: %w", tracked.event.Name, log.TxHash.Hex(), err)
+	}
+
+	if tracked.hasOperatorSet {
+		opset = abi.ConvertType(values["operatorSet"], new(ICrossChainRegistry.OperatorSet)).(*ICrossChainRegistry.OperatorSet)
+	}
+	if tracked.hasOperator {
+		address := values["operator"].(common.Address)
+		operator = &address
+	}
+	switch log.Topics[0] {
+	case allocationUpdatedEvent.ID:
+		change = &scheduledChange{contract: log.Address, opset: *opset, blockNumber: log.BlockNumber, effectBlock: uint64(values["effectBlock"].(uint32))}
+	case keyRotationScheduledEvent.ID:
+		change = &scheduledChange{contract: log.Address, opset: *opset, blockNumber: log.BlockNumber, activateAt: values["activateAt"].(uint64)}
+	}
+	return opset, operator, change, true, nil
+}
+
+// hasMember reports whether any of the operators is a member of the operator set.
+func (s *operatorSetState) hasMember(operators map[common.Address]bool) bool {
+	for operator := range operators {
+		if s.members[operator] {
+			return true
+		}
+	}
+	return false
+}
+
+// affects reports whether the change can make the operator set's table at to differ from its
+// table at from. An allocation change moves the calculator's minimum slashable stake once the
+// effect block is within its lookahead, and the current allocation at the effect block.
+func (c scheduledChange) affects(opset ICrossChainRegistry.OperatorSet, params *CalculatorParams, from, to *snapshot.Snapshot) bool {
+	if params == nil || c.opset != opset {
+		return false
+	}
+	if c.effectBlock != 0 {
+		return c.contract == params.AllocationManager && c.effectBlock > from.Number && c.effectBlock <= to.Number+params.LookaheadBlocks
+	}
+	return c.contract == params.KeyRegistrar && c.activateAt > from.Timestamp && c.activateAt <= to.Timestamp
+}
+
+// pendingAfter reports whether the change still has an effect to come after the snapshot.
+func (c scheduledChange) pendingAfter(snap *snapshot.Snapshot) bool {
+	if c.effectBlock != 0 {
+		return c.effectBlock > snap.Number
+	}
+	return c.activateAt > snap.Timestamp
+}
+
+// mustEvent returns the named event of a contract ABI.
+func mustEvent(metaData *bind.MetaData, name string) abi.Event {
+	parsed, err := metaData.GetAbi()
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse contract ABI: %v", err))
+	}
+	return parsed.Events[name]
+}
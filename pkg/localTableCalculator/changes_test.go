@@ -0,0 +1,189 @@
+package localTableCalculator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IAllocationManager"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IDelegationManager"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IKeyRegistrar"
+	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var (
+	testRegistryAddress   = common.HexToAddress("0xcc")
+	testDelegationManager = common.HexToAddress("0xde1e")
+	otherOpset            = ICrossChainRegistry.OperatorSet{Avs: common.HexToAddress("0xa2"), Id: 7}
+)
+
+// testLog encodes an event log with args in the event's input order.
+func testLog(t *testing.T, metaData *bind.MetaData, name string, contract common.Address, block uint64, args ...interface{}) types.Log {
+	parsed, err := metaData.GetAbi()
+	require.NoError(t, err)
+	event := parsed.Events[name]
+	topics := []common.Hash{event.ID}
+	var data []interface{}
+	for i, input := range event.Inputs {
+		if !input.Indexed {
+			data = append(data, args[i])
+			continue
+		}
+		topic, err := abi.MakeTopics([]interface{}{args[i]})
+		require.NoError(t, err)
+		topics = append(topics, topic[0][0])
+	}
+	packed, err := event.Inputs.NonIndexed().Pack(data...)
+	require.NoError(t, err)
+	return types.Log{Address: contract, Topics: topics, Data: packed, BlockNumber: block}
+}
+
+// newTestChangeDetector returns a detector whose L1 client serves logs from the given list,
+// filtered like eth_getLogs, and the number of eth_getLogs calls made.
+func newTestChangeDetector(t *testing.T, reader *fakeReader, logs []types.Log, failLargerThan uint64) (*ChangeDetector, *int) {
+	ec := chainManager.NewMockEthClientInterface(t)
+	calls := 0
+	ec.On("FilterLogs", mock.Anything, mock.Anything).Return(func(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+		calls++
+		from, to := q.FromBlock.Uint64(), q.ToBlock.Uint64()
+		if failLargerThan != 0 && to-from+1 > failLargerThan {
+			return nil, errors.New("query returned more than 10000 results")
+		}
+		var matched []types.Log
+		for _, log := range logs {
+			if log.BlockNumber < from || log.BlockNumber > to {
+				continue
+			}
+			if len(q.Addresses) > 0 && !containsAddress(q.Addresses, log.Address) {
+				continue
+			}
+			if len(q.Topics) > 0 && !containsHash(q.Topics[0], log.Topics[0]) {
+				continue
+			}
+			matched = append(matched, log)
+		}
+		return matched, nil
+	}).Maybe()
+
+	detector, err := NewChangeDetectorWithReader(&ChangeDetectorConfig{
+		CrossChainRegistryAddress: testRegistryAddress,
+		HistoryFromBlock:          1,
+		LogRangeBlocks:            100,
+	}, reader, ec, zap.NewNop())
+	require.NoError(t, err)
+	return detector, &calls
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+func containsHash(hashes []common.Hash, hash common.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+func testBlock(number uint64) *snapshot.Snapshot {
+	return &snapshot.Snapshot{Number: number, Hash: common.BigToHash(new(big.Int).SetUint64(number)), Timestamp: number * 12}
+}
+
+// TestChangeDetector verifies that operator sets are reported as changed by events naming
+// them or their members, and by allocation changes and key rotations scheduled earlier, and
+// as unchanged otherwise.
+func TestChangeDetector(t *testing.T) {
+	reader := newFakeReader(0)
+	allocationManager, keyRegistrar := reader.params.AllocationManager, reader.params.KeyRegistrar
+	strategy := common.HexToAddress("0x51")
+
+	logs := []types.Log{
+		// Scheduled before the backfill, takes effect at block 1025; the lookahead is 10 blocks
+		testLog(t, IAllocationManager.IAllocationManagerMetaData, "AllocationUpdated", allocationManager, 500,
+			operatorA, IAllocationManager.OperatorSet(testOpset), strategy, uint64(1000), uint32(1025)),
+		// Shares of an operator that is not a member
+		testLog(t, IDelegationManager.IDelegationManagerMetaData, "OperatorSharesIncreased", testDelegationManager, 1005,
+			common.HexToAddress("0x99"), common.HexToAddress("0x98"), strategy, big.NewInt(1)),
+		// Rotation of a key of the other operator set, active at block 1040's timestamp
+		testLog(t, IKeyRegistrar.IKeyRegistrarMetaData, "KeyRotationScheduled", keyRegistrar, 1025,
+			IKeyRegistrar.OperatorSet(otherOpset), operatorA, uint8(2), []byte{0x01}, []byte{0x02}, uint64(1040*12)),
+		// Shares of a member of both operator sets
+		testLog(t, IDelegationManager.IDelegationManagerMetaData, "OperatorSharesDecreased", testDelegationManager, 1045,
+			operatorD, common.HexToAddress("0x98"), strategy, big.NewInt(1)),
+		// Emitted by a contract that is not watched
+		testLog(t, IDelegationManager.IDelegationManagerMetaData, "OperatorSharesDecreased", common.HexToAddress("0xbad"), 1055,
+			operatorD, common.HexToAddress("0x98"), strategy, big.NewInt(1)),
+	}
+	detector, _ := newTestChangeDetector(t, reader, logs, 0)
+
+	opsets := []ICrossChainRegistry.OperatorSet{testOpset, otherOpset}
+	tests := []struct {
+		from, to uint64
+		want     []bool
+	}{
+		{from: 1000, to: 1010, want: []bool{false, false}},
+		// The allocation change is within the lookahead of block 1020
+		{from: 1010, to: 1020, want: []bool{true, false}},
+		// The allocation change takes effect; the rotation is scheduled
+		{from: 1020, to: 1030, want: []bool{true, true}},
+		// The rotation activates
+		{from: 1030, to: 1040, want: []bool{false, true}},
+		{from: 1040, to: 1050, want: []bool{true, true}},
+		{from: 1050, to: 1060, want: []bool{false, false}},
+	}
+	for _, tt := range tests {
+		changed, err := detector.ChangedOperatorSets(context.Background(), testBlock(tt.from), testBlock(tt.to), opsets)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, changed, "blocks %d to %d", tt.from, tt.to)
+	}
+}
+
+// TestChangeDetector_UnsupportedCalculator verifies that operator sets with custom calculators
+// are always reported as changed.
+func TestChangeDetector_UnsupportedCalculator(t *testing.T) {
+	reader := newFakeReader(0)
+	reader.paramsErr = ErrUnsupportedCalculator
+	detector, _ := newTestChangeDetector(t, reader, nil, 0)
+
+	changed, err := detector.ChangedOperatorSets(context.Background(), testBlock(1000), testBlock(1010), []ICrossChainRegistry.OperatorSet{testOpset})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true}, changed)
+}
+
+// TestChangeDetector_SplitsLogRanges verifies that log ranges the RPC rejects as too large are
+// halved until they succeed.
+func TestChangeDetector_SplitsLogRanges(t *testing.T) {
+	reader := newFakeReader(0)
+	logs := []types.Log{
+		testLog(t, IKeyRegistrar.IKeyRegistrarMetaData, "KeyDeregistered", reader.params.KeyRegistrar, 1090,
+			IKeyRegistrar.OperatorSet(testOpset), operatorA, uint8(1)),
+	}
+	detector, calls := newTestChangeDetector(t, reader, logs, 30)
+
+	changed, err := detector.ChangedOperatorSets(context.Background(), testBlock(1000), testBlock(1100), []ICrossChainRegistry.OperatorSet{testOpset})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true}, changed)
+	assert.Greater(t, *calls, 2)
+
+	_, err = detector.ChangedOperatorSets(context.Background(), testBlock(1100), testBlock(1050), []ICrossChainRegistry.OperatorSet{testOpset})
+	assert.ErrorContains(t, err, "is not after")
+}
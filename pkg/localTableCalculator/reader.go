@@ -0,0 +1,299 @@
+package localTableCalculator
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/CrossChainRegistry"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IAllocationManager"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IKeyRegistrar"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// standardCalculatorABI covers the public immutables of the standard BN254 and ECDSA table
+// calculators, which are not part of IOperatorTableCalculator.
+const standardCalculatorABI = `[{"inputs":[],"name":"allocationManager","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"keyRegistrar","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"LOOKAHEAD_BLOCKS","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+// CalculatorParams are the immutables of a standard table calculator.
+type CalculatorParams struct {
+	// AllocationManager is the AllocationManager the calculator reads members and stake from
+	AllocationManager common.Address
+	// KeyRegistrar is the KeyRegistrar the calculator reads operator keys from
+	KeyRegistrar common.Address
+	// LookaheadBlocks is how many blocks ahead of the reference block slashable stake is measured
+	LookaheadBlocks uint64
+}
+
+// ContractReader reads the L1 state the CrossChainRegistry and the standard table calculators
+// read when calculating operator table bytes. Every read is made at opts.
+type ContractReader interface {
+	// OperatorSetCurveType returns the operator set's curve type in the CrossChainRegistry's KeyRegistrar
+	OperatorSetCurveType(opts *bind.CallOpts, opset ICrossChainRegistry.OperatorSet) (operatorTable.CurveType, error)
+	// OperatorSetConfig returns the operator set's configuration in the CrossChainRegistry
+	OperatorSetConfig(opts *bind.CallOpts, opset ICrossChainRegistry.OperatorSet) (operatorTable.OperatorSetConfig, error)
+	// OperatorTableCalculator returns the operator set's table calculator in the CrossChainRegistry
+	OperatorTableCalculator(opts *bind.CallOpts, opset ICrossChainRegistry.OperatorSet) (common.Address, error)
+	// CalculatorParams returns a standard table calculator's immutables
+	CalculatorParams(opts *bind.CallOpts, calculator common.Address) (*CalculatorParams, error)
+	// Members returns the operator set's members in AllocationManager order
+	Members(opts *bind.CallOpts, allocationManager common.Address, opset ICrossChainRegistry.OperatorSet) ([]common.Address, error)
+	// Strategies returns the operator set's strategies
+	Strategies(opts *bind.CallOpts, allocationManager common.Address, opset ICrossChainRegistry.OperatorSet) ([]common.Address, error)
+	// MinimumSlashableStake returns each operator's minimum slashable stake per strategy up to futureBlock
+	MinimumSlashableStake(opts *bind.CallOpts, allocationManager common.Address, opset ICrossChainRegistry.OperatorSet, operators []common.Address, strategies []common.Address, futureBlock uint32) ([][]*big.Int, error)
+	// IsRegistered reports whether the operator has registered a key for the operator set
+	IsRegistered(opts *bind.CallOpts, keyRegistrar common.Address, opset ICrossChainRegistry.OperatorSet, operator common.Address) (bool, error)
+	// BN254Key returns the operator's G1 public key for the operator set
+	BN254Key(opts *bind.CallOpts, keyRegistrar common.Address, opset ICrossChainRegistry.OperatorSet, operator common.Address) (operatorTable.G1Point, error)
+	// ECDSAAddress returns the operator's signing address for the operator set
+	ECDSAAddress(opts *bind.CallOpts, keyRegistrar common.Address, opset ICrossChainRegistry.OperatorSet, operator common.Address) (common.Address, error)
+	// RegistryKeyRegistrar returns the KeyRegistrar the CrossChainRegistry reads curve types from
+	RegistryKeyRegistrar(opts *bind.CallOpts) (common.Address, error)
+	// DelegationManager returns the DelegationManager the AllocationManager reads operator shares from
+	DelegationManager(opts *bind.CallOpts, allocationManager common.Address) (common.Address, error)
+}
+
+// ChainReader is a ContractReader backed by an L1 client.
+type ChainReader struct {
+	caller   bind.ContractCaller
+	registry *CrossChainRegistry.CrossChainRegistryCaller
+
+	calculatorAbi abi.ABI
+
+	mu                   sync.Mutex
+	registryKeyRegistrar *common.Address
+}
+
+var _ ContractReader = (*ChainReader)(nil)
+
+// NewChainReader creates a ChainReader.
+//
+// Parameters:
+//   - crossChainRegistryAddress: The CrossChainRegistry address
+//   - caller: The L1 contract caller
+//
+// Returns:
+//   - *ChainReader: The reader
+//   - error: An error if the CrossChainRegistry cannot be bound
+func NewChainReader(crossChainRegistryAddress common.Address, caller bind.ContractCaller) (*ChainReader, error) {
+	registry, err := CrossChainRegistry.NewCrossChainRegistryCaller(crossChainRegistryAddress, caller)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind CrossChainRegistry: %w", err)
+	}
+	calculatorAbi, err := abi.JSON(strings.NewReader(standardCalculatorABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse table calculator ABI: %w", err)
+	}
+	return &ChainReader{caller: caller, registry: registry, calculatorAbi: calculatorAbi}, nil
+}
+
+// OperatorSetCurveType implements ContractReader.
+func (r *ChainReader) OperatorSetCurveType(opts *bind.CallOpts, opset ICrossChainRegistry.OperatorSet) (operatorTable.CurveType, error) {
+	keyRegistrarAddress, err := r.crossChainRegistryKeyRegistrar(opts)
+	if err != nil {
+		return 0, err
+	}
+	keyRegistrar, err := r.keyRegistrar(keyRegistrarAddress)
+	if err != nil {
+		return 0, err
+	}
+	curveType, err := keyRegistrar.GetOperatorSetCurveType(opts, IKeyRegistrar.OperatorSet(opset))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get operator set curve type: %w", err)
+	}
+	return operatorTable.CurveType(curveType), nil
+}
+
+// OperatorSetConfig implements ContractReader.
+func (r *ChainReader) OperatorSetConfig(opts *bind.CallOpts, opset ICrossChainRegistry.OperatorSet) (operatorTable.OperatorSetConfig, error) {
+	config, err := r.registry.GetOperatorSetConfig(opts, CrossChainRegistry.OperatorSet(opset))
+	if err != nil {
+		return operatorTable.OperatorSetConfig{}, fmt.Errorf("failed to get operator set config: %w", err)
+	}
+	return operatorTable.OperatorSetConfig{Owner: config.Owner, MaxStalenessPeriod: config.MaxStalenessPeriod}, nil
+}
+
+// OperatorTableCalculator implements ContractReader.
+func (r *ChainReader) OperatorTableCalculator(opts *bind.CallOpts, opset ICrossChainRegistry.OperatorSet) (common.Address, error) {
+	calculator, err := r.registry.GetOperatorTableCalculator(opts, CrossChainRegistry.OperatorSet(opset))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to get operator table calculator: %w", err)
+	}
+	return calculator, nil
+}
+
+// CalculatorParams implements ContractReader. Calculators that do not expose the standard
+// calculators' immutables return an error wrapping ErrUnsupportedCalculator.
+func (r *ChainReader) CalculatorParams(opts *bind.CallOpts, calculator common.Address) (*CalculatorParams, error) {
+	contract := bind.NewBoundContract(calculator, r.calculatorAbi, r.caller, nil, nil)
+	call := func(method string) (interface{}, error) {
+		var out []interface{}
+		if err := contract.Call(opts, &out, method); err != nil {
+			return nil, fmt.Errorf("%w: %s does not implement %s(): %w", ErrUnsupportedCalculator, calculator.Hex(), method, err)
+		}
+		return out[0], nil
+	}
+
+	allocationManager, err := call("allocationManager")
+	if err != nil {
+		return nil, err
+	}
+	keyRegistrar, err := call("keyRegistrar")
+	if err != nil {
+		return nil, err
+	}
+	lookahead, err := call("LOOKAHEAD_BLOCKS")
+	if err != nil {
+		return nil, err
+	}
+	lookaheadBlocks := *abi.ConvertType(lookahead, new(*big.Int)).(**big.Int)
+	if !lookaheadBlocks.IsUint64() {
+		return nil, fmt.Errorf("%w: %s has lookahead of %s blocks", ErrUnsupportedCalculator, calculator.Hex(), lookaheadBlocks)
+	}
+	return &CalculatorParams{
+		AllocationManager: *abi.ConvertType(allocationManager, new(common.Address)).(*common.Address),
+		KeyRegistrar:      *abi.ConvertType(keyRegistrar, new(common.Address)).(*common.Address),
+		LookaheadBlocks:   lookaheadBlocks.Uint64(),
+	}, nil
+}
+
+// Members implements ContractReader.
+func (r *ChainReader) Members(opts *bind.CallOpts, allocationManager common.Address, opset ICrossChainRegistry.OperatorSet) ([]common.Address, error) {
+	am, err := r.allocationManager(allocationManager)
+	if err != nil {
+		return nil, err
+	}
+	members, err := am.GetMembers(opts, IAllocationManager.OperatorSet(opset))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operator set members: %w", err)
+	}
+	return members, nil
+}
+
+// Strategies implements ContractReader.
+func (r *ChainReader) Strategies(opts *bind.CallOpts, allocationManager common.Address, opset ICrossChainRegistry.OperatorSet) ([]common.Address, error) {
+	am, err := r.allocationManager(allocationManager)
+	if err != nil {
+		return nil, err
+	}
+	strategies, err := am.GetStrategiesInOperatorSet(opts, IAllocationManager.OperatorSet(opset))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operator set strategies: %w", err)
+	}
+	return strategies, nil
+}
+
+// MinimumSlashableStake implements ContractReader.
+func (r *ChainReader) MinimumSlashableStake(
+	opts *bind.CallOpts,
+	allocationManager common.Address,
+	opset ICrossChainRegistry.OperatorSet,
+	operators []common.Address,
+	strategies []common.Address,
+	futureBlock uint32,
+) ([][]*big.Int, error) {
+	am, err := r.allocationManager(allocationManager)
+	if err != nil {
+		return nil, err
+	}
+	stake, err := am.GetMinimumSlashableStake(opts, IAllocationManager.OperatorSet(opset), operators, strategies, futureBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get minimum slashable stake: %w", err)
+	}
+	return stake, nil
+}
+
+// IsRegistered implements ContractReader.
+func (r *ChainReader) IsRegistered(opts *bind.CallOpts, keyRegistrarAddress common.Address, opset ICrossChainRegistry.OperatorSet, operator common.Address) (bool, error) {
+	keyRegistrar, err := r.keyRegistrar(keyRegistrarAddress)
+	if err != nil {
+		return false, err
+	}
+	registered, err := keyRegistrar.IsRegistered(opts, IKeyRegistrar.OperatorSet(opset), operator)
+	if err != nil {
+		return false, fmt.Errorf("failed to check key registration of operator %s: %w", operator.Hex(), err)
+	}
+	return registered, nil
+}
+
+// BN254Key implements ContractReader.
+func (r *ChainReader) BN254Key(opts *bind.CallOpts, keyRegistrarAddress common.Address, opset ICrossChainRegistry.OperatorSet, operator common.Address) (operatorTable.G1Point, error) {
+	keyRegistrar, err := r.keyRegistrar(keyRegistrarAddress)
+	if err != nil {
+		return operatorTable.G1Point{}, err
+	}
+	key, err := keyRegistrar.GetBN254Key(opts, IKeyRegistrar.OperatorSet(opset), operator)
+	if err != nil {
+		return operatorTable.G1Point{}, fmt.Errorf("failed to get BN254 key of operator %s: %w", operator.Hex(), err)
+	}
+	return operatorTable.G1Point{X: key.G1Point.X, Y: key.G1Point.Y}, nil
+}
+
+// ECDSAAddress implements ContractReader.
+func (r *ChainReader) ECDSAAddress(opts *bind.CallOpts, keyRegistrarAddress common.Address, opset ICrossChainRegistry.OperatorSet, operator common.Address) (common.Address, error) {
+	keyRegistrar, err := r.keyRegistrar(keyRegistrarAddress)
+	if err != nil {
+		return common.Address{}, err
+	}
+	signer, err := keyRegistrar.GetECDSAAddress(opts, IKeyRegistrar.OperatorSet(opset), operator)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to get ECDSA address of operator %s: %w", operator.Hex(), err)
+	}
+	return signer, nil
+}
+
+// RegistryKeyRegistrar implements ContractReader.
+func (r *ChainReader) RegistryKeyRegistrar(opts *bind.CallOpts) (common.Address, error) {
+	return r.crossChainRegistryKeyRegistrar(opts)
+}
+
+// DelegationManager implements ContractReader.
+func (r *ChainReader) DelegationManager(opts *bind.CallOpts, allocationManager common.Address) (common.Address, error) {
+	am, err := r.allocationManager(allocationManager)
+	if err != nil {
+		return common.Address{}, err
+	}
+	delegationManager, err := am.Delegation(opts)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to get AllocationManager delegation manager: %w", err)
+	}
+	return delegationManager, nil
+}
+
+// crossChainRegistryKeyRegistrar returns the KeyRegistrar the CrossChainRegistry reads curve
+// types from. It is immutable, so it is read once.
+func (r *ChainReader) crossChainRegistryKeyRegistrar(opts *bind.CallOpts) (common.Address, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.registryKeyRegistrar != nil {
+		return *r.registryKeyRegistrar, nil
+	}
+	keyRegistrar, err := r.registry.KeyRegistrar(opts)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to get CrossChainRegistry key registrar: %w", err)
+	}
+	r.registryKeyRegistrar = &keyRegistrar
+	return keyRegistrar, nil
+}
+
+func (r *ChainReader) allocationManager(address common.Address) (*IAllocationManager.IAllocationManagerCaller, error) {
+	am, err := IAllocationManager.NewIAllocationManagerCaller(address, r.caller)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind AllocationManager: %w", err)
+	}
+	return am, nil
+}
+
+func (r *ChainReader) keyRegistrar(address common.Address) (*IKeyRegistrar.IKeyRegistrarCaller, error) {
+	keyRegistrar, err := IKeyRegistrar.NewIKeyRegistrarCaller(address, r.caller)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind KeyRegistrar: %w", err)
+	}
+	return keyRegistrar, nil
+}
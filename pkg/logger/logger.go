@@ -4,6 +4,9 @@
 package logger
 
 import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"net/http"
@@ -75,3 +78,24 @@ func HttpLoggerMiddleware(next http.Handler, l *zap.Logger) http.Handler {
 		}
 	})
 }
+
+// WithTraceContext returns a logger that includes the trace and span IDs of the
+// span carried by ctx, so log lines can be correlated with exported traces.
+// If ctx carries no valid span context, the logger is returned unchanged.
+//
+// Parameters:
+//   - ctx: The context carrying the active span
+//   - l: The logger to annotate
+//
+// Returns:
+//   - *zap.Logger: A logger with traceId and spanId fields attached
+func WithTraceContext(ctx context.Context, l *zap.Logger) *zap.Logger {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return l
+	}
+	return l.With(
+		zap.String("traceId", spanCtx.TraceID().String()),
+		zap.String("spanId", spanCtx.SpanID().String()),
+	)
+}
@@ -0,0 +1,83 @@
+// Package merkle mirrors the inclusion proof checks of the EigenLayer contracts' Merkle
+// library, so proofs can be checked off-chain with exactly the logic the contracts run.
+//
+// Trees are keccak256 trees whose leaves are padded with zero leaves to a power of two. A
+// proof is the concatenation of the 32-byte siblings from leaf to root, and the leaf index
+// decides at each level whether the sibling is hashed on the left or the right.
+package merkle
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ErrInvalidProofLength mirrors Merkle.InvalidProofLength, for proofs that are not a whole
+	// number of 32-byte siblings
+	ErrInvalidProofLength = errors.New("invalid proof length")
+	// ErrInvalidIndex mirrors Merkle.InvalidIndex, for indices the proof does not fully consume
+	ErrInvalidIndex = errors.New("invalid index")
+	// ErrEmptyRoot mirrors Merkle.EmptyRoot, as no proof verifies against the zero root
+	ErrEmptyRoot = errors.New("empty root")
+)
+
+// ProcessInclusionProofKeccak recomputes the root from a leaf and its inclusion proof, matching
+// Merkle.processInclusionProofKeccak. An empty proof yields the leaf itself, whatever the index.
+//
+// Parameters:
+//   - proof: The concatenated 32-byte siblings
+//   - leaf: The leaf hash
+//   - index: The leaf index
+//
+// Returns:
+//   - common.Hash: The computed root
+//   - error: An error wrapping ErrInvalidProofLength or ErrInvalidIndex where the contract would revert
+func ProcessInclusionProofKeccak(proof []byte, leaf common.Hash, index uint64) (common.Hash, error) {
+	if len(proof) == 0 {
+		return leaf, nil
+	}
+	if len(proof)%common.HashLength != 0 {
+		return common.Hash{}, fmt.Errorf("%w: %d bytes is not a multiple of 32", ErrInvalidProofLength, len(proof))
+	}
+	computed := leaf
+	for i := 0; i < len(proof); i += common.HashLength {
+		sibling := proof[i : i+common.HashLength]
+		if index%2 == 0 {
+			computed = crypto.Keccak256Hash(computed[:], sibling)
+		} else {
+			computed = crypto.Keccak256Hash(sibling, computed[:])
+		}
+		index /= 2
+	}
+	if index != 0 {
+		return common.Hash{}, fmt.Errorf("%w: %d levels do not reach the index", ErrInvalidIndex, len(proof)/common.HashLength)
+	}
+	return computed, nil
+}
+
+// VerifyInclusionKeccak reports whether leaf is at index in the tree with the given root,
+// matching Merkle.verifyInclusionKeccak.
+//
+// Parameters:
+//   - proof: The concatenated 32-byte siblings
+//   - root: The tree root
+//   - leaf: The leaf hash
+//   - index: The leaf index
+//
+// Returns:
+//   - bool: Whether the proof computes the root
+//   - error: An error wrapping ErrEmptyRoot, ErrInvalidProofLength or ErrInvalidIndex where
+//     the contract would revert
+func VerifyInclusionKeccak(proof []byte, root, leaf common.Hash, index uint64) (bool, error) {
+	if root == (common.Hash{}) {
+		return false, ErrEmptyRoot
+	}
+	computed, err := ProcessInclusionProofKeccak(proof, leaf, index)
+	if err != nil {
+		return false, err
+	}
+	return computed == root, nil
+}
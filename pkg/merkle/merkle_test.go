@@ -0,0 +1,67 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessInclusionProofKeccak(t *testing.T) {
+	leaves := []common.Hash{
+		crypto.Keccak256Hash([]byte("a")),
+		crypto.Keccak256Hash([]byte("b")),
+		crypto.Keccak256Hash([]byte("c")),
+		{},
+	}
+	left := crypto.Keccak256Hash(leaves[0][:], leaves[1][:])
+	right := crypto.Keccak256Hash(leaves[2][:], leaves[3][:])
+	root := crypto.Keccak256Hash(left[:], right[:])
+
+	// The sibling goes on the right for even indices and on the left for odd ones
+	computed, err := ProcessInclusionProofKeccak(append(leaves[3].Bytes(), left[:]...), leaves[2], 2)
+	require.NoError(t, err)
+	assert.Equal(t, root, computed)
+	computed, err = ProcessInclusionProofKeccak(append(leaves[0].Bytes(), right[:]...), leaves[1], 1)
+	require.NoError(t, err)
+	assert.Equal(t, root, computed)
+
+	valid, err := VerifyInclusionKeccak(append(leaves[0].Bytes(), right[:]...), root, leaves[1], 1)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	valid, err = VerifyInclusionKeccak(append(leaves[0].Bytes(), right[:]...), root, leaves[1], 0)
+	require.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestProcessInclusionProofKeccak_EmptyProof(t *testing.T) {
+	leaf := crypto.Keccak256Hash([]byte("a"))
+
+	// As in the contract, an empty proof is the leaf at any index
+	for _, index := range []uint64{0, 1, 7} {
+		computed, err := ProcessInclusionProofKeccak(nil, leaf, index)
+		require.NoError(t, err)
+		assert.Equal(t, leaf, computed)
+	}
+}
+
+func TestProcessInclusionProofKeccak_Rejects(t *testing.T) {
+	leaf := crypto.Keccak256Hash([]byte("a"))
+	sibling := crypto.Keccak256Hash([]byte("b"))
+
+	_, err := ProcessInclusionProofKeccak(sibling[:31], leaf, 0)
+	assert.ErrorIs(t, err, ErrInvalidProofLength)
+	_, err = ProcessInclusionProofKeccak(append(sibling.Bytes(), 0), leaf, 0)
+	assert.ErrorIs(t, err, ErrInvalidProofLength)
+
+	// One level only consumes one bit of the index
+	_, err = ProcessInclusionProofKeccak(sibling[:], leaf, 2)
+	assert.ErrorIs(t, err, ErrInvalidIndex)
+
+	_, err = VerifyInclusionKeccak(sibling[:], common.Hash{}, leaf, 0)
+	assert.ErrorIs(t, err, ErrEmptyRoot)
+	_, err = VerifyInclusionKeccak(nil, common.Hash{}, common.Hash{}, 0)
+	assert.ErrorIs(t, err, ErrEmptyRoot)
+}
@@ -0,0 +1,242 @@
+// Package multicall batches read-only contract calls through the Multicall3 contract's
+// tryAggregate function, so many eth_calls can be served by a single RPC round trip.
+// Batch sizes adapt to the RPC provider's gas and response-size limits: a batch that
+// exceeds them is split in half and retried, and the size grows back as batches succeed.
+package multicall
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Multicall3Address is the address Multicall3 is deployed at on most EVM chains.
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// DefaultBatchSize is the starting batch size used when Config.BatchSize is unset.
+const DefaultBatchSize = 50
+
+const tryAggregateABI = `[{"inputs":[{"internalType":"bool","name":"requireSuccess","type":"bool"},{"components":[{"internalType":"address","name":"target","type":"address"},{"internalType":"bytes","name":"callData","type":"bytes"}],"internalType":"struct Multicall3.Call[]","name":"calls","type":"tuple[]"}],"name":"tryAggregate","outputs":[{"components":[{"internalType":"bool","name":"success","type":"bool"},{"internalType":"bytes","name":"returnData","type":"bytes"}],"internalType":"struct Multicall3.Result[]","name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+// Call is a single call to include in a batch.
+type Call struct {
+	// Target is the contract to call
+	Target common.Address
+	// CallData is the ABI-encoded call
+	CallData []byte
+}
+
+// Result is the outcome of a single call in a batch.
+type Result struct {
+	// Success reports whether the call succeeded; failed calls carry their revert data
+	Success bool
+	// ReturnData is the call's return data, or its revert data if it failed
+	ReturnData []byte
+	// Err is set when the batch containing the call failed as a whole, so the call was not
+	// executed and should be retried on its own
+	Err error
+}
+
+// aggregateResult is a Multicall3.Result as returned by tryAggregate.
+type aggregateResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// Config holds the configuration for a Multicall.
+type Config struct {
+	// Address is the Multicall3 contract address. Defaults to Multicall3Address.
+	Address common.Address
+	// BatchSize is the largest number of calls sent in one tryAggregate. Defaults to DefaultBatchSize.
+	BatchSize int
+	// SplitOn reports whether a failed batch exceeded the RPC's gas or response-size limits, so
+	// it is split in half and retried. Other failures are not retried; the batch's calls are
+	// reported with Result.Err set. Defaults to IsLimitError.
+	SplitOn func(err error) bool
+}
+
+// Multicall executes batches of calls through Multicall3. It is safe for concurrent use.
+type Multicall struct {
+	contract     *bind.BoundContract
+	splitOn      func(err error) bool
+	maxBatchSize int
+
+	mu        sync.Mutex
+	batchSize int
+}
+
+// NewMulticall creates a new Multicall.
+//
+// Parameters:
+//   - cfg: The Multicall3 configuration, or nil for defaults
+//   - caller: The contract caller used to execute batches
+//
+// Returns:
+//   - *Multicall: The multicall client
+//   - error: An error if the Multicall3 ABI cannot be parsed
+func NewMulticall(cfg *Config, caller bind.ContractCaller) (*Multicall, error) {
+	address := Multicall3Address
+	batchSize := DefaultBatchSize
+	splitOn := IsLimitError
+	if cfg != nil {
+		if cfg.Address != (common.Address{}) {
+			address = cfg.Address
+		}
+		if cfg.BatchSize > 0 {
+			batchSize = cfg.BatchSize
+		}
+		if cfg.SplitOn != nil {
+			splitOn = cfg.SplitOn
+		}
+	}
+	parsed, err := abi.JSON(strings.NewReader(tryAggregateABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Multicall3 ABI: %w", err)
+	}
+	return &Multicall{
+		contract:     bind.NewBoundContract(address, parsed, caller, nil, nil),
+		splitOn:      splitOn,
+		maxBatchSize: batchSize,
+		batchSize:    batchSize,
+	}, nil
+}
+
+// BatchSize returns the current batch size, which shrinks as batches exceed the RPC's limits
+// and grows back, up to the configured size, as they succeed.
+func (m *Multicall) BatchSize() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.batchSize
+}
+
+// TryAggregate executes the calls in batches and returns their results in the same order.
+// A call that reverts does not fail the batch; it is reported with Success set to false.
+// When a batch exceeds the RPC's gas or response-size limits (see Config.SplitOn), it is split
+// in half and retried, and the smaller batch size is used until batches succeed again. When a
+// batch fails for any other reason, such as a missing Multicall3 or an unknown block, its calls
+// are reported with Err set and the remaining batches still run.
+//
+// Parameters:
+//   - opts: Call options, including the block to read at
+//   - calls: The calls to execute
+//
+// Returns:
+//   - []Result: One result per call, in order
+//   - error: An error if opts' context is done
+func (m *Multicall) TryAggregate(opts *bind.CallOpts, calls []Call) ([]Result, error) {
+	results := make([]Result, 0, len(calls))
+	for start := 0; start < len(calls); {
+		if opts != nil && opts.Context != nil && opts.Context.Err() != nil {
+			return nil, opts.Context.Err()
+		}
+		size := min(m.BatchSize(), len(calls)-start)
+		batch, err := m.tryAggregate(opts, calls[start:start+size])
+		if err != nil {
+			if size > 1 && m.splitOn(err) {
+				m.shrink(size)
+				continue
+			}
+			err = fmt.Errorf("failed to execute multicall batch of %d calls: %w", size, err)
+			for range size {
+				results = append(results, Result{Err: err})
+			}
+			start += size
+			continue
+		}
+		m.grow(size)
+		results = append(results, batch...)
+		start += size
+	}
+	return results, nil
+}
+
+func (m *Multicall) tryAggregate(opts *bind.CallOpts, calls []Call) ([]Result, error) {
+	var out []interface{}
+	if err := m.contract.Call(opts, &out, "tryAggregate", false, calls); err != nil {
+		return nil, err
+	}
+	if len(out) != 1 {
+		return nil, fmt.Errorf("unexpected tryAggregate output length %d", len(out))
+	}
+	aggregated := *abi.ConvertType(out[0], new([]aggregateResult)).(*[]aggregateResult)
+	if len(aggregated) != len(calls) {
+		return nil, fmt.Errorf("tryAggregate returned %d results for %d calls", len(aggregated), len(calls))
+	}
+	results := make([]Result, len(aggregated))
+	for i, r := range aggregated {
+		results[i] = Result{Success: r.Success, ReturnData: r.ReturnData}
+	}
+	return results, nil
+}
+
+// shrink halves the batch size after a batch of the given size exceeded the RPC's limits.
+func (m *Multicall) shrink(failedSize int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if next := failedSize / 2; next < m.batchSize {
+		m.batchSize = max(next, 1)
+	}
+}
+
+// grow doubles the batch size, up to the configured size, after a full batch succeeded.
+func (m *Multicall) grow(succeededSize int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if succeededSize >= m.batchSize {
+		m.batchSize = min(m.batchSize*2, m.maxBatchSize)
+	}
+}
+
+// limitErrorMarkers are the error messages RPC providers use for eth_call gas and
+// response-size limits.
+var limitErrorMarkers = []string{
+	"out of gas",
+	"gas required exceeds allowance",
+	"exceeds block gas limit",
+	"response size",
+	"response too large",
+	"response body too large",
+	"request entity too large",
+	"query returned more than",
+}
+
+// IsLimitError reports whether an error is an RPC's gas or response-size limit. It is the
+// default Config.SplitOn and the single classifier of such errors in this module.
+//
+// Parameters:
+//   - err: The error of a failed batch
+//
+// Returns:
+//   - bool: True if a smaller batch may succeed
+func IsLimitError(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, marker := range limitErrorMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// RevertError converts a failed call's revert data into an error, decoding a
+// standard Error(string) reason when present.
+//
+// Parameters:
+//   - returnData: The revert data of a failed call
+//
+// Returns:
+//   - error: The revert error
+func RevertError(returnData []byte) error {
+	if reason, err := abi.UnpackRevert(returnData); err == nil {
+		return fmt.Errorf("execution reverted: %s", reason)
+	}
+	if len(returnData) == 0 {
+		return errors.New("execution reverted")
+	}
+	return fmt.Errorf("execution reverted: 0x%x", returnData)
+}
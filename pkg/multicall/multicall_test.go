@@ -0,0 +1,219 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMulticall3 decodes tryAggregate calls and answers them like Multicall3 would. Calls whose
+// data starts with 0xff revert; batches larger than maxBatch run out of gas, and batches
+// containing a call whose data starts with 0xee fail with an unknown block error.
+type fakeMulticall3 struct {
+	t   *testing.T
+	abi abi.ABI
+
+	mu         sync.Mutex
+	maxBatch   int
+	batchSizes []int
+}
+
+func newFakeMulticall3(t *testing.T, maxBatch int) *fakeMulticall3 {
+	parsed, err := abi.JSON(strings.NewReader(tryAggregateABI))
+	require.NoError(t, err)
+	return &fakeMulticall3{t: t, abi: parsed, maxBatch: maxBatch}
+}
+
+func (f *fakeMulticall3) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return []byte{0x1}, nil
+}
+
+func (f *fakeMulticall3) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method := f.abi.Methods["tryAggregate"]
+	args, err := method.Inputs.Unpack(msg.Data[4:])
+	require.NoError(f.t, err)
+	calls := *abi.ConvertType(args[1], new([]Call)).(*[]Call)
+
+	f.mu.Lock()
+	f.batchSizes = append(f.batchSizes, len(calls))
+	maxBatch := f.maxBatch
+	f.mu.Unlock()
+
+	if len(calls) > maxBatch {
+		return nil, errors.New("out of gas")
+	}
+	for _, call := range calls {
+		if len(call.CallData) > 0 && call.CallData[0] == 0xee {
+			return nil, errors.New("unknown block")
+		}
+	}
+	results := make([]Result, len(calls))
+	for i, call := range calls {
+		if len(call.CallData) > 0 && call.CallData[0] == 0xff {
+			results[i] = Result{Success: false}
+			continue
+		}
+		results[i] = Result{Success: true, ReturnData: append([]byte{0xaa}, call.CallData...)}
+	}
+	return method.Outputs.Pack(results)
+}
+
+func newTestCalls(n int) []Call {
+	calls := make([]Call, n)
+	for i := range calls {
+		calls[i] = Call{Target: common.HexToAddress("0x1234"), CallData: []byte{byte(i)}}
+	}
+	return calls
+}
+
+func TestTryAggregate_Results(t *testing.T) {
+	backend := newFakeMulticall3(t, 100)
+	mc, err := NewMulticall(&Config{BatchSize: 4}, backend)
+	require.NoError(t, err)
+
+	calls := newTestCalls(10)
+	calls[5].CallData = []byte{0xff}
+
+	results, err := mc.TryAggregate(nil, calls)
+	require.NoError(t, err)
+	require.Len(t, results, 10)
+	for i, result := range results {
+		if i == 5 {
+			assert.False(t, result.Success)
+			continue
+		}
+		assert.True(t, result.Success)
+		assert.Equal(t, []byte{0xaa, byte(i)}, result.ReturnData)
+	}
+	assert.Equal(t, []int{4, 4, 2}, backend.batchSizes)
+}
+
+func TestTryAggregate_AdaptsBatchSize(t *testing.T) {
+	backend := newFakeMulticall3(t, 5)
+	mc, err := NewMulticall(&Config{BatchSize: 16}, backend)
+	require.NoError(t, err)
+
+	results, err := mc.TryAggregate(nil, newTestCalls(20))
+	require.NoError(t, err)
+	require.Len(t, results, 20)
+	for i, result := range results {
+		assert.Equal(t, []byte{0xaa, byte(i)}, result.ReturnData)
+	}
+
+	// 16 and 8 run out of gas; each success at 4 grows the size back to 8, which fails again
+	assert.Equal(t, []int{16, 8, 4, 8, 4, 8, 4, 8, 4, 4}, backend.batchSizes)
+	assert.Equal(t, 8, mc.BatchSize())
+
+	// Once the provider accepts larger batches, the size grows back to the configured maximum
+	backend.maxBatch = 100
+	backend.batchSizes = nil
+	_, err = mc.TryAggregate(nil, newTestCalls(40))
+	require.NoError(t, err)
+	assert.Equal(t, []int{8, 16, 16}, backend.batchSizes)
+	assert.Equal(t, 16, mc.BatchSize())
+}
+
+func TestTryAggregate_SingleCallFailure(t *testing.T) {
+	backend := newFakeMulticall3(t, 0)
+	mc, err := NewMulticall(&Config{BatchSize: 2}, backend)
+	require.NoError(t, err)
+
+	results, err := mc.TryAggregate(nil, newTestCalls(3))
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for _, result := range results {
+		require.Error(t, result.Err)
+		assert.Contains(t, result.Err.Error(), "out of gas")
+	}
+	assert.Equal(t, 1, mc.BatchSize())
+}
+
+func TestTryAggregate_BatchFailureDoesNotShrink(t *testing.T) {
+	backend := newFakeMulticall3(t, 100)
+	mc, err := NewMulticall(&Config{BatchSize: 4}, backend)
+	require.NoError(t, err)
+
+	calls := newTestCalls(10)
+	calls[5].CallData = []byte{0xee}
+
+	results, err := mc.TryAggregate(nil, calls)
+	require.NoError(t, err)
+	require.Len(t, results, 10)
+	for i, result := range results {
+		if i >= 4 && i < 8 {
+			require.Error(t, result.Err)
+			assert.Contains(t, result.Err.Error(), "unknown block")
+			continue
+		}
+		assert.NoError(t, result.Err)
+		assert.Equal(t, []byte{0xaa, byte(i)}, result.ReturnData)
+	}
+	assert.Equal(t, []int{4, 4, 2}, backend.batchSizes)
+	assert.Equal(t, 4, mc.BatchSize())
+}
+
+func TestTryAggregate_CustomSplitOn(t *testing.T) {
+	backend := newFakeMulticall3(t, 100)
+	mc, err := NewMulticall(&Config{
+		BatchSize: 4,
+		SplitOn:   func(err error) bool { return strings.Contains(err.Error(), "unknown block") },
+	}, backend)
+	require.NoError(t, err)
+
+	calls := newTestCalls(4)
+	calls[0].CallData = []byte{0xee}
+
+	results, err := mc.TryAggregate(nil, calls)
+	require.NoError(t, err)
+	require.Error(t, results[0].Err)
+	for _, result := range results[1:] {
+		assert.NoError(t, result.Err)
+	}
+	assert.Equal(t, []int{4, 2, 1, 1, 2}, backend.batchSizes)
+}
+
+func TestTryAggregate_ContextCanceled(t *testing.T) {
+	backend := newFakeMulticall3(t, 100)
+	mc, err := NewMulticall(nil, backend)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = mc.TryAggregate(&bind.CallOpts{Context: ctx}, newTestCalls(3))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, backend.batchSizes)
+}
+
+func TestIsLimitError(t *testing.T) {
+	assert.True(t, IsLimitError(errors.New("out of gas")))
+	assert.True(t, IsLimitError(errors.New("gas required exceeds allowance (30000000)")))
+	assert.True(t, IsLimitError(errors.New("exceeds block gas limit")))
+	assert.True(t, IsLimitError(errors.New("response size exceeded")))
+	assert.True(t, IsLimitError(errors.New("query returned more than 10000 results")))
+	assert.False(t, IsLimitError(errors.New("unknown block")))
+	assert.False(t, IsLimitError(errors.New("execution reverted")))
+	assert.False(t, IsLimitError(errors.New("header for hash not found")))
+	assert.False(t, IsLimitError(errors.New("daily request limit exceeded")))
+}
+
+func TestRevertError(t *testing.T) {
+	assert.EqualError(t, RevertError(nil), "execution reverted")
+
+	// Error(string) with reason "bad"
+	reason, err := abi.Arguments{{Type: abi.Type{T: abi.StringTy}}}.Pack("bad")
+	require.NoError(t, err)
+	data := append([]byte{0x08, 0xc3, 0x79, 0xa0}, reason...)
+	assert.EqualError(t, RevertError(data), "execution reverted: bad")
+
+	assert.EqualError(t, RevertError([]byte{0xde, 0xad}), "execution reverted: 0xdead")
+}
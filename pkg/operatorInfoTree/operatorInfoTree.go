@@ -0,0 +1,258 @@
+// Package operatorInfoTree rebuilds the operator info tree of a BN254 operator set and
+// produces the Merkle proofs the BN254CertificateVerifier expects for non-signer witnesses.
+//
+// The tree's leaves are LeafCalculatorMixin.calculateOperatorInfoLeaf of each operator's
+// BN254OperatorInfo, in table order, padded with zero leaves to the next power of two and
+// hashed pairwise with keccak256, as Merkle.merkleizeKeccak does. A proof is the concatenation
+// of the 32-byte siblings from leaf to root, as Merkle.getProofKeccak returns it.
+package operatorInfoTree
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IBN254CertificateVerifier"
+	"github.com/Layr-Labs/multichain-go/pkg/merkle"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ErrRootMismatch is returned when the rebuilt tree's root differs from the table's
+	// operator info tree root
+	ErrRootMismatch = errors.New("operator info tree root does not match the operator table")
+	// ErrOperatorCountMismatch is returned when the number of operator infos differs from the
+	// table's operator count
+	ErrOperatorCountMismatch = errors.New("operator info count does not match the operator table")
+	// ErrInvalidProof is returned for proofs that are not a whole number of 32-byte siblings
+	// or do not consume the whole index, matching Merkle.processInclusionProofKeccak's reverts
+	ErrInvalidProof = errors.New("invalid operator info proof")
+)
+
+// Tree is the operator info tree of a BN254 operator set.
+type Tree struct {
+	operators []operatorTable.BN254OperatorInfo
+	// layers[0] are the padded leaves and the last layer holds the root
+	layers [][]common.Hash
+}
+
+// OperatorProof is an operator's leaf in the operator info tree and its inclusion proof.
+type OperatorProof struct {
+	// Index is the operator's leaf index, i.e. its position in the operator table
+	Index uint32 `json:"index"`
+	// OperatorInfo is the operator's pubkey and weights
+	OperatorInfo operatorTable.BN254OperatorInfo `json:"operatorInfo"`
+	// Leaf is the salted leaf hash of OperatorInfo
+	Leaf common.Hash `json:"leaf"`
+	// Proof is the concatenation of the leaf's siblings from the bottom of the tree up
+	Proof []byte `json:"proof"`
+}
+
+// New builds the operator info tree over operators, in table order. An empty operator set
+// has the zero root, as the table calculators emit it.
+//
+// Parameters:
+//   - operators: The operator infos in table order
+//
+// Returns:
+//   - *Tree: The tree
+//   - error: An error if an operator info cannot be encoded
+func New(operators []operatorTable.BN254OperatorInfo) (*Tree, error) {
+	if uint64(len(operators)) > math.MaxUint32 {
+		return nil, fmt.Errorf("too many operators for uint32 indices: %d", len(operators))
+	}
+	t := &Tree{operators: operators}
+	if len(operators) == 0 {
+		return t, nil
+	}
+
+	width := 1
+	for width < len(operators) {
+		width *= 2
+	}
+	leaves := make([]common.Hash, width)
+	for i := range operators {
+		leaf, err := operatorTable.BN254OperatorInfoLeaf(&operators[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate leaf of operator %d: %w", i, err)
+		}
+		leaves[i] = leaf
+	}
+	t.layers = [][]common.Hash{leaves}
+	for layer := leaves; len(layer) > 1; {
+		next := make([]common.Hash, len(layer)/2)
+		for i := range next {
+			next[i] = crypto.Keccak256Hash(layer[2*i][:], layer[2*i+1][:])
+		}
+		t.layers = append(t.layers, next)
+		layer = next
+	}
+	return t, nil
+}
+
+// NewForTable builds the operator info tree over operators and checks it against a decoded
+// BN254 table, so proofs are only produced for the tree the destination chain has stored.
+//
+// Parameters:
+//   - info: The BN254 operator set info from the operator table at the reference block
+//   - operators: The operator infos in table order
+//
+// Returns:
+//   - *Tree: The tree
+//   - error: An error wrapping ErrOperatorCountMismatch or ErrRootMismatch if the operators
+//     do not make up the table, or an error if an operator info cannot be encoded
+func NewForTable(info *operatorTable.BN254OperatorSetInfo, operators []operatorTable.BN254OperatorInfo) (*Tree, error) {
+	if info == nil {
+		return nil, fmt.Errorf("operator table has no BN254 operator set info")
+	}
+	numOperators := info.NumOperators
+	if numOperators == nil {
+		numOperators = new(big.Int)
+	}
+	if numOperators.Cmp(big.NewInt(int64(len(operators)))) != 0 {
+		return nil, fmt.Errorf("%w: got %d operator infos, table has %s", ErrOperatorCountMismatch, len(operators), numOperators)
+	}
+	t, err := New(operators)
+	if err != nil {
+		return nil, err
+	}
+	if t.Root() != info.OperatorInfoTreeRoot {
+		return nil, fmt.Errorf("%w: rebuilt %s, table has %s", ErrRootMismatch, t.Root().Hex(), info.OperatorInfoTreeRoot.Hex())
+	}
+	return t, nil
+}
+
+// Root returns the root of the tree, or the zero hash if it has no operators.
+func (t *Tree) Root() common.Hash {
+	if len(t.layers) == 0 {
+		return common.Hash{}
+	}
+	return t.layers[len(t.layers)-1][0]
+}
+
+// Len returns the number of operators in the tree.
+func (t *Tree) Len() int {
+	return len(t.operators)
+}
+
+// IndexOf returns the leaf index of the first operator with the given pubkey.
+//
+// Parameters:
+//   - pubkey: The operator's G1 pubkey
+//
+// Returns:
+//   - uint32: The operator's leaf index
+//   - bool: Whether an operator has the pubkey
+func (t *Tree) IndexOf(pubkey operatorTable.G1Point) (uint32, bool) {
+	for i, operator := range t.operators {
+		if operator.Pubkey.X.Cmp(pubkey.X) == 0 && operator.Pubkey.Y.Cmp(pubkey.Y) == 0 {
+			return uint32(i), true
+		}
+	}
+	return 0, false
+}
+
+// Proof returns the operator at index and its inclusion proof, matching Merkle.getProofKeccak.
+//
+// Parameters:
+//   - index: The operator's leaf index
+//
+// Returns:
+//   - *OperatorProof: The operator's leaf and proof
+//   - error: An error if index is out of range
+func (t *Tree) Proof(index uint32) (*OperatorProof, error) {
+	if int(index) >= len(t.operators) {
+		return nil, fmt.Errorf("operator index %d out of range for %d operators", index, len(t.operators))
+	}
+	proof := make([]byte, 0, common.HashLength*(len(t.layers)-1))
+	position := int(index)
+	for _, layer := range t.layers[:len(t.layers)-1] {
+		sibling := layer[position^1]
+		proof = append(proof, sibling[:]...)
+		position /= 2
+	}
+	return &OperatorProof{
+		Index:        index,
+		OperatorInfo: t.operators[index],
+		Leaf:         t.layers[0][index],
+		Proof:        proof,
+	}, nil
+}
+
+// Proofs returns every operator's inclusion proof, in table order.
+func (t *Tree) Proofs() []*OperatorProof {
+	proofs := make([]*OperatorProof, len(t.operators))
+	for i := range t.operators {
+		// Indices are in range by construction
+		proofs[i], _ = t.Proof(uint32(i))
+	}
+	return proofs
+}
+
+// Witness converts the proof to the non-signer witness the BN254CertificateVerifier takes.
+func (p *OperatorProof) Witness() IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254OperatorInfoWitness {
+	return IBN254CertificateVerifier.IBN254CertificateVerifierTypesBN254OperatorInfoWitness{
+		OperatorIndex:     p.Index,
+		OperatorInfoProof: p.Proof,
+		OperatorInfo: IBN254CertificateVerifier.IOperatorTableCalculatorTypesBN254OperatorInfo{
+			Pubkey: IBN254CertificateVerifier.BN254G1Point{
+				X: p.OperatorInfo.Pubkey.X,
+				Y: p.OperatorInfo.Pubkey.Y,
+			},
+			Weights: p.OperatorInfo.Weights,
+		},
+	}
+}
+
+// ProcessProof recomputes the root from a leaf and its inclusion proof, matching
+// Merkle.processInclusionProofKeccak. An empty proof yields the leaf itself.
+//
+// Parameters:
+//   - proof: The concatenated 32-byte siblings
+//   - leaf: The leaf hash
+//   - index: The leaf index
+//
+// Returns:
+//   - common.Hash: The computed root
+//   - error: An error wrapping ErrInvalidProof where the contract would revert
+func ProcessProof(proof []byte, leaf common.Hash, index uint32) (common.Hash, error) {
+	root, err := merkle.ProcessInclusionProofKeccak(proof, leaf, uint64(index))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+	return root, nil
+}
+
+// VerifyProof reports whether leaf is at index in the tree with the given root, matching
+// Merkle.verifyInclusionKeccak. The zero root never verifies, as the contract reverts on it.
+//
+// Parameters:
+//   - root: The operator info tree root
+//   - leaf: The leaf hash
+//   - index: The leaf index
+//   - proof: The concatenated 32-byte siblings
+//
+// Returns:
+//   - bool: Whether the proof is valid
+func VerifyProof(root, leaf common.Hash, index uint32, proof []byte) bool {
+	valid, err := merkle.VerifyInclusionKeccak(proof, root, leaf, uint64(index))
+	return err == nil && valid
+}
+
+// Verify reports whether the proof's operator info is included in the tree with the given root.
+//
+// Parameters:
+//   - root: The operator info tree root
+//
+// Returns:
+//   - bool: Whether the proof is valid
+func (p *OperatorProof) Verify(root common.Hash) bool {
+	leaf, err := operatorTable.BN254OperatorInfoLeaf(&p.OperatorInfo)
+	if err != nil {
+		return false
+	}
+	return VerifyProof(root, leaf, p.Index, p.Proof)
+}
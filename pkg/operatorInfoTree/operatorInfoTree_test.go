@@ -0,0 +1,158 @@
+package operatorInfoTree
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testOperators(n int) []operatorTable.BN254OperatorInfo {
+	operators := make([]operatorTable.BN254OperatorInfo, n)
+	for i := range operators {
+		operators[i] = operatorTable.BN254OperatorInfo{
+			Pubkey:  operatorTable.G1Point{X: big.NewInt(int64(2*i + 1)), Y: big.NewInt(int64(2*i + 2))},
+			Weights: []*big.Int{big.NewInt(int64(100 * (i + 1)))},
+		}
+	}
+	return operators
+}
+
+func leafOf(t *testing.T, info operatorTable.BN254OperatorInfo) common.Hash {
+	leaf, err := operatorTable.BN254OperatorInfoLeaf(&info)
+	require.NoError(t, err)
+	return leaf
+}
+
+func hash(left, right common.Hash) common.Hash {
+	return crypto.Keccak256Hash(left[:], right[:])
+}
+
+func TestRoot(t *testing.T) {
+	operators := testOperators(3)
+	a, b, c := leafOf(t, operators[0]), leafOf(t, operators[1]), leafOf(t, operators[2])
+
+	for _, tc := range []struct {
+		n    int
+		root common.Hash
+	}{
+		{0, common.Hash{}},
+		{1, a},
+		{2, hash(a, b)},
+		// Padded with a zero leaf to the next power of two
+		{3, hash(hash(a, b), hash(c, common.Hash{}))},
+	} {
+		tree, err := New(operators[:tc.n])
+		require.NoError(t, err)
+		assert.Equal(t, tc.root, tree.Root(), "%d operators", tc.n)
+		assert.Equal(t, tc.n, tree.Len())
+	}
+}
+
+func TestProof(t *testing.T) {
+	operators := testOperators(3)
+	tree, err := New(operators)
+	require.NoError(t, err)
+
+	// Siblings of leaf 2 are the zero padding leaf and hash(a, b)
+	proof, err := tree.Proof(2)
+	require.NoError(t, err)
+	ab := hash(leafOf(t, operators[0]), leafOf(t, operators[1]))
+	assert.Equal(t, append(common.Hash{}.Bytes(), ab[:]...), proof.Proof)
+	assert.Equal(t, leafOf(t, operators[2]), proof.Leaf)
+	assert.Equal(t, operators[2], proof.OperatorInfo)
+
+	_, err = tree.Proof(3)
+	assert.Error(t, err, "padding leaves have no proof")
+
+	single, err := New(operators[:1])
+	require.NoError(t, err)
+	proof, err = single.Proof(0)
+	require.NoError(t, err)
+	assert.Empty(t, proof.Proof)
+	assert.True(t, proof.Verify(single.Root()))
+}
+
+func TestProofs_VerifyAcrossSizes(t *testing.T) {
+	for n := 1; n <= 17; n++ {
+		tree, err := New(testOperators(n))
+		require.NoError(t, err)
+		proofs := tree.Proofs()
+		require.Len(t, proofs, n)
+		for i, proof := range proofs {
+			assert.Equal(t, uint32(i), proof.Index)
+			assert.True(t, proof.Verify(tree.Root()), "operator %d of %d", i, n)
+			if n > 1 {
+				assert.False(t, VerifyProof(tree.Root(), proof.Leaf, proof.Index^1, proof.Proof), "wrong index %d of %d", i, n)
+			}
+		}
+	}
+}
+
+func TestVerifyProof_Rejects(t *testing.T) {
+	operators := testOperators(4)
+	tree, err := New(operators)
+	require.NoError(t, err)
+	proof, err := tree.Proof(1)
+	require.NoError(t, err)
+
+	tampered := proof.OperatorInfo
+	tampered.Weights = []*big.Int{big.NewInt(1)}
+	assert.False(t, (&OperatorProof{Index: 1, OperatorInfo: tampered, Proof: proof.Proof}).Verify(tree.Root()))
+	assert.False(t, proof.Verify(common.Hash{}), "the zero root reverts on-chain")
+	assert.False(t, VerifyProof(tree.Root(), proof.Leaf, 1, proof.Proof[:40]))
+
+	// An index beyond the proof's depth is not consumed
+	_, err = ProcessProof(proof.Proof, proof.Leaf, 5)
+	assert.ErrorIs(t, err, ErrInvalidProof)
+	_, err = ProcessProof(proof.Proof[:33], proof.Leaf, 1)
+	assert.ErrorIs(t, err, ErrInvalidProof)
+}
+
+func TestNewForTable(t *testing.T) {
+	operators := testOperators(3)
+	tree, err := New(operators)
+	require.NoError(t, err)
+	info := &operatorTable.BN254OperatorSetInfo{
+		OperatorInfoTreeRoot: tree.Root(),
+		NumOperators:         big.NewInt(3),
+	}
+
+	_, err = NewForTable(info, operators)
+	require.NoError(t, err)
+
+	_, err = NewForTable(info, operators[:2])
+	assert.ErrorIs(t, err, ErrOperatorCountMismatch)
+
+	reordered := []operatorTable.BN254OperatorInfo{operators[1], operators[0], operators[2]}
+	_, err = NewForTable(info, reordered)
+	assert.ErrorIs(t, err, ErrRootMismatch)
+
+	_, err = NewForTable(&operatorTable.BN254OperatorSetInfo{NumOperators: big.NewInt(0)}, nil)
+	assert.NoError(t, err, "an empty operator set has the zero root")
+}
+
+func TestIndexOfAndWitness(t *testing.T) {
+	operators := testOperators(5)
+	tree, err := New(operators)
+	require.NoError(t, err)
+
+	index, ok := tree.IndexOf(operatorTable.G1Point{X: big.NewInt(7), Y: big.NewInt(8)})
+	require.True(t, ok)
+	assert.Equal(t, uint32(3), index)
+	_, ok = tree.IndexOf(operatorTable.G1Point{X: big.NewInt(7), Y: big.NewInt(9)})
+	assert.False(t, ok)
+
+	proof, err := tree.Proof(index)
+	require.NoError(t, err)
+	witness := proof.Witness()
+	assert.Equal(t, uint32(3), witness.OperatorIndex)
+	assert.Equal(t, proof.Proof, witness.OperatorInfoProof)
+	assert.Equal(t, big.NewInt(7), witness.OperatorInfo.Pubkey.X)
+	assert.Equal(t, big.NewInt(8), witness.OperatorInfo.Pubkey.Y)
+	assert.Equal(t, []*big.Int{big.NewInt(400)}, witness.OperatorInfo.Weights)
+}
@@ -0,0 +1,373 @@
+// Package operatorTable decodes the operator table bytes produced by the CrossChainRegistry's
+// calculateOperatorTableBytes into typed structures, and encodes them back.
+//
+// Operator table bytes are abi.encode(OperatorSet, CurveType, OperatorSetConfig, bytes), where
+// the trailing bytes are the curve-specific table produced by the operator set's table
+// calculator: abi.encode(BN254OperatorSetInfo) for BN254 and abi.encode(ECDSAOperatorInfo[])
+// for ECDSA. This mirrors OperatorTableUpdater._decodeOperatorTableBytes on the destination chain.
+package operatorTable
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// CurveType is the key type of an operator set, matching the KeyRegistrar's CurveType enum.
+type CurveType uint8
+
+const (
+	CurveTypeNone  CurveType = 0
+	CurveTypeECDSA CurveType = 1
+	CurveTypeBN254 CurveType = 2
+)
+
+// String returns the lowercase name of the curve type.
+func (c CurveType) String() string {
+	switch c {
+	case CurveTypeNone:
+		return "none"
+	case CurveTypeECDSA:
+		return "ecdsa"
+	case CurveTypeBN254:
+		return "bn254"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(c))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c CurveType) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *CurveType) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "none":
+		*c = CurveTypeNone
+	case "ecdsa":
+		*c = CurveTypeECDSA
+	case "bn254":
+		*c = CurveTypeBN254
+	default:
+		return fmt.Errorf("invalid curve type %q", string(text))
+	}
+	return nil
+}
+
+// OperatorSet identifies the operator set a table belongs to.
+type OperatorSet struct {
+	Avs common.Address `json:"avs"`
+	Id  uint32         `json:"id"`
+}
+
+// OperatorSetConfig is the operator set's configuration in the CrossChainRegistry.
+type OperatorSetConfig struct {
+	// Owner is the address allowed to update the operator set's configuration
+	Owner common.Address `json:"owner"`
+	// MaxStalenessPeriod is how long, in seconds, a table stays valid; 0 means it never goes stale
+	MaxStalenessPeriod uint32 `json:"maxStalenessPeriod"`
+}
+
+// G1Point is a point on the BN254 G1 curve.
+type G1Point struct {
+	X *big.Int `json:"x"`
+	Y *big.Int `json:"y"`
+}
+
+// BN254OperatorSetInfo is the table of a BN254 operator set.
+type BN254OperatorSetInfo struct {
+	// OperatorInfoTreeRoot is the Merkle root of the operators' BN254OperatorInfo leaves
+	OperatorInfoTreeRoot common.Hash `json:"operatorInfoTreeRoot"`
+	// NumOperators is the number of operators in the set
+	NumOperators *big.Int `json:"numOperators"`
+	// AggregatePubkey is the sum of the operators' G1 public keys
+	AggregatePubkey G1Point `json:"aggregatePubkey"`
+	// TotalWeights are the operators' summed weights, one per weight type
+	TotalWeights []*big.Int `json:"totalWeights"`
+}
+
+// OPERATOR_INFO_LEAF_SALT is the salt prepended to BN254 operator info leaves, matching
+// LeafCalculatorMixin.
+const OPERATOR_INFO_LEAF_SALT = 0x75
+
+// BN254OperatorInfo is a single operator's leaf in a BN254 operator set's operator info tree.
+type BN254OperatorInfo struct {
+	// Pubkey is the operator's G1 public key
+	Pubkey G1Point `json:"pubkey"`
+	// Weights are the operator's weights, one per weight type
+	Weights []*big.Int `json:"weights"`
+}
+
+// ECDSAOperatorInfo is a single operator in the table of an ECDSA operator set.
+type ECDSAOperatorInfo struct {
+	// Pubkey is the operator's signing address
+	Pubkey common.Address `json:"pubkey"`
+	// Weights are the operator's weights, one per weight type
+	Weights []*big.Int `json:"weights"`
+}
+
+// OperatorTable is a decoded operator table.
+type OperatorTable struct {
+	// OperatorSet is the operator set the table belongs to
+	OperatorSet OperatorSet `json:"operatorSet"`
+	// CurveType is the operator set's key type
+	CurveType CurveType `json:"curveType"`
+	// Config is the operator set's configuration
+	Config OperatorSetConfig `json:"config"`
+	// OperatorTableInfo is the raw curve-specific table
+	OperatorTableInfo hexutil.Bytes `json:"operatorTableInfo"`
+	// BN254 is the decoded table when CurveType is CurveTypeBN254
+	BN254 *BN254OperatorSetInfo `json:"bn254,omitempty"`
+	// ECDSA is the decoded table when CurveType is CurveTypeECDSA
+	ECDSA []ECDSAOperatorInfo `json:"ecdsa,omitempty"`
+}
+
+var (
+	operatorTableArgs        abi.Arguments
+	bn254OperatorSetInfoArgs abi.Arguments
+	bn254OperatorInfoArgs    abi.Arguments
+	ecdsaOperatorInfosArgs   abi.Arguments
+)
+
+func init() {
+	operatorSetType := mustNewType("tuple", []abi.ArgumentMarshaling{
+		{Name: "avs", Type: "address"},
+		{Name: "id", Type: "uint32"},
+	})
+	operatorSetConfigType := mustNewType("tuple", []abi.ArgumentMarshaling{
+		{Name: "owner", Type: "address"},
+		{Name: "maxStalenessPeriod", Type: "uint32"},
+	})
+	operatorTableArgs = abi.Arguments{
+		{Type: operatorSetType},
+		{Type: mustNewType("uint8", nil)},
+		{Type: operatorSetConfigType},
+		{Type: mustNewType("bytes", nil)},
+	}
+
+	bn254OperatorSetInfoArgs = abi.Arguments{
+		{Type: mustNewType("tuple", []abi.ArgumentMarshaling{
+			{Name: "operatorInfoTreeRoot", Type: "bytes32"},
+			{Name: "numOperators", Type: "uint256"},
+			{Name: "aggregatePubkey", Type: "tuple", Components: []abi.ArgumentMarshaling{
+				{Name: "X", Type: "uint256"},
+				{Name: "Y", Type: "uint256"},
+			}},
+			{Name: "totalWeights", Type: "uint256[]"},
+		})},
+	}
+
+	bn254OperatorInfoArgs = abi.Arguments{
+		{Type: mustNewType("tuple", []abi.ArgumentMarshaling{
+			{Name: "pubkey", Type: "tuple", Components: []abi.ArgumentMarshaling{
+				{Name: "X", Type: "uint256"},
+				{Name: "Y", Type: "uint256"},
+			}},
+			{Name: "weights", Type: "uint256[]"},
+		})},
+	}
+
+	ecdsaOperatorInfosArgs = abi.Arguments{
+		{Type: mustNewType("tuple[]", []abi.ArgumentMarshaling{
+			{Name: "pubkey", Type: "address"},
+			{Name: "weights", Type: "uint256[]"},
+		})},
+	}
+}
+
+func mustNewType(t string, components []abi.ArgumentMarshaling) abi.Type {
+	typ, err := abi.NewType(t, "", components)
+	if err != nil {
+		panic(fmt.Sprintf("invalid ABI type %s: %v", t, err))
+	}
+	return typ
+}
+
+// Decode decodes operator table bytes, including the curve-specific table for BN254 and
+// ECDSA operator sets.
+//
+// Parameters:
+//   - tableBytes: The operator table bytes returned by CalculateOperatorTableBytes
+//
+// Returns:
+//   - *OperatorTable: The decoded operator table
+//   - error: An error if the bytes, or the curve-specific table they contain, cannot be decoded
+func Decode(tableBytes []byte) (*OperatorTable, error) {
+	values, err := operatorTableArgs.Unpack(tableBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode operator table: %w", err)
+	}
+	table := &OperatorTable{
+		OperatorSet:       *abi.ConvertType(values[0], new(OperatorSet)).(*OperatorSet),
+		CurveType:         CurveType(values[1].(uint8)),
+		Config:            *abi.ConvertType(values[2], new(OperatorSetConfig)).(*OperatorSetConfig),
+		OperatorTableInfo: values[3].([]byte),
+	}
+
+	switch table.CurveType {
+	case CurveTypeBN254:
+		info, err := DecodeBN254OperatorSetInfo(table.OperatorTableInfo)
+		if err != nil {
+			return nil, err
+		}
+		table.BN254 = info
+	case CurveTypeECDSA:
+		infos, err := DecodeECDSAOperatorInfos(table.OperatorTableInfo)
+		if err != nil {
+			return nil, err
+		}
+		table.ECDSA = infos
+	}
+	return table, nil
+}
+
+// Encode encodes an operator table into operator table bytes. For BN254 and ECDSA tables the
+// curve-specific table is encoded from the BN254 or ECDSA field; otherwise OperatorTableInfo
+// is used as is.
+//
+// Parameters:
+//   - table: The operator table to encode
+//
+// Returns:
+//   - []byte: The operator table bytes
+//   - error: An error if the table cannot be encoded
+func Encode(table *OperatorTable) ([]byte, error) {
+	info := []byte(table.OperatorTableInfo)
+	var err error
+	switch table.CurveType {
+	case CurveTypeBN254:
+		if table.BN254 == nil {
+			return nil, fmt.Errorf("BN254 operator table has no BN254 operator set info")
+		}
+		if info, err = EncodeBN254OperatorSetInfo(table.BN254); err != nil {
+			return nil, err
+		}
+	case CurveTypeECDSA:
+		if info, err = EncodeECDSAOperatorInfos(table.ECDSA); err != nil {
+			return nil, err
+		}
+	}
+	if info == nil {
+		info = []byte{}
+	}
+
+	encoded, err := operatorTableArgs.Pack(table.OperatorSet, uint8(table.CurveType), table.Config, info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode operator table: %w", err)
+	}
+	return encoded, nil
+}
+
+// DecodeBN254OperatorSetInfo decodes the curve-specific table of a BN254 operator set.
+//
+// Parameters:
+//   - data: The ABI-encoded BN254OperatorSetInfo
+//
+// Returns:
+//   - *BN254OperatorSetInfo: The decoded operator set info
+//   - error: An error if the data cannot be decoded
+func DecodeBN254OperatorSetInfo(data []byte) (*BN254OperatorSetInfo, error) {
+	values, err := bn254OperatorSetInfoArgs.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode BN254 operator set info: %w", err)
+	}
+	return abi.ConvertType(values[0], new(BN254OperatorSetInfo)).(*BN254OperatorSetInfo), nil
+}
+
+// EncodeBN254OperatorSetInfo encodes the curve-specific table of a BN254 operator set.
+//
+// Parameters:
+//   - info: The operator set info to encode
+//
+// Returns:
+//   - []byte: The ABI-encoded BN254OperatorSetInfo
+//   - error: An error if the info cannot be encoded
+func EncodeBN254OperatorSetInfo(info *BN254OperatorSetInfo) ([]byte, error) {
+	normalized := *info
+	if normalized.TotalWeights == nil {
+		normalized.TotalWeights = []*big.Int{}
+	}
+	encoded, err := bn254OperatorSetInfoArgs.Pack(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode BN254 operator set info: %w", err)
+	}
+	return encoded, nil
+}
+
+// EncodeBN254OperatorInfo encodes a BN254 operator info as abi.encode(BN254OperatorInfo).
+//
+// Parameters:
+//   - info: The operator info to encode
+//
+// Returns:
+//   - []byte: The ABI-encoded BN254OperatorInfo
+//   - error: An error if the info cannot be encoded
+func EncodeBN254OperatorInfo(info *BN254OperatorInfo) ([]byte, error) {
+	normalized := *info
+	if normalized.Weights == nil {
+		normalized.Weights = []*big.Int{}
+	}
+	encoded, err := bn254OperatorInfoArgs.Pack(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode BN254 operator info: %w", err)
+	}
+	return encoded, nil
+}
+
+// BN254OperatorInfoLeaf returns the operator info tree leaf of a BN254 operator, matching
+// LeafCalculatorMixin.calculateOperatorInfoLeaf: keccak256(OPERATOR_INFO_LEAF_SALT || abi.encode(info)).
+//
+// Parameters:
+//   - info: The operator info
+//
+// Returns:
+//   - common.Hash: The leaf
+//   - error: An error if the info cannot be encoded
+func BN254OperatorInfoLeaf(info *BN254OperatorInfo) (common.Hash, error) {
+	encoded, err := EncodeBN254OperatorInfo(info)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash([]byte{OPERATOR_INFO_LEAF_SALT}, encoded), nil
+}
+
+// DecodeECDSAOperatorInfos decodes the curve-specific table of an ECDSA operator set.
+//
+// Parameters:
+//   - data: The ABI-encoded ECDSAOperatorInfo[]
+//
+// Returns:
+//   - []ECDSAOperatorInfo: The decoded operators, in table order
+//   - error: An error if the data cannot be decoded
+func DecodeECDSAOperatorInfos(data []byte) ([]ECDSAOperatorInfo, error) {
+	values, err := ecdsaOperatorInfosArgs.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode ECDSA operator infos: %w", err)
+	}
+	return *abi.ConvertType(values[0], new([]ECDSAOperatorInfo)).(*[]ECDSAOperatorInfo), nil
+}
+
+// EncodeECDSAOperatorInfos encodes the curve-specific table of an ECDSA operator set.
+//
+// Parameters:
+//   - infos: The operators to encode, in table order
+//
+// Returns:
+//   - []byte: The ABI-encoded ECDSAOperatorInfo[]
+//   - error: An error if the operators cannot be encoded
+func EncodeECDSAOperatorInfos(infos []ECDSAOperatorInfo) ([]byte, error) {
+	if infos == nil {
+		infos = []ECDSAOperatorInfo{}
+	}
+	encoded, err := ecdsaOperatorInfosArgs.Pack(infos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode ECDSA operator infos: %w", err)
+	}
+	return encoded, nil
+}
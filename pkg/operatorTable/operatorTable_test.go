@@ -0,0 +1,166 @@
+package operatorTable
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBN254Table() *OperatorTable {
+	return &OperatorTable{
+		OperatorSet: OperatorSet{Avs: common.HexToAddress("0x1234"), Id: 7},
+		CurveType:   CurveTypeBN254,
+		Config: OperatorSetConfig{
+			Owner:              common.HexToAddress("0xabcd"),
+			MaxStalenessPeriod: 86400,
+		},
+		BN254: &BN254OperatorSetInfo{
+			OperatorInfoTreeRoot: common.HexToHash("0xdeadbeef"),
+			NumOperators:         big.NewInt(3),
+			AggregatePubkey:      G1Point{X: big.NewInt(11), Y: big.NewInt(22)},
+			TotalWeights:         []*big.Int{big.NewInt(1000), big.NewInt(2000)},
+		},
+	}
+}
+
+func newECDSATable() *OperatorTable {
+	return &OperatorTable{
+		OperatorSet: OperatorSet{Avs: common.HexToAddress("0x5678"), Id: 1},
+		CurveType:   CurveTypeECDSA,
+		Config: OperatorSetConfig{
+			Owner:              common.HexToAddress("0xef01"),
+			MaxStalenessPeriod: 0,
+		},
+		ECDSA: []ECDSAOperatorInfo{
+			{Pubkey: common.HexToAddress("0x01"), Weights: []*big.Int{big.NewInt(5)}},
+			{Pubkey: common.HexToAddress("0x02"), Weights: []*big.Int{big.NewInt(7), big.NewInt(9)}},
+		},
+	}
+}
+
+func TestEncodeDecode_BN254(t *testing.T) {
+	table := newBN254Table()
+
+	encoded, err := Encode(table)
+	require.NoError(t, err)
+
+	decoded, err := Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, table.OperatorSet, decoded.OperatorSet)
+	assert.Equal(t, CurveTypeBN254, decoded.CurveType)
+	assert.Equal(t, table.Config, decoded.Config)
+	assert.Equal(t, table.BN254, decoded.BN254)
+	assert.Nil(t, decoded.ECDSA)
+
+	info, err := EncodeBN254OperatorSetInfo(table.BN254)
+	require.NoError(t, err)
+	assert.Equal(t, info, []byte(decoded.OperatorTableInfo))
+
+	reencoded, err := Encode(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, encoded, reencoded)
+}
+
+func TestEncodeDecode_ECDSA(t *testing.T) {
+	table := newECDSATable()
+
+	encoded, err := Encode(table)
+	require.NoError(t, err)
+
+	decoded, err := Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, table.OperatorSet, decoded.OperatorSet)
+	assert.Equal(t, CurveTypeECDSA, decoded.CurveType)
+	assert.Equal(t, table.Config, decoded.Config)
+	assert.Equal(t, table.ECDSA, decoded.ECDSA)
+	assert.Nil(t, decoded.BN254)
+
+	reencoded, err := Encode(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, encoded, reencoded)
+}
+
+func TestEncodeDecode_UnknownCurveKeepsRawInfo(t *testing.T) {
+	table := &OperatorTable{
+		OperatorSet:       OperatorSet{Avs: common.HexToAddress("0x1234"), Id: 2},
+		CurveType:         CurveTypeNone,
+		OperatorTableInfo: []byte{0x01, 0x02, 0x03},
+	}
+
+	encoded, err := Encode(table)
+	require.NoError(t, err)
+
+	decoded, err := Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, table.OperatorTableInfo, decoded.OperatorTableInfo)
+	assert.Nil(t, decoded.BN254)
+	assert.Nil(t, decoded.ECDSA)
+}
+
+func TestEncode_CurveTypeWord(t *testing.T) {
+	// The curve type is the third word of the table bytes
+	bn254Bytes, err := Encode(newBN254Table())
+	require.NoError(t, err)
+	assert.Equal(t, byte(CurveTypeBN254), bn254Bytes[95])
+
+	ecdsaBytes, err := Encode(newECDSATable())
+	require.NoError(t, err)
+	assert.Equal(t, byte(CurveTypeECDSA), ecdsaBytes[95])
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	_, err := Decode([]byte{0x01, 0x02})
+	assert.Error(t, err)
+
+	// A BN254 table whose curve-specific bytes are not a BN254OperatorSetInfo
+	table := &OperatorTable{CurveType: CurveTypeNone, OperatorTableInfo: []byte{0x01}}
+	encoded, err := Encode(table)
+	require.NoError(t, err)
+	encoded[3*32-1] = byte(CurveTypeBN254)
+	_, err = Decode(encoded)
+	assert.ErrorContains(t, err, "failed to decode BN254 operator set info")
+
+	_, err = Encode(&OperatorTable{CurveType: CurveTypeBN254})
+	assert.Error(t, err)
+}
+
+func TestOperatorTable_JSON(t *testing.T) {
+	table := newBN254Table()
+	encoded, err := Encode(table)
+	require.NoError(t, err)
+	decoded, err := Decode(encoded)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"curveType":"bn254"`)
+
+	var roundTripped OperatorTable
+	require.NoError(t, json.Unmarshal(data, &roundTripped))
+	assert.Equal(t, *decoded, roundTripped)
+}
+
+func TestBN254OperatorInfoLeaf(t *testing.T) {
+	info := &BN254OperatorInfo{
+		Pubkey:  G1Point{X: big.NewInt(11), Y: big.NewInt(22)},
+		Weights: []*big.Int{big.NewInt(100), big.NewInt(200)},
+	}
+
+	// abi.encode of a dynamic struct: offset, X, Y, weights offset, weights length, weights
+	var expected []byte
+	for _, word := range []int64{0x20, 11, 22, 0x60, 2, 100, 200} {
+		expected = append(expected, common.LeftPadBytes(big.NewInt(word).Bytes(), 32)...)
+	}
+	encoded, err := EncodeBN254OperatorInfo(info)
+	require.NoError(t, err)
+	assert.Equal(t, expected, encoded)
+
+	leaf, err := BN254OperatorInfoLeaf(info)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.Keccak256Hash(append([]byte{OPERATOR_INFO_LEAF_SALT}, expected...)), leaf)
+}
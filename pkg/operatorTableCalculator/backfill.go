@@ -0,0 +1,237 @@
+package operatorTableCalculator
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/Layr-Labs/multichain-go/pkg/tracing"
+	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// BackfillRange selects the reference blocks sampled by CalculateStakeTableRootRange.
+type BackfillRange struct {
+	// From is the first block sampled
+	From uint64
+	// To is the last block that may be sampled; it is included when it falls on a step
+	To uint64
+	// Step is the distance between sampled blocks. Values below 1 sample every block.
+	Step uint64
+	// Parallelism is the maximum number of blocks calculated concurrently. Values below 1
+	// calculate one block at a time. Config.Parallelism still bounds the calls made per block.
+	Parallelism int
+	// ChangeSource, when set, finds the operator sets whose tables may have changed since the
+	// previous sampled block. The tables of the others are reused instead of being calculated
+	// again. Blocks are then split into Parallelism runs of consecutive blocks, each calculated
+	// in order.
+	ChangeSource OperatorSetChangeSource
+}
+
+// OperatorSetChangeSource finds the operator sets whose tables may differ between two blocks.
+// See localTableCalculator.ChangeDetector for an implementation based on L1 events.
+type OperatorSetChangeSource interface {
+	// ChangedOperatorSets reports, for each opset, whether its table at to may differ from its
+	// table at from. Reporting an unchanged operator set as changed only costs a calculation;
+	// the reverse puts a stale table in the tree.
+	ChangedOperatorSets(ctx context.Context, from, to *snapshot.Snapshot, opsets []ICrossChainRegistry.OperatorSet) ([]bool, error)
+}
+
+// Blocks returns the sampled block numbers in ascending order.
+//
+// Returns:
+//   - []uint64: The block numbers From, From+Step, ... up to and including To
+func (r BackfillRange) Blocks() []uint64 {
+	step := r.Step
+	if step < 1 {
+		step = 1
+	}
+	var blocks []uint64
+	for block := r.From; block <= r.To; block += step {
+		blocks = append(blocks, block)
+		if r.To-block < step {
+			break
+		}
+	}
+	return blocks
+}
+
+// RootSample is the global table root calculated at one sampled block.
+type RootSample struct {
+	// BlockNumber is the reference block number
+	BlockNumber uint64 `json:"blockNumber"`
+	// BlockHash is the reference block hash
+	BlockHash common.Hash `json:"blockHash"`
+	// Timestamp is the reference block timestamp
+	Timestamp uint64 `json:"timestamp"`
+	// Root is the calculated global table root
+	Root common.Hash `json:"root"`
+	// OperatorSetCount is the number of operator sets in the tree
+	OperatorSetCount int `json:"operatorSetCount"`
+	// SkippedCount is the number of reservations whose table could not be calculated
+	SkippedCount int `json:"skippedCount"`
+	// ReusedCount is the number of tables reused from the previous sampled block
+	ReusedCount int `json:"reusedCount"`
+}
+
+// CalculateStakeTableRootRange calculates the global table root at every block sampled by r,
+// with each block's reads pinned to its hash. Without r.ChangeSource each block is a full
+// calculation. With it, only the operator sets it reports as changed since the previous block
+// call CalculateOperatorTableBytes; the others reuse their previous table and leaf. The
+// reservations are still read at every block, and the first block of each run is calculated
+// in full.
+//
+// Parameters:
+//   - ctx: Context for the calculations
+//   - r: The blocks to sample
+//
+// Returns:
+//   - []RootSample: One sample per block, in block order
+//   - error: An error naming the first block that could not be calculated
+func (c *StakeTableCalculator) CalculateStakeTableRootRange(ctx context.Context, r BackfillRange) ([]RootSample, error) {
+	if r.To < r.From {
+		return nil, fmt.Errorf("invalid block range: to block %d is before from block %d", r.To, r.From)
+	}
+	blocks := r.Blocks()
+
+	ctx, span := tracing.Start(ctx, c.tracer, "StakeTableCalculator.CalculateStakeTableRootRange",
+		attribute.Int64("fromBlockNumber", int64(r.From)),
+		attribute.Int64("toBlockNumber", int64(r.To)),
+		attribute.Int("sampleCount", len(blocks)),
+	)
+
+	parallelism := r.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	runs := backfillRuns(len(blocks), parallelism, r.ChangeSource != nil)
+	samples := make([]RootSample, len(blocks))
+	errs := make([]error, len(blocks))
+
+	// Stop starting new blocks once one has failed
+	var failed atomic.Bool
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for _, run := range runs {
+		sem <- struct{}{}
+		if failed.Load() {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(run []int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var reuse *tableReuse
+			if r.ChangeSource != nil {
+				reuse = &tableReuse{source: r.ChangeSource}
+			}
+			for _, i := range run {
+				if failed.Load() {
+					return
+				}
+				samples[i], errs[i] = c.calculateRootSample(ctx, blocks[i], reuse)
+				if errs[i] != nil {
+					failed.Store(true)
+					return
+				}
+			}
+		}(run)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			err = fmt.Errorf("failed to calculate stake table root at block %d: %w", blocks[i], err)
+			tracing.End(span, err)
+			return nil, err
+		}
+	}
+	tracing.End(span, nil)
+	return samples, nil
+}
+
+// backfillRuns splits the indices of n blocks into runs that are calculated in order. Without
+// reuse every block is its own run; with it, the blocks are split into at most parallelism runs
+// of consecutive blocks.
+func backfillRuns(n int, parallelism int, reuse bool) [][]int {
+	runCount := n
+	if reuse {
+		runCount = min(parallelism, n)
+	}
+	runs := make([][]int, 0, runCount)
+	for r := 0; r < runCount; r++ {
+		var run []int
+		for i := r * n / runCount; i < (r+1)*n/runCount; i++ {
+			run = append(run, i)
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// calculateRootSample resolves a block and calculates its root, reusing the tables of the
+// previous block in the run where reuse allows it.
+func (c *StakeTableCalculator) calculateRootSample(ctx context.Context, block uint64, reuse *tableReuse) (RootSample, error) {
+	snap, err := snapshot.Resolve(ctx, c.ethClient, new(big.Int).SetUint64(block))
+	if err != nil {
+		return RootSample{}, err
+	}
+	report := newCalculationReport(snap.Number, snap.Hash)
+	reuse.start()
+	root, _, dist, err := c.calculateStakeTableRoot(ctx, snap.CallOpts(ctx), snap.Number, snap, report, reuse)
+	if err != nil {
+		return RootSample{}, err
+	}
+	reuse.finish(snap)
+	return RootSample{
+		BlockNumber:      snap.Number,
+		BlockHash:        snap.Hash,
+		Timestamp:        snap.Timestamp,
+		Root:             common.Hash(root),
+		OperatorSetCount: len(dist.GetOperatorSets()),
+		SkippedCount:     len(report.Skipped),
+		ReusedCount:      reuse.reusedCount(),
+	}, nil
+}
+
+// WriteRootSamplesCSV writes samples as CSV with a header row.
+//
+// Parameters:
+//   - w: The destination
+//   - samples: The samples to write
+//
+// Returns:
+//   - error: An error if writing fails
+func WriteRootSamplesCSV(w io.Writer, samples []RootSample) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"blockNumber", "blockHash", "timestamp", "root", "operatorSetCount", "skippedCount", "reusedCount"}); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, sample := range samples {
+		if err := writer.Write([]string{
+			strconv.FormatUint(sample.BlockNumber, 10),
+			sample.BlockHash.Hex(),
+			strconv.FormatUint(sample.Timestamp, 10),
+			sample.Root.Hex(),
+			strconv.Itoa(sample.OperatorSetCount),
+			strconv.Itoa(sample.SkippedCount),
+			strconv.Itoa(sample.ReusedCount),
+		}); err != nil {
+			return fmt.Errorf("failed to write CSV row for block %d: %w", sample.BlockNumber, err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package operatorTableCalculator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillRange_Blocks(t *testing.T) {
+	tests := []struct {
+		name string
+		r    BackfillRange
+		want []uint64
+	}{
+		{name: "single block", r: BackfillRange{From: 5, To: 5, Step: 10}, want: []uint64{5}},
+		{name: "to on step", r: BackfillRange{From: 100, To: 120, Step: 10}, want: []uint64{100, 110, 120}},
+		{name: "to between steps", r: BackfillRange{From: 100, To: 125, Step: 10}, want: []uint64{100, 110, 120}},
+		{name: "zero step", r: BackfillRange{From: 1, To: 3}, want: []uint64{1, 2, 3}},
+		{name: "near max", r: BackfillRange{From: ^uint64(0) - 1, To: ^uint64(0), Step: 5}, want: []uint64{^uint64(0) - 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.r.Blocks())
+		})
+	}
+}
+
+func TestCalculateStakeTableRootRange(t *testing.T) {
+	for _, parallelism := range []int{1, 3} {
+		calculator, mockRegistryCaller, mockEthClient := setupTestCalculator(t)
+		opsets := createTestOperatorSets(2)
+		table := encodeECDSATable(t, 1, 10)
+
+		var headers []*types.Header
+		for _, block := range []int64{100, 110, 120} {
+			header := &types.Header{Number: big.NewInt(block), Time: uint64(1700000000 + block*12)}
+			headers = append(headers, header)
+			opts := &bind.CallOpts{Context: context.Background(), BlockHash: header.Hash()}
+			mockEthClient.On("HeaderByNumber", mock.Anything, big.NewInt(block)).Return(header, nil)
+
+			if block < 120 {
+				mockRegistryCaller.On("GetActiveGenerationReservationCount", opts).Return(big.NewInt(1), nil)
+				mockRegistryCaller.On("GetActiveGenerationReservationsByRange", opts, big.NewInt(0), big.NewInt(1)).
+					Return(opsets[:1], nil)
+			} else {
+				// The second operator set is registered but its calculator reverts
+				mockRegistryCaller.On("GetActiveGenerationReservationCount", opts).Return(big.NewInt(2), nil)
+				mockRegistryCaller.On("GetActiveGenerationReservationsByRange", opts, big.NewInt(0), big.NewInt(2)).
+					Return(opsets, nil)
+				mockRegistryCaller.On("CalculateOperatorTableBytes", opts, opsets[1]).
+					Return(nil, errors.New("execution reverted"))
+			}
+			mockRegistryCaller.On("CalculateOperatorTableBytes", opts, opsets[0]).Return(table, nil)
+		}
+
+		samples, err := calculator.CalculateStakeTableRootRange(context.Background(), BackfillRange{
+			From:        100,
+			To:          125,
+			Step:        10,
+			Parallelism: parallelism,
+		})
+		require.NoError(t, err)
+		require.Len(t, samples, 3)
+
+		for i, sample := range samples {
+			assert.Equal(t, headers[i].Number.Uint64(), sample.BlockNumber)
+			assert.Equal(t, headers[i].Hash(), sample.BlockHash)
+			assert.Equal(t, headers[i].Time, sample.Timestamp)
+			assert.Equal(t, 1, sample.OperatorSetCount)
+			assert.Equal(t, samples[0].Root, sample.Root, "unchanged table gives the same root")
+		}
+		assert.NotEqual(t, [32]byte{}, samples[0].Root)
+		assert.Equal(t, 0, samples[1].SkippedCount)
+		assert.Equal(t, 1, samples[2].SkippedCount)
+	}
+}
+
+func TestCalculateStakeTableRootRange_Errors(t *testing.T) {
+	calculator, _, mockEthClient := setupTestCalculator(t)
+
+	_, err := calculator.CalculateStakeTableRootRange(context.Background(), BackfillRange{From: 10, To: 5})
+	assert.ErrorContains(t, err, "invalid block range")
+
+	mockEthClient.On("HeaderByNumber", mock.Anything, big.NewInt(10)).Return(nil, errors.New("missing trie node"))
+	_, err = calculator.CalculateStakeTableRootRange(context.Background(), BackfillRange{From: 10, To: 20, Step: 10})
+	assert.ErrorContains(t, err, "block 10")
+	assert.ErrorContains(t, err, "missing trie node")
+}
+
+// fakeChangeSource reports the opsets listed for the later block as changed, and fails for
+// the blocks in errs.
+type fakeChangeSource struct {
+	mu      sync.Mutex
+	changed map[uint64][]uint32
+	errs    map[uint64]error
+	calls   [][2]uint64
+}
+
+func (f *fakeChangeSource) ChangedOperatorSets(_ context.Context, from, to *snapshot.Snapshot, opsets []ICrossChainRegistry.OperatorSet) ([]bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, [2]uint64{from.Number, to.Number})
+	if err := f.errs[to.Number]; err != nil {
+		return nil, err
+	}
+	changed := make([]bool, len(opsets))
+	for i, opset := range opsets {
+		for _, id := range f.changed[to.Number] {
+			changed[i] = changed[i] || opset.Id == id
+		}
+	}
+	return changed, nil
+}
+
+// TestCalculateStakeTableRootRange_ReusesUnchangedTables verifies that with a change source
+// only changed operator sets, and those skipped at the previous block, are calculated again,
+// and that the roots match a backfill that calculates every table.
+func TestCalculateStakeTableRootRange_ReusesUnchangedTables(t *testing.T) {
+	blocks := []int64{100, 110, 120, 130}
+	setup := func(t *testing.T) (*StakeTableCalculator, *MockICrossChainRegistryCaller) {
+		calculator, mockRegistryCaller, mockEthClient := setupTestCalculator(t)
+		opsets := createTestOperatorSets(3)
+		for _, block := range blocks {
+			header := &types.Header{Number: big.NewInt(block), Time: uint64(1700000000 + block*12)}
+			opts := &bind.CallOpts{Context: context.Background(), BlockHash: header.Hash()}
+			mockEthClient.On("HeaderByNumber", mock.Anything, big.NewInt(block)).Return(header, nil)
+			mockRegistryCaller.On("GetActiveGenerationReservationCount", opts).Return(big.NewInt(3), nil)
+			mockRegistryCaller.On("GetActiveGenerationReservationsByRange", opts, big.NewInt(0), big.NewInt(3)).
+				Return(opsets, nil)
+
+			mockRegistryCaller.On("CalculateOperatorTableBytes", opts, opsets[0]).Return(encodeECDSATable(t, 1, 10), nil).Maybe()
+			// The second operator set's stake changes at block 110
+			weight := int64(20)
+			if block >= 110 {
+				weight = 25
+			}
+			mockRegistryCaller.On("CalculateOperatorTableBytes", opts, opsets[1]).Return(encodeECDSATable(t, 2, weight), nil).Maybe()
+			// The third operator set's calculator always reverts
+			mockRegistryCaller.On("CalculateOperatorTableBytes", opts, opsets[2]).Return(nil, errors.New("execution reverted")).Maybe()
+		}
+		return calculator, mockRegistryCaller
+	}
+
+	full, fullRegistry := setup(t)
+	want, err := full.CalculateStakeTableRootRange(context.Background(), BackfillRange{From: 100, To: 130, Step: 10})
+	require.NoError(t, err)
+	fullRegistry.AssertNumberOfCalls(t, "CalculateOperatorTableBytes", 12)
+
+	t.Run("in order", func(t *testing.T) {
+		calculator, mockRegistryCaller := setup(t)
+		source := &fakeChangeSource{
+			changed: map[uint64][]uint32{110: {2}},
+			errs:    map[uint64]error{120: errors.New("log range too large")},
+		}
+		samples, err := calculator.CalculateStakeTableRootRange(context.Background(), BackfillRange{
+			From:         100,
+			To:           130,
+			Step:         10,
+			ChangeSource: source,
+		})
+		require.NoError(t, err)
+
+		// 100: every table. 110: the changed opset and the skipped one. 120: every table, as the
+		// change source failed. 130: only the skipped opset.
+		mockRegistryCaller.AssertNumberOfCalls(t, "CalculateOperatorTableBytes", 3+2+3+1)
+		assert.Equal(t, [][2]uint64{{100, 110}, {110, 120}, {120, 130}}, source.calls)
+
+		require.Len(t, samples, len(want))
+		for i, sample := range samples {
+			assert.Equal(t, want[i].Root, sample.Root, "block %d", sample.BlockNumber)
+			assert.Equal(t, 2, sample.OperatorSetCount)
+			assert.Equal(t, 1, sample.SkippedCount)
+		}
+		assert.NotEqual(t, samples[0].Root, samples[1].Root)
+		assert.Equal(t, []int{0, 1, 0, 2}, []int{samples[0].ReusedCount, samples[1].ReusedCount, samples[2].ReusedCount, samples[3].ReusedCount})
+	})
+
+	t.Run("parallel runs", func(t *testing.T) {
+		calculator, mockRegistryCaller := setup(t)
+		source := &fakeChangeSource{changed: map[uint64][]uint32{110: {2}}}
+		samples, err := calculator.CalculateStakeTableRootRange(context.Background(), BackfillRange{
+			From:         100,
+			To:           130,
+			Step:         10,
+			Parallelism:  2,
+			ChangeSource: source,
+		})
+		require.NoError(t, err)
+
+		// Runs 100-110 and 120-130 each start with a full calculation
+		mockRegistryCaller.AssertNumberOfCalls(t, "CalculateOperatorTableBytes", 3+2+3+1)
+		assert.ElementsMatch(t, [][2]uint64{{100, 110}, {120, 130}}, source.calls)
+		for i, sample := range samples {
+			assert.Equal(t, want[i].Root, sample.Root, "block %d", sample.BlockNumber)
+		}
+	})
+}
+
+func TestBackfillRuns(t *testing.T) {
+	assert.Equal(t, [][]int{{0}, {1}, {2}}, backfillRuns(3, 2, false))
+	assert.Equal(t, [][]int{{0, 1, 2}}, backfillRuns(3, 1, true))
+	assert.Equal(t, [][]int{{0, 1}, {2, 3, 4}}, backfillRuns(5, 2, true))
+	assert.Equal(t, [][]int{{0}, {1}}, backfillRuns(2, 4, true))
+}
+
+func TestWriteRootSamplesCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteRootSamplesCSV(&buf, []RootSample{
+		{BlockNumber: 100, Timestamp: 1700000000, OperatorSetCount: 3, SkippedCount: 1, ReusedCount: 2},
+	})
+	require.NoError(t, err)
+	assert.Equal(t,
+		"blockNumber,blockHash,timestamp,root,operatorSetCount,skippedCount,reusedCount\n"+
+			"100,0x0000000000000000000000000000000000000000000000000000000000000000,1700000000,"+
+			"0x0000000000000000000000000000000000000000000000000000000000000000,3,1,2\n",
+		buf.String())
+}
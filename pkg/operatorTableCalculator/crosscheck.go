@@ -0,0 +1,170 @@
+package operatorTableCalculator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/multichain-go/pkg/logger"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+// LocalTableComputer calculates operator table bytes without calling the operator set's
+// on-chain table calculator, such as localTableCalculator.Calculator.
+type LocalTableComputer interface {
+	CalculateOperatorTableBytes(ctx context.Context, snap *snapshot.Snapshot, opset ICrossChainRegistry.OperatorSet) ([]byte, error)
+}
+
+// CrossCheckMode is what happens to an operator set whose on-chain table differs from the
+// local calculation.
+type CrossCheckMode string
+
+const (
+	// CrossCheckWarn flags the mismatch in the report and keeps the on-chain table
+	CrossCheckWarn CrossCheckMode = "warn"
+	// CrossCheckExclude leaves the operator set out of the tree, as if its calculator reverted
+	CrossCheckExclude CrossCheckMode = "exclude"
+	// CrossCheckAbort fails the calculation
+	CrossCheckAbort CrossCheckMode = "abort"
+)
+
+// ErrCrossCheckMismatch is returned in CrossCheckAbort mode when an on-chain table differs
+// from the local calculation.
+var ErrCrossCheckMismatch = errors.New("operator table differs from local calculation")
+
+// ParseCrossCheckMode parses a cross-check mode. The empty string is CrossCheckWarn.
+//
+// Parameters:
+//   - value: "warn", "exclude" or "abort"
+//
+// Returns:
+//   - CrossCheckMode: The mode
+//   - error: An error if the mode is unknown
+func ParseCrossCheckMode(value string) (CrossCheckMode, error) {
+	switch mode := CrossCheckMode(value); mode {
+	case "":
+		return CrossCheckWarn, nil
+	case CrossCheckWarn, CrossCheckExclude, CrossCheckAbort:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown cross-check mode %q: expected warn, exclude or abort", value)
+	}
+}
+
+// CrossCheckStatus is the outcome of cross-checking one operator table.
+type CrossCheckStatus string
+
+const (
+	// CrossCheckMatch means the on-chain and local table bytes are identical
+	CrossCheckMatch CrossCheckStatus = "match"
+	// CrossCheckMismatch means the on-chain and local table bytes differ
+	CrossCheckMismatch CrossCheckStatus = "mismatch"
+	// CrossCheckUnavailable means the table could not be calculated locally, e.g. because the
+	// operator set uses a custom calculator
+	CrossCheckUnavailable CrossCheckStatus = "unavailable"
+)
+
+// CrossCheckResult compares an operator set's on-chain table bytes with the local calculation.
+type CrossCheckResult struct {
+	Avs common.Address `json:"avs"`
+	Id  uint32         `json:"id"`
+	// Status is the outcome of the comparison
+	Status CrossCheckStatus `json:"status"`
+	// OnChainTableBytesHash is the keccak256 hash of the on-chain table bytes
+	OnChainTableBytesHash common.Hash `json:"onChainTableBytesHash"`
+	// LocalTableBytesHash is the keccak256 hash of the local table bytes, or zero if unavailable
+	LocalTableBytesHash common.Hash `json:"localTableBytesHash"`
+	// Reason explains why the local calculation is unavailable
+	Reason string `json:"reason,omitempty"`
+}
+
+func (c *StakeTableCalculator) crossCheckMode() CrossCheckMode {
+	if c.config == nil || c.config.CrossCheckMode == "" {
+		return CrossCheckWarn
+	}
+	return c.config.CrossCheckMode
+}
+
+// crossCheckOperatorTables calculates each opset whose on-chain calculation succeeded with
+// the local table computer and compares the two, with at most Config.Parallelism local
+// calculations in flight. The result for an opset whose on-chain calculation failed, whose
+// table already came from Config.LocalFallback, or whose table was reused from the previous
+// block of a backfill and checked there, is nil.
+func (c *StakeTableCalculator) crossCheckOperatorTables(
+	ctx context.Context,
+	snap *snapshot.Snapshot,
+	opsets []ICrossChainRegistry.OperatorSet,
+	results []operatorTableResult,
+) []*CrossCheckResult {
+	l := logger.WithTraceContext(ctx, c.logger)
+
+	checks := make([]*CrossCheckResult, len(opsets))
+	sem := make(chan struct{}, c.parallelism())
+	var wg sync.WaitGroup
+	for i, opset := range opsets {
+		if results[i].err != nil || results[i].local || results[i].reused {
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, opset ICrossChainRegistry.OperatorSet) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			check := &CrossCheckResult{
+				Avs:                   opset.Avs,
+				Id:                    opset.Id,
+				OnChainTableBytesHash: crypto.Keccak256Hash(results[i].tableBytes),
+			}
+			checks[i] = check
+
+			localBytes, err := c.config.LocalTableComputer.CalculateOperatorTableBytes(ctx, snap, opset)
+			if err != nil {
+				check.Status = CrossCheckUnavailable
+				check.Reason = err.Error()
+				l.Sugar().Debugw("Operator table cannot be calculated locally",
+					zap.Uint32("opsetId", opset.Id),
+					zap.String("opsetAvs", opset.Avs.String()),
+					zap.Error(err),
+				)
+				return
+			}
+			check.LocalTableBytesHash = crypto.Keccak256Hash(localBytes)
+			if bytes.Equal(localBytes, results[i].tableBytes) {
+				check.Status = CrossCheckMatch
+				return
+			}
+			check.Status = CrossCheckMismatch
+			l.Sugar().Warnw("Operator table differs from local calculation",
+				zap.Uint32("opsetId", opset.Id),
+				zap.String("opsetAvs", opset.Avs.String()),
+				zap.String("onChainTableBytesHash", check.OnChainTableBytesHash.Hex()),
+				zap.String("localTableBytesHash", check.LocalTableBytesHash.Hex()),
+			)
+		}(i, opset)
+	}
+	wg.Wait()
+	return checks
+}
+
+// crossCheckError returns an error wrapping ErrCrossCheckMismatch that lists every mismatched
+// opset, or nil if none mismatched.
+func crossCheckError(checks []*CrossCheckResult) error {
+	var mismatched []string
+	for _, check := range checks {
+		if check != nil && check.Status == CrossCheckMismatch {
+			mismatched = append(mismatched, fmt.Sprintf("%s/%d", check.Avs.Hex(), check.Id))
+		}
+	}
+	if len(mismatched) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w for operator sets %s", ErrCrossCheckMismatch, strings.Join(mismatched, ", "))
+}
@@ -0,0 +1,125 @@
+package operatorTableCalculator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeLocalTableComputer struct {
+	tables map[uint32][]byte
+}
+
+func (f *fakeLocalTableComputer) CalculateOperatorTableBytes(_ context.Context, _ *snapshot.Snapshot, opset ICrossChainRegistry.OperatorSet) ([]byte, error) {
+	tableBytes, ok := f.tables[opset.Id]
+	if !ok {
+		return nil, errors.New("unsupported operator table calculator")
+	}
+	return tableBytes, nil
+}
+
+// setupCrossCheck mocks a calculation of three opsets whose on-chain tables are 0x01, 0x02 and
+// 0x03. Locally, opset 1 matches, opset 2 differs and opset 3 cannot be calculated.
+func setupCrossCheck(t *testing.T, mode CrossCheckMode) (*StakeTableCalculator, *snapshot.Snapshot, []ICrossChainRegistry.OperatorSet) {
+	calculator, mockRegistryCaller, mockEthClient := setupTestCalculator(t)
+	calculator.config.LocalTableComputer = &fakeLocalTableComputer{tables: map[uint32][]byte{
+		1: {0x01},
+		2: {0x22},
+	}}
+	calculator.config.CrossCheckMode = mode
+
+	header := &types.Header{Number: big.NewInt(12345), Time: 1700000000}
+	callOpts := &bind.CallOpts{Context: context.Background(), BlockHash: header.Hash()}
+	opsets := createTestOperatorSets(3)
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).Return(big.NewInt(3), nil)
+	mockRegistryCaller.On("GetActiveGenerationReservationsByRange", callOpts, big.NewInt(0), big.NewInt(3)).Return(opsets, nil)
+	for i, opset := range opsets {
+		mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opset).Return([]byte{byte(i + 1)}, nil)
+	}
+	mockEthClient.On("HeaderByNumber", mock.Anything, big.NewInt(12345)).Return(header, nil).Maybe()
+	return calculator, snapshot.New(header), opsets
+}
+
+func TestCrossCheck_Warn(t *testing.T) {
+	calculator, snap, opsets := setupCrossCheck(t, "")
+
+	_, _, dist, report, err := calculator.CalculateStakeTableRootWithReport(context.Background(), snap)
+	require.NoError(t, err)
+	assert.Len(t, dist.GetOperatorSets(), 3, "mismatches are only flagged")
+
+	require.Len(t, report.CrossChecks, 3)
+	assert.Equal(t, CrossCheckResult{
+		Avs:                   opsets[0].Avs,
+		Id:                    opsets[0].Id,
+		Status:                CrossCheckMatch,
+		OnChainTableBytesHash: crypto.Keccak256Hash([]byte{0x01}),
+		LocalTableBytesHash:   crypto.Keccak256Hash([]byte{0x01}),
+	}, report.CrossChecks[0])
+	assert.Equal(t, CrossCheckMismatch, report.CrossChecks[1].Status)
+	assert.Equal(t, crypto.Keccak256Hash([]byte{0x22}), report.CrossChecks[1].LocalTableBytesHash)
+	assert.Equal(t, CrossCheckUnavailable, report.CrossChecks[2].Status)
+	assert.Contains(t, report.CrossChecks[2].Reason, "unsupported operator table calculator")
+}
+
+func TestCrossCheck_Exclude(t *testing.T) {
+	calculator, snap, opsets := setupCrossCheck(t, CrossCheckExclude)
+
+	_, _, dist, report, err := calculator.CalculateStakeTableRootWithReport(context.Background(), snap)
+	require.NoError(t, err)
+	assert.Len(t, dist.GetOperatorSets(), 2, "unavailable checks do not exclude")
+	assert.Equal(t, []SkippedOperatorSet{
+		{Avs: opsets[1].Avs, Id: opsets[1].Id, Reason: ErrCrossCheckMismatch.Error()},
+	}, report.Skipped)
+}
+
+func TestCrossCheck_Abort(t *testing.T) {
+	calculator, snap, opsets := setupCrossCheck(t, CrossCheckAbort)
+
+	_, _, _, _, err := calculator.CalculateStakeTableRootWithReport(context.Background(), snap)
+	assert.ErrorIs(t, err, ErrCrossCheckMismatch)
+	assert.ErrorContains(t, err, opsets[1].Avs.Hex()+"/2")
+}
+
+func TestCrossCheck_ByBlockNumber(t *testing.T) {
+	calculator, mockRegistryCaller, mockEthClient := setupTestCalculator(t)
+	calculator.config.LocalTableComputer = &fakeLocalTableComputer{tables: map[uint32][]byte{1: {0x01}}}
+
+	header := &types.Header{Number: big.NewInt(12345), Time: 1700000000}
+	callOpts := &bind.CallOpts{BlockNumber: big.NewInt(12345)}
+	opsets := createTestOperatorSets(1)
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", mock.Anything).Return(big.NewInt(1), nil)
+	mockRegistryCaller.On("GetActiveGenerationReservationsByRange", mock.Anything, big.NewInt(0), big.NewInt(1)).Return(opsets, nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", mock.MatchedBy(func(opts *bind.CallOpts) bool {
+		return opts.BlockNumber.Cmp(callOpts.BlockNumber) == 0
+	}), opsets[0]).Return([]byte{0x01}, nil)
+	mockEthClient.On("HeaderByNumber", mock.Anything, big.NewInt(12345)).Return(header, nil).Once()
+
+	_, _, dist, err := calculator.CalculateStakeTableRoot(context.Background(), 12345)
+	require.NoError(t, err)
+	assert.Len(t, dist.GetOperatorSets(), 1)
+}
+
+func TestParseCrossCheckMode(t *testing.T) {
+	for value, want := range map[string]CrossCheckMode{"": CrossCheckWarn, "warn": CrossCheckWarn, "exclude": CrossCheckExclude, "abort": CrossCheckAbort} {
+		mode, err := ParseCrossCheckMode(value)
+		require.NoError(t, err)
+		assert.Equal(t, want, mode)
+	}
+	_, err := ParseCrossCheckMode("strict")
+	assert.Error(t, err)
+
+	_, err = NewStakeTableRootCalculatorWithRegistryCaller(&Config{CrossCheckMode: "strict"}, chainManager.NewMockEthClientInterface(t), NewMockICrossChainRegistryCaller(t), zap.NewNop())
+	assert.Error(t, err)
+}
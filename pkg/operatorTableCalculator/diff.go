@@ -0,0 +1,254 @@
+package operatorTableCalculator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/Layr-Labs/multichain-go/pkg/tracing"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// StakeTableDiff describes how the stake tables changed between two distributions.
+type StakeTableDiff struct {
+	// FromBlockNumber is the reference block of the older distribution
+	FromBlockNumber uint64 `json:"fromBlockNumber"`
+	// FromBlockHash is the reference block hash of the older distribution
+	FromBlockHash common.Hash `json:"fromBlockHash"`
+	// FromRoot is the global table root of the older distribution
+	FromRoot common.Hash `json:"fromRoot"`
+	// ToBlockNumber is the reference block of the newer distribution
+	ToBlockNumber uint64 `json:"toBlockNumber"`
+	// ToBlockHash is the reference block hash of the newer distribution
+	ToBlockHash common.Hash `json:"toBlockHash"`
+	// ToRoot is the global table root of the newer distribution
+	ToRoot common.Hash `json:"toRoot"`
+	// Added are the operator sets only in the newer distribution, in its leaf order
+	Added []OperatorSetSummary `json:"added"`
+	// Removed are the operator sets only in the older distribution, in its leaf order
+	Removed []OperatorSetSummary `json:"removed"`
+	// Changed are the operator sets in both distributions whose table bytes differ, in the
+	// newer distribution's leaf order
+	Changed []OperatorSetChange `json:"changed"`
+	// Unchanged is the number of operator sets whose table bytes are identical
+	Unchanged int `json:"unchanged"`
+}
+
+// HasChanges reports whether any operator set was added, removed, or changed.
+func (d *StakeTableDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0
+}
+
+// OperatorSetSummary describes an operator set's table in one distribution.
+type OperatorSetSummary struct {
+	Avs common.Address `json:"avs"`
+	Id  uint32         `json:"id"`
+	// CurveType is the operator set's key type
+	CurveType operatorTable.CurveType `json:"curveType"`
+	// TableBytesHash is the keccak256 hash of the operator table bytes
+	TableBytesHash common.Hash `json:"tableBytesHash"`
+	// OperatorCount is the number of operators in the table
+	OperatorCount int64 `json:"operatorCount"`
+	// TotalWeights are the table's summed weights, one per weight type
+	TotalWeights []*big.Int `json:"totalWeights"`
+	// DecodeError is set if the table bytes could not be decoded
+	DecodeError string `json:"decodeError,omitempty"`
+}
+
+// OperatorSetChange describes how an operator set's table changed between two distributions.
+type OperatorSetChange struct {
+	Avs common.Address `json:"avs"`
+	Id  uint32         `json:"id"`
+	// CurveType is the operator set's key type in the newer distribution
+	CurveType operatorTable.CurveType `json:"curveType"`
+	// FromTableBytesHash is the keccak256 hash of the older table bytes
+	FromTableBytesHash common.Hash `json:"fromTableBytesHash"`
+	// ToTableBytesHash is the keccak256 hash of the newer table bytes
+	ToTableBytesHash common.Hash `json:"toTableBytesHash"`
+	// OperatorCountDelta is the change in the number of operators
+	OperatorCountDelta int64 `json:"operatorCountDelta"`
+	// TotalWeightDeltas are the changes in the summed weights, one per weight type
+	TotalWeightDeltas []*big.Int `json:"totalWeightDeltas"`
+	// AggregatePubkeyChanged reports whether a BN254 table's aggregate pubkey changed
+	AggregatePubkeyChanged bool `json:"aggregatePubkeyChanged"`
+	// ConfigChanged reports whether the operator set config (owner or max staleness) changed
+	ConfigChanged bool `json:"configChanged"`
+	// DecodeError is set if either table could not be decoded; the deltas are then zero
+	DecodeError string `json:"decodeError,omitempty"`
+}
+
+// CalculateStakeTableDiff calculates the distributions at two reference blocks and compares them.
+//
+// Parameters:
+//   - ctx: Context for the calculations
+//   - from: The older reference block
+//   - to: The newer reference block
+//
+// Returns:
+//   - *StakeTableDiff: The differences between the two distributions
+//   - error: An error if either distribution cannot be calculated
+func (c *StakeTableCalculator) CalculateStakeTableDiff(
+	ctx context.Context,
+	from *snapshot.Snapshot,
+	to *snapshot.Snapshot,
+) (*StakeTableDiff, error) {
+	ctx, span := tracing.Start(ctx, c.tracer, "StakeTableCalculator.CalculateStakeTableDiff",
+		attribute.Int64("fromBlockNumber", int64(from.Number)),
+		attribute.Int64("toBlockNumber", int64(to.Number)),
+	)
+
+	fromRoot, _, fromDist, err := c.CalculateStakeTableRootAtSnapshot(ctx, from)
+	if err != nil {
+		err = fmt.Errorf("failed to calculate stake table at block %d: %w", from.Number, err)
+		tracing.End(span, err)
+		return nil, err
+	}
+	toRoot, _, toDist, err := c.CalculateStakeTableRootAtSnapshot(ctx, to)
+	if err != nil {
+		err = fmt.Errorf("failed to calculate stake table at block %d: %w", to.Number, err)
+		tracing.End(span, err)
+		return nil, err
+	}
+
+	diff := DiffDistributions(fromDist, toDist)
+	diff.FromBlockNumber = from.Number
+	diff.FromBlockHash = from.Hash
+	diff.FromRoot = fromRoot
+	diff.ToBlockNumber = to.Number
+	diff.ToBlockHash = to.Hash
+	diff.ToRoot = toRoot
+	span.SetAttributes(
+		attribute.Int("added", len(diff.Added)),
+		attribute.Int("removed", len(diff.Removed)),
+		attribute.Int("changed", len(diff.Changed)),
+	)
+	tracing.End(span, nil)
+	return diff, nil
+}
+
+// DiffDistributions compares two distributions by operator set. The block and root fields of
+// the returned diff are left unset.
+//
+// Parameters:
+//   - from: The older distribution
+//   - to: The newer distribution
+//
+// Returns:
+//   - *StakeTableDiff: The differences between the two distributions
+func DiffDistributions(from, to *distribution.Distribution) *StakeTableDiff {
+	fromSnapshot, toSnapshot := from.Snapshot(), to.Snapshot()
+	changes := fromSnapshot.Diff(toSnapshot)
+	diff := &StakeTableDiff{
+		Added:     []OperatorSetSummary{},
+		Removed:   []OperatorSetSummary{},
+		Changed:   []OperatorSetChange{},
+		Unchanged: toSnapshot.Len() - len(changes.Added) - len(changes.Changed),
+	}
+
+	for _, opset := range changes.Added {
+		toBytes, _ := toSnapshot.GetTableData(opset)
+		diff.Added = append(diff.Added, summarizeOperatorSet(opset, toBytes))
+	}
+	for _, opset := range changes.Changed {
+		fromBytes, _ := fromSnapshot.GetTableData(opset)
+		toBytes, _ := toSnapshot.GetTableData(opset)
+		diff.Changed = append(diff.Changed, compareOperatorSet(opset, fromBytes, toBytes))
+	}
+	for _, opset := range changes.Removed {
+		fromBytes, _ := fromSnapshot.GetTableData(opset)
+		diff.Removed = append(diff.Removed, summarizeOperatorSet(opset, fromBytes))
+	}
+
+	return diff
+}
+
+func summarizeOperatorSet(opset distribution.OperatorSet, tableBytes []byte) OperatorSetSummary {
+	summary := OperatorSetSummary{
+		Avs:            opset.Avs,
+		Id:             opset.Id,
+		TableBytesHash: crypto.Keccak256Hash(tableBytes),
+		TotalWeights:   []*big.Int{},
+	}
+	table, err := operatorTable.Decode(tableBytes)
+	if err != nil {
+		summary.DecodeError = err.Error()
+		return summary
+	}
+	summary.CurveType = table.CurveType
+	summary.OperatorCount, summary.TotalWeights = tableTotals(table)
+	return summary
+}
+
+func compareOperatorSet(opset distribution.OperatorSet, fromBytes, toBytes []byte) OperatorSetChange {
+	change := OperatorSetChange{
+		Avs:                opset.Avs,
+		Id:                 opset.Id,
+		FromTableBytesHash: crypto.Keccak256Hash(fromBytes),
+		ToTableBytesHash:   crypto.Keccak256Hash(toBytes),
+		TotalWeightDeltas:  []*big.Int{},
+	}
+
+	fromTable, err := operatorTable.Decode(fromBytes)
+	if err != nil {
+		change.DecodeError = err.Error()
+		return change
+	}
+	toTable, err := operatorTable.Decode(toBytes)
+	if err != nil {
+		change.DecodeError = err.Error()
+		return change
+	}
+
+	change.CurveType = toTable.CurveType
+	change.ConfigChanged = fromTable.Config != toTable.Config
+
+	fromCount, fromWeights := tableTotals(fromTable)
+	toCount, toWeights := tableTotals(toTable)
+	change.OperatorCountDelta = toCount - fromCount
+	for i := 0; i < max(len(fromWeights), len(toWeights)); i++ {
+		delta := new(big.Int)
+		if i < len(toWeights) {
+			delta.Add(delta, toWeights[i])
+		}
+		if i < len(fromWeights) {
+			delta.Sub(delta, fromWeights[i])
+		}
+		change.TotalWeightDeltas = append(change.TotalWeightDeltas, delta)
+	}
+
+	if fromTable.BN254 != nil && toTable.BN254 != nil {
+		fromKey, toKey := fromTable.BN254.AggregatePubkey, toTable.BN254.AggregatePubkey
+		change.AggregatePubkeyChanged = fromKey.X.Cmp(toKey.X) != 0 || fromKey.Y.Cmp(toKey.Y) != 0
+	} else {
+		change.AggregatePubkeyChanged = (fromTable.BN254 == nil) != (toTable.BN254 == nil)
+	}
+
+	return change
+}
+
+// tableTotals returns the number of operators in a decoded table and its summed weights.
+// ECDSA tables carry per-operator weights, which are summed here.
+func tableTotals(table *operatorTable.OperatorTable) (int64, []*big.Int) {
+	switch {
+	case table.BN254 != nil:
+		return table.BN254.NumOperators.Int64(), table.BN254.TotalWeights
+	case table.ECDSA != nil:
+		totals := []*big.Int{}
+		for _, operator := range table.ECDSA {
+			for i, weight := range operator.Weights {
+				if i == len(totals) {
+					totals = append(totals, new(big.Int))
+				}
+				totals[i].Add(totals[i], weight)
+			}
+		}
+		return int64(len(table.ECDSA)), totals
+	default:
+		return 0, []*big.Int{}
+	}
+}
@@ -0,0 +1,176 @@
+package operatorTableCalculator
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeBN254Table(t *testing.T, id uint32, numOperators int64, pubkeyX int64, weights ...int64) []byte {
+	totalWeights := make([]*big.Int, len(weights))
+	for i, w := range weights {
+		totalWeights[i] = big.NewInt(w)
+	}
+	tableBytes, err := operatorTable.Encode(&operatorTable.OperatorTable{
+		OperatorSet: operatorTable.OperatorSet{Avs: common.HexToAddress("0xa1"), Id: id},
+		CurveType:   operatorTable.CurveTypeBN254,
+		Config:      operatorTable.OperatorSetConfig{Owner: common.HexToAddress("0xb1"), MaxStalenessPeriod: 3600},
+		BN254: &operatorTable.BN254OperatorSetInfo{
+			NumOperators:    big.NewInt(numOperators),
+			AggregatePubkey: operatorTable.G1Point{X: big.NewInt(pubkeyX), Y: big.NewInt(1)},
+			TotalWeights:    totalWeights,
+		},
+	})
+	require.NoError(t, err)
+	return tableBytes
+}
+
+func encodeECDSATable(t *testing.T, id uint32, operatorWeights ...int64) []byte {
+	operators := make([]operatorTable.ECDSAOperatorInfo, len(operatorWeights))
+	for i, w := range operatorWeights {
+		operators[i] = operatorTable.ECDSAOperatorInfo{
+			Pubkey:  common.BigToAddress(big.NewInt(int64(i + 1))),
+			Weights: []*big.Int{big.NewInt(w)},
+		}
+	}
+	tableBytes, err := operatorTable.Encode(&operatorTable.OperatorTable{
+		OperatorSet: operatorTable.OperatorSet{Avs: common.HexToAddress("0xa1"), Id: id},
+		CurveType:   operatorTable.CurveTypeECDSA,
+		ECDSA:       operators,
+	})
+	require.NoError(t, err)
+	return tableBytes
+}
+
+func newTestDistribution(t *testing.T, tables map[distribution.OperatorSet][]byte, order []distribution.OperatorSet) *distribution.Distribution {
+	dist := distribution.NewDistributionWithOperatorSets(order)
+	for _, opset := range order {
+		require.NoError(t, dist.SetTableData(opset, tables[opset]))
+	}
+	return dist
+}
+
+func TestDiffDistributions(t *testing.T) {
+	avs := common.HexToAddress("0xa1")
+	unchanged := distribution.OperatorSet{Avs: avs, Id: 1}
+	bn254 := distribution.OperatorSet{Avs: avs, Id: 2}
+	ecdsa := distribution.OperatorSet{Avs: avs, Id: 3}
+	removed := distribution.OperatorSet{Avs: avs, Id: 4}
+	added := distribution.OperatorSet{Avs: avs, Id: 5}
+
+	from := newTestDistribution(t, map[distribution.OperatorSet][]byte{
+		unchanged: encodeBN254Table(t, 1, 2, 7, 100),
+		bn254:     encodeBN254Table(t, 2, 3, 8, 300, 30),
+		ecdsa:     encodeECDSATable(t, 3, 10, 20),
+		removed:   encodeECDSATable(t, 4, 5),
+	}, []distribution.OperatorSet{unchanged, bn254, ecdsa, removed})
+	to := newTestDistribution(t, map[distribution.OperatorSet][]byte{
+		unchanged: encodeBN254Table(t, 1, 2, 7, 100),
+		bn254:     encodeBN254Table(t, 2, 4, 9, 450, 25),
+		ecdsa:     encodeECDSATable(t, 3, 10),
+		added:     encodeBN254Table(t, 5, 1, 3, 50),
+	}, []distribution.OperatorSet{unchanged, added, bn254, ecdsa})
+
+	diff := DiffDistributions(from, to)
+	require.True(t, diff.HasChanges())
+	assert.Equal(t, 1, diff.Unchanged)
+
+	require.Len(t, diff.Added, 1)
+	assert.Equal(t, added.Id, diff.Added[0].Id)
+	assert.Equal(t, operatorTable.CurveTypeBN254, diff.Added[0].CurveType)
+	assert.Equal(t, int64(1), diff.Added[0].OperatorCount)
+	assert.Equal(t, []*big.Int{big.NewInt(50)}, diff.Added[0].TotalWeights)
+
+	require.Len(t, diff.Removed, 1)
+	assert.Equal(t, removed.Id, diff.Removed[0].Id)
+	assert.Equal(t, operatorTable.CurveTypeECDSA, diff.Removed[0].CurveType)
+	assert.Equal(t, int64(1), diff.Removed[0].OperatorCount)
+	assert.Equal(t, []*big.Int{big.NewInt(5)}, diff.Removed[0].TotalWeights)
+
+	require.Len(t, diff.Changed, 2)
+	bn254Change := diff.Changed[0]
+	assert.Equal(t, bn254.Id, bn254Change.Id)
+	assert.Equal(t, int64(1), bn254Change.OperatorCountDelta)
+	assert.Equal(t, []*big.Int{big.NewInt(150), big.NewInt(-5)}, bn254Change.TotalWeightDeltas)
+	assert.True(t, bn254Change.AggregatePubkeyChanged)
+	assert.False(t, bn254Change.ConfigChanged)
+	fromBytes, _ := from.GetTableData(bn254)
+	assert.Equal(t, crypto.Keccak256Hash(fromBytes), bn254Change.FromTableBytesHash)
+
+	ecdsaChange := diff.Changed[1]
+	assert.Equal(t, ecdsa.Id, ecdsaChange.Id)
+	assert.Equal(t, operatorTable.CurveTypeECDSA, ecdsaChange.CurveType)
+	assert.Equal(t, int64(-1), ecdsaChange.OperatorCountDelta)
+	assert.Equal(t, []*big.Int{big.NewInt(-20)}, ecdsaChange.TotalWeightDeltas)
+	assert.False(t, ecdsaChange.AggregatePubkeyChanged)
+
+	encoded, err := json.Marshal(diff)
+	require.NoError(t, err)
+	var decoded StakeTableDiff
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, *diff, decoded)
+}
+
+func TestDiffDistributions_UndecodableTable(t *testing.T) {
+	opset := distribution.OperatorSet{Avs: common.HexToAddress("0xa1"), Id: 1}
+	from := newTestDistribution(t, map[distribution.OperatorSet][]byte{opset: {0x01}},
+		[]distribution.OperatorSet{opset})
+	to := newTestDistribution(t, map[distribution.OperatorSet][]byte{opset: {0x02}},
+		[]distribution.OperatorSet{opset})
+
+	diff := DiffDistributions(from, to)
+	require.Len(t, diff.Changed, 1)
+	assert.NotEmpty(t, diff.Changed[0].DecodeError)
+	assert.Equal(t, crypto.Keccak256Hash([]byte{0x02}), diff.Changed[0].ToTableBytesHash)
+}
+
+func TestCalculateStakeTableDiff(t *testing.T) {
+	calculator, mockRegistryCaller, mockEthClient := setupTestCalculator(t)
+
+	fromHeader := &types.Header{Number: big.NewInt(100), Time: 1700000000}
+	toHeader := &types.Header{Number: big.NewInt(200), Time: 1700001200}
+	fromOpts := &bind.CallOpts{Context: context.Background(), BlockHash: fromHeader.Hash()}
+	toOpts := &bind.CallOpts{Context: context.Background(), BlockHash: toHeader.Hash()}
+
+	opsets := createTestOperatorSets(2)
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", fromOpts).Return(big.NewInt(1), nil)
+	mockRegistryCaller.On("GetActiveGenerationReservationsByRange", fromOpts, big.NewInt(0), big.NewInt(1)).
+		Return(opsets[:1], nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", fromOpts, opsets[0]).
+		Return(encodeECDSATable(t, 1, 10), nil)
+
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", toOpts).Return(big.NewInt(2), nil)
+	mockRegistryCaller.On("GetActiveGenerationReservationsByRange", toOpts, big.NewInt(0), big.NewInt(2)).
+		Return(opsets, nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", toOpts, opsets[0]).
+		Return(encodeECDSATable(t, 1, 10, 15), nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", toOpts, opsets[1]).
+		Return(encodeECDSATable(t, 2, 1), nil)
+
+	mockEthClient.On("HeaderByNumber", mock.Anything, big.NewInt(100)).Return(fromHeader, nil)
+	mockEthClient.On("HeaderByNumber", mock.Anything, big.NewInt(200)).Return(toHeader, nil)
+
+	diff, err := calculator.CalculateStakeTableDiff(context.Background(), snapshot.New(fromHeader), snapshot.New(toHeader))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), diff.FromBlockNumber)
+	assert.Equal(t, toHeader.Hash(), diff.ToBlockHash)
+	assert.NotEqual(t, diff.FromRoot, diff.ToRoot)
+	require.Len(t, diff.Added, 1)
+	assert.Equal(t, opsets[1].Id, diff.Added[0].Id)
+	require.Len(t, diff.Changed, 1)
+	assert.Equal(t, int64(1), diff.Changed[0].OperatorCountDelta)
+	assert.Equal(t, []*big.Int{big.NewInt(15)}, diff.Changed[0].TotalWeightDeltas)
+	assert.Empty(t, diff.Removed)
+}
@@ -0,0 +1,57 @@
+package operatorTableCalculator
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/multichain-go/pkg/logger"
+	"github.com/Layr-Labs/multichain-go/pkg/multicall"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"go.uber.org/zap"
+)
+
+// calculateOutOfGasTablesLocally replaces the result of every opset whose on-chain calculation
+// ran out of gas, or hit another RPC limit of multicall.IsLimitError, with the table from
+// Config.LocalFallback, with at most Config.Parallelism local calculations in flight. Opsets the local calculator cannot handle, such as those with
+// custom calculators, keep their on-chain error and are skipped as before.
+func (c *StakeTableCalculator) calculateOutOfGasTablesLocally(
+	ctx context.Context,
+	snap *snapshot.Snapshot,
+	opsets []ICrossChainRegistry.OperatorSet,
+	results []operatorTableResult,
+) {
+	l := logger.WithTraceContext(ctx, c.logger)
+
+	sem := make(chan struct{}, c.parallelism())
+	var wg sync.WaitGroup
+	for i, opset := range opsets {
+		if results[i].err == nil || !multicall.IsLimitError(results[i].err) {
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, opset ICrossChainRegistry.OperatorSet) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			tableBytes, err := c.config.LocalFallback.CalculateOperatorTableBytes(ctx, snap, opset)
+			if err != nil {
+				l.Sugar().Warnw("CalculateOperatorTableBytes ran out of gas and the table cannot be calculated locally",
+					zap.Uint32("opsetId", opset.Id),
+					zap.String("opsetAvs", opset.Avs.String()),
+					zap.NamedError("onChainError", results[i].err),
+					zap.Error(err),
+				)
+				return
+			}
+			l.Sugar().Warnw("CalculateOperatorTableBytes ran out of gas, using the locally calculated table",
+				zap.Uint32("opsetId", opset.Id),
+				zap.String("opsetAvs", opset.Avs.String()),
+				zap.NamedError("onChainError", results[i].err),
+			)
+			results[i] = operatorTableResult{tableBytes: tableBytes, local: true}
+		}(i, opset)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,63 @@
+package operatorTableCalculator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLocalFallback verifies that an opset whose on-chain calculation runs out of gas uses the
+// local table when one can be calculated, and is skipped otherwise. Reverts are never replaced.
+func TestLocalFallback(t *testing.T) {
+	calculator, mockRegistryCaller, mockEthClient := setupTestCalculator(t)
+	local := &fakeLocalTableComputer{tables: map[uint32][]byte{
+		1: {0x01},
+		2: {0x22},
+		4: {0x44},
+	}}
+	calculator.config.LocalFallback = local
+	calculator.config.LocalTableComputer = local
+
+	header := &types.Header{Number: big.NewInt(12345), Time: 1700000000}
+	callOpts := &bind.CallOpts{Context: context.Background(), BlockHash: header.Hash()}
+	opsets := createTestOperatorSets(4)
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).Return(big.NewInt(4), nil)
+	mockRegistryCaller.On("GetActiveGenerationReservationsByRange", callOpts, big.NewInt(0), big.NewInt(4)).Return(opsets, nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[0]).Return([]byte{0x01}, nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[1]).Return([]byte(nil), errors.New("out of gas"))
+	mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[2]).Return([]byte(nil), errors.New("gas required exceeds allowance (30000000)"))
+	mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[3]).Return([]byte(nil), errors.New("execution reverted"))
+	mockEthClient.On("HeaderByNumber", mock.Anything, big.NewInt(12345)).Return(header, nil).Maybe()
+
+	_, _, dist, report, err := calculator.CalculateStakeTableRootWithReport(context.Background(), snapshot.New(header))
+	require.NoError(t, err)
+
+	require.Len(t, dist.GetOrderedOperatorSets(), 2)
+	data, found := dist.GetTableData(dist.GetOrderedOperatorSets()[1])
+	require.True(t, found)
+	assert.Equal(t, []byte{0x22}, data)
+
+	require.Len(t, report.Included, 2)
+	assert.False(t, report.Included[0].CalculatedLocally)
+	assert.True(t, report.Included[1].CalculatedLocally)
+	assert.Equal(t, crypto.Keccak256Hash([]byte{0x22}), report.Included[1].TableBytesHash)
+
+	// The opset without a local table keeps its on-chain error; the reverted one is not recalculated
+	require.Len(t, report.Skipped, 2)
+	assert.Equal(t, opsets[2].Id, report.Skipped[0].Id)
+	assert.Contains(t, report.Skipped[0].Reason, "gas required exceeds allowance")
+	assert.Equal(t, opsets[3].Id, report.Skipped[1].Id)
+
+	// Locally calculated tables are not cross-checked against themselves
+	require.Len(t, report.CrossChecks, 1)
+	assert.Equal(t, opsets[0].Id, report.CrossChecks[0].Id)
+}
@@ -0,0 +1,173 @@
+package operatorTableCalculator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
+	"github.com/Layr-Labs/multichain-go/pkg/logger"
+	"github.com/Layr-Labs/multichain-go/pkg/multicall"
+	"github.com/Layr-Labs/multichain-go/pkg/tracing"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// MulticallerInterface batches read-only contract calls, see multicall.Multicall.
+type MulticallerInterface interface {
+	TryAggregate(opts *bind.CallOpts, calls []multicall.Call) ([]multicall.Result, error)
+}
+
+// reservationPage is a [startIndex, endIndex) range of active generation reservations.
+type reservationPage struct {
+	startIndex   uint64
+	endIndex     uint64
+	reservations []ICrossChainRegistry.OperatorSet
+	fetched      bool
+}
+
+func newMulticaller(cfg *Config, ec chainManager.EthClientInterface) (MulticallerInterface, error) {
+	if cfg == nil || cfg.Multicall == nil {
+		return nil, nil
+	}
+	mcCfg := *cfg.Multicall
+	if mcCfg.SplitOn == nil {
+		mcCfg.SplitOn = func(err error) bool {
+			return classifyPageError(err) == pageErrorTooLarge
+		}
+	}
+	mc, err := multicall.NewMulticall(&mcCfg, ec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create multicall: %w", err)
+	}
+	return mc, nil
+}
+
+// fetchActiveGenerationReservationPagesMulticall fetches every page in one or more Multicall3
+// batches. Pages that cannot be fetched or decoded are left unfetched so the caller can fall
+// back to individual calls for them.
+func (c *StakeTableCalculator) fetchActiveGenerationReservationPagesMulticall(
+	callOpts *bind.CallOpts,
+	pages []reservationPage,
+) {
+	ctx, span := tracing.Start(callOpts.Context, c.tracer, "StakeTableCalculator.MulticallGetActiveGenerationReservationsByRange",
+		attribute.Int("pages", len(pages)),
+	)
+	l := logger.WithTraceContext(ctx, c.logger)
+
+	registryAbi, err := ICrossChainRegistry.ICrossChainRegistryMetaData.GetAbi()
+	if err != nil {
+		tracing.End(span, err)
+		l.Sugar().Warnw("Failed to load CrossChainRegistry ABI, falling back to individual calls", zap.Error(err))
+		return
+	}
+
+	calls := make([]multicall.Call, len(pages))
+	for i, page := range pages {
+		data, err := registryAbi.Pack("getActiveGenerationReservationsByRange",
+			new(big.Int).SetUint64(page.startIndex),
+			new(big.Int).SetUint64(page.endIndex),
+		)
+		if err != nil {
+			tracing.End(span, err)
+			l.Sugar().Warnw("Failed to encode reservation page call, falling back to individual calls", zap.Error(err))
+			return
+		}
+		calls[i] = multicall.Call{Target: c.config.CrossChainRegistryAddress, CallData: data}
+	}
+
+	batchOpts := *callOpts
+	batchOpts.Context = ctx
+	results, err := c.multicaller.TryAggregate(&batchOpts, calls)
+	tracing.End(span, err)
+	if err != nil {
+		l.Sugar().Warnw("Multicall failed, falling back to individual GetActiveGenerationReservationsByRange calls",
+			zap.Error(err),
+		)
+		return
+	}
+
+	for i, result := range results {
+		if result.Err != nil || !result.Success {
+			continue
+		}
+		out, err := registryAbi.Unpack("getActiveGenerationReservationsByRange", result.ReturnData)
+		if err != nil || len(out) != 1 {
+			continue
+		}
+		pages[i].reservations = *abi.ConvertType(out[0], new([]ICrossChainRegistry.OperatorSet)).(*[]ICrossChainRegistry.OperatorSet)
+		pages[i].fetched = true
+	}
+}
+
+// calculateOperatorTablesMulticall calculates table bytes for every opset through Multicall3.
+// A call that fails inside a batch, or whose whole batch failed, is retried on its own before
+// the opset is skipped, since a large batch can exhaust the gas available to later calls and
+// make them fail spuriously. Retries run with at most Config.Parallelism calls in flight.
+func (c *StakeTableCalculator) calculateOperatorTablesMulticall(
+	ctx context.Context,
+	pin *bind.CallOpts,
+	opsets []ICrossChainRegistry.OperatorSet,
+) ([]operatorTableResult, error) {
+	batchCtx, span := tracing.Start(ctx, c.tracer, "StakeTableCalculator.MulticallCalculateOperatorTableBytes",
+		attribute.Int("calls", len(opsets)),
+	)
+	l := logger.WithTraceContext(batchCtx, c.logger)
+
+	registryAbi, err := ICrossChainRegistry.ICrossChainRegistryMetaData.GetAbi()
+	if err != nil {
+		tracing.End(span, err)
+		return nil, fmt.Errorf("failed to load CrossChainRegistry ABI: %w", err)
+	}
+
+	calls := make([]multicall.Call, len(opsets))
+	for i, opset := range opsets {
+		data, err := registryAbi.Pack("calculateOperatorTableBytes", opset)
+		if err != nil {
+			tracing.End(span, err)
+			return nil, fmt.Errorf("failed to encode CalculateOperatorTableBytes call: %w", err)
+		}
+		calls[i] = multicall.Call{Target: c.config.CrossChainRegistryAddress, CallData: data}
+	}
+
+	batchOpts := *pin
+	batchOpts.Context = batchCtx
+	batched, err := c.multicaller.TryAggregate(&batchOpts, calls)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]operatorTableResult, len(opsets))
+	var retry []int
+	for i, result := range batched {
+		var batchErr error
+		switch {
+		case result.Err != nil:
+			batchErr = result.Err
+		case result.Success:
+			out, err := registryAbi.Unpack("calculateOperatorTableBytes", result.ReturnData)
+			if err == nil && len(out) == 1 {
+				results[i].tableBytes = *abi.ConvertType(out[0], new([]byte)).(*[]byte)
+				continue
+			}
+			if err == nil {
+				err = fmt.Errorf("unexpected output length %d", len(out))
+			}
+			batchErr = fmt.Errorf("failed to decode operator table bytes: %w", err)
+		default:
+			batchErr = multicall.RevertError(result.ReturnData)
+		}
+		l.Sugar().Debugw("Batched CalculateOperatorTableBytes failed, retrying individually",
+			zap.Uint32("opsetId", opsets[i].Id),
+			zap.String("opsetAvs", opsets[i].Avs.String()),
+			zap.Error(batchErr),
+		)
+		retry = append(retry, i)
+	}
+	c.calculateOperatorTablesIndividually(ctx, pin, opsets, retry, results)
+	return results, nil
+}
@@ -8,18 +8,24 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"sync"
+	"time"
 
 	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
 	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/logger"
+	"github.com/Layr-Labs/multichain-go/pkg/multicall"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/Layr-Labs/multichain-go/pkg/tracing"
 	"github.com/Layr-Labs/multichain-go/pkg/util"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	merkletree "github.com/wealdtech/go-merkletree/v2"
-	"github.com/wealdtech/go-merkletree/v2/keccak256"
 )
 
 // CrossChainRegistryCallerInterface defines the interface for interacting with the CrossChainRegistry contract
@@ -32,6 +38,32 @@ type CrossChainRegistryCallerInterface interface {
 // Config holds the configuration for the StakeTableCalculator.
 type Config struct {
 	CrossChainRegistryAddress common.Address
+	// TracerProvider, when set, enables OpenTelemetry spans for the calculation
+	TracerProvider trace.TracerProvider
+	// Parallelism is the maximum number of CalculateOperatorTableBytes calls made concurrently.
+	// Values below 1 run the calls one at a time.
+	Parallelism int
+	// Multicall, when set, batches registry reads through Multicall3
+	Multicall *multicall.Config
+	// PageSize is the starting number of reservations fetched per GetActiveGenerationReservationsByRange
+	// call. Defaults to DefaultPageSize. The size halves when the RPC rejects a page as too large
+	// or times out.
+	PageSize int
+	// MaxPageSize, when above PageSize, lets the page size double after each full page up to it.
+	MaxPageSize int
+	// PageRetries is the number of times a page that fails transiently (rate limits, dropped
+	// connections) is retried. Defaults to DefaultPageRetries; values below 0 disable retries.
+	PageRetries int
+	// LocalTableComputer, when set, recalculates every operator table off-chain and compares
+	// it with the on-chain CalculateOperatorTableBytes result
+	LocalTableComputer LocalTableComputer
+	// CrossCheckMode is what happens to an operator set whose on-chain table differs from the
+	// local calculation. Defaults to CrossCheckWarn.
+	CrossCheckMode CrossCheckMode
+	// LocalFallback, when set, calculates the table off-chain for an operator set whose
+	// CalculateOperatorTableBytes call runs out of gas, instead of skipping the operator set.
+	// Only standard BN254 and ECDSA calculators can be calculated this way.
+	LocalFallback LocalTableComputer
 }
 
 // StakeTableCalculator is responsible for calculating the cloud operator table root.
@@ -40,6 +72,10 @@ type StakeTableCalculator struct {
 	ethClient                chainManager.EthClientInterface
 	logger                   *zap.Logger
 	crossChainRegistryCaller CrossChainRegistryCallerInterface
+	multicaller              MulticallerInterface
+	tracer                   trace.Tracer
+	pageSizer                *pageSizer
+	pageRetryBackoff         time.Duration
 }
 
 // NewStakeTableRootCalculator creates a new instance of StakeTableCalculator.
@@ -49,47 +85,185 @@ func NewStakeTableRootCalculator(cfg *Config, ec chainManager.EthClientInterface
 		return nil, fmt.Errorf("failed to bind NewICrossChainRegistryCaller: %w", err)
 	}
 
+	if cfg != nil {
+		if _, err := ParseCrossCheckMode(string(cfg.CrossCheckMode)); err != nil {
+			return nil, err
+		}
+	}
+
+	multicaller, err := newMulticaller(cfg, ec)
+	if err != nil {
+		return nil, err
+	}
+
 	return &StakeTableCalculator{
 		config:                   cfg,
 		ethClient:                ec,
 		logger:                   l,
 		crossChainRegistryCaller: registryCaller,
+		multicaller:              multicaller,
+		tracer:                   newTracer(cfg),
+		pageSizer:                newPageSizer(cfg),
+		pageRetryBackoff:         defaultPageRetryBackoff,
 	}, nil
 }
 
 // NewStakeTableRootCalculatorWithRegistryCaller creates a new instance of StakeTableCalculator with a pre-bound registry caller.
 func NewStakeTableRootCalculatorWithRegistryCaller(cfg *Config, ec chainManager.EthClientInterface, registryCaller CrossChainRegistryCallerInterface, l *zap.Logger) (*StakeTableCalculator, error) {
+	if cfg != nil {
+		if _, err := ParseCrossCheckMode(string(cfg.CrossCheckMode)); err != nil {
+			return nil, err
+		}
+	}
+
+	multicaller, err := newMulticaller(cfg, ec)
+	if err != nil {
+		return nil, err
+	}
+
 	return &StakeTableCalculator{
 		config:                   cfg,
 		ethClient:                ec,
 		logger:                   l,
 		crossChainRegistryCaller: registryCaller,
+		multicaller:              multicaller,
+		tracer:                   newTracer(cfg),
+		pageSizer:                newPageSizer(cfg),
+		pageRetryBackoff:         defaultPageRetryBackoff,
 	}, nil
 }
 
+func newTracer(cfg *Config) trace.Tracer {
+	if cfg == nil {
+		return nil
+	}
+	return tracing.NewTracer(cfg.TracerProvider)
+}
+
 // CalculateStakeTableRoot performs the complete calculation for a given reference block.
 func (c *StakeTableCalculator) CalculateStakeTableRoot(
 	ctx context.Context,
 	referenceBlockNumber uint64,
 ) (
 	[32]byte,
-	*merkletree.MerkleTree,
+	*distribution.MerkleTree,
 	*distribution.Distribution,
 	error,
 ) {
-	var zeroRoot [32]byte // Return in case of error or no data
+	ctx, span := tracing.Start(ctx, c.tracer, "StakeTableCalculator.CalculateStakeTableRoot",
+		attribute.Int64("referenceBlockNumber", int64(referenceBlockNumber)),
+	)
+	pin := &bind.CallOpts{BlockNumber: new(big.Int).SetUint64(referenceBlockNumber)}
+	root, tree, dist, err := c.calculateStakeTableRoot(ctx, pin, referenceBlockNumber, nil, nil, nil)
+	if dist != nil {
+		span.SetAttributes(attribute.Int("operatorSetCount", len(dist.GetOperatorSets())))
+	}
+	tracing.End(span, err)
+	return root, tree, dist, err
+}
 
-	callOpts := &bind.CallOpts{
-		Context:     ctx,
-		BlockNumber: new(big.Int).SetUint64(referenceBlockNumber),
+// CalculateStakeTableRootAtSnapshot performs the complete calculation with every read pinned
+// to the snapshot's block hash. The snapshot is re-verified once the calculation finishes;
+// if the reference block was reorged out, an error wrapping snapshot.ErrReorg is returned.
+func (c *StakeTableCalculator) CalculateStakeTableRootAtSnapshot(
+	ctx context.Context,
+	snap *snapshot.Snapshot,
+) (
+	[32]byte,
+	*distribution.MerkleTree,
+	*distribution.Distribution,
+	error,
+) {
+	ctx, span := tracing.Start(ctx, c.tracer, "StakeTableCalculator.CalculateStakeTableRoot",
+		attribute.Int64("referenceBlockNumber", int64(snap.Number)),
+		attribute.String("referenceBlockHash", snap.Hash.Hex()),
+	)
+	root, tree, dist, err := c.calculateStakeTableRoot(ctx, snap.CallOpts(ctx), snap.Number, snap, nil, nil)
+	if dist != nil {
+		span.SetAttributes(attribute.Int("operatorSetCount", len(dist.GetOperatorSets())))
 	}
+	tracing.End(span, err)
+	return root, tree, dist, err
+}
 
-	opsetsWithCalculators, err := c.fetchActiveGenerationReservationsPaginated(callOpts)
+// CalculateStakeTableRootWithReport is CalculateStakeTableRootAtSnapshot that also returns a
+// report of the included and skipped operator sets.
+func (c *StakeTableCalculator) CalculateStakeTableRootWithReport(
+	ctx context.Context,
+	snap *snapshot.Snapshot,
+) (
+	[32]byte,
+	*distribution.MerkleTree,
+	*distribution.Distribution,
+	*CalculationReport,
+	error,
+) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, c.tracer, "StakeTableCalculator.CalculateStakeTableRoot",
+		attribute.Int64("referenceBlockNumber", int64(snap.Number)),
+		attribute.String("referenceBlockHash", snap.Hash.Hex()),
+	)
+	report := newCalculationReport(snap.Number, snap.Hash)
+	root, tree, dist, err := c.calculateStakeTableRoot(ctx, snap.CallOpts(ctx), snap.Number, snap, report, nil)
+	if dist != nil {
+		span.SetAttributes(attribute.Int("operatorSetCount", len(dist.GetOperatorSets())))
+	}
+	tracing.End(span, err)
+	if err != nil {
+		return root, nil, nil, nil, err
+	}
+	report.Elapsed = Duration(time.Since(start))
+	return root, tree, dist, report, nil
+}
+
+// CalculateOperatorTableBytesAtSnapshot calculates the operator table bytes of a single
+// operator set at the snapshot's block hash. Errors are checked for a reorg of the snapshot.
+func (c *StakeTableCalculator) CalculateOperatorTableBytesAtSnapshot(
+	ctx context.Context,
+	snap *snapshot.Snapshot,
+	opset ICrossChainRegistry.OperatorSet,
+) ([]byte, error) {
+	tableBytes, err := c.calculateOperatorTableBytes(ctx, snap.CallOpts(ctx), opset)
+	if err != nil {
+		return nil, snap.CheckError(ctx, c.ethClient, err)
+	}
+	return tableBytes, nil
+}
+
+// calculateStakeTableRoot runs the calculation with every read made at pin, which selects the
+// reference block either by number or by hash. When snap is set, reads are checked for a reorg.
+// The outcome for each reservation is recorded in report, if it is not nil. When reuse is set,
+// the tables of operator sets that did not change since the previous backfill block are reused.
+func (c *StakeTableCalculator) calculateStakeTableRoot(
+	ctx context.Context,
+	pin *bind.CallOpts,
+	referenceBlockNumber uint64,
+	snap *snapshot.Snapshot,
+	report *CalculationReport,
+	reuse *tableReuse,
+) (
+	[32]byte,
+	*distribution.MerkleTree,
+	*distribution.Distribution,
+	error,
+) {
+	var zeroRoot [32]byte // Return in case of error or no data
+	l := logger.WithTraceContext(ctx, c.logger)
+
+	callOpts := *pin
+	callOpts.Context = ctx
+
+	opsetsWithCalculators, err := c.fetchActiveGenerationReservationsPaginated(&callOpts)
+	if snap != nil {
+		err = snap.CheckError(ctx, c.ethClient, err)
+	}
 	if err != nil {
 		return zeroRoot, nil, nil, fmt.Errorf("failed to fetch active generation reservations: %w", err)
 	}
 
-	c.logger.Sugar().Infow("Fetched active generation reservations",
+	report.setTotalReservations(len(opsetsWithCalculators))
+
+	l.Sugar().Infow("Fetched active generation reservations",
 		zap.Any("opsets", opsetsWithCalculators),
 		zap.Uint64("referenceBlockNumber", referenceBlockNumber),
 	)
@@ -97,7 +271,7 @@ func (c *StakeTableCalculator) CalculateStakeTableRoot(
 	dist := distribution.NewDistribution()
 
 	if len(opsetsWithCalculators) == 0 {
-		c.logger.Sugar().Infow("No calculators registered for this block, global table root will be zero.")
+		l.Sugar().Infow("No calculators registered for this block, global table root will be zero.")
 		return zeroRoot, nil, dist, nil
 	}
 
@@ -114,49 +288,89 @@ func (c *StakeTableCalculator) CalculateStakeTableRoot(
 	var opsetTableRoots [][]byte
 	var opsetTableBytes [][]byte
 
-	for i, opset := range allOpsets {
-		c.logger.Sugar().Infow("Calculating operator table bytes for opset",
-			zap.Uint32("opsetId", opset.Id),
-			zap.String("opsetAvs", opset.Avs.String()),
-		)
+	results := c.reuseOrCalculateOperatorTables(ctx, pin, snap, opsetsWithCalculators, reuse)
+
+	var localSnap *snapshot.Snapshot
+	if c.config != nil && (c.config.LocalTableComputer != nil || c.config.LocalFallback != nil) {
+		localSnap = snap
+		if localSnap == nil {
+			if localSnap, err = snapshot.Resolve(ctx, c.ethClient, new(big.Int).SetUint64(referenceBlockNumber)); err != nil {
+				return zeroRoot, nil, nil, fmt.Errorf("failed to resolve reference block for local calculation: %w", err)
+			}
+		}
+	}
+	if c.config != nil && c.config.LocalFallback != nil {
+		c.calculateOutOfGasTablesLocally(ctx, localSnap, opsetsWithCalculators, results)
+	}
+
+	var checks []*CrossCheckResult
+	if c.config != nil && c.config.LocalTableComputer != nil {
+		checks = c.crossCheckOperatorTables(ctx, localSnap, opsetsWithCalculators, results)
+		report.setCrossChecks(checks)
+		if c.crossCheckMode() == CrossCheckAbort {
+			if err := crossCheckError(checks); err != nil {
+				return zeroRoot, nil, nil, err
+			}
+		}
+	}
 
-		tableBytes, err := c.crossChainRegistryCaller.CalculateOperatorTableBytes(&bind.CallOpts{
-			Context:     ctx,
-			BlockNumber: new(big.Int).SetUint64(referenceBlockNumber),
-		}, opsetsWithCalculators[i])
+	for i, opset := range allOpsets {
+		tableBytes, err := results[i].tableBytes, results[i].err
+		if err == nil && checks != nil && checks[i] != nil && checks[i].Status == CrossCheckMismatch && c.crossCheckMode() == CrossCheckExclude {
+			l.Sugar().Errorw("Skipping opset: operator table differs from local calculation",
+				zap.Uint32("opsetId", opset.Id),
+				zap.String("opsetAvs", opset.Avs.String()),
+			)
+			report.skip(opset, ErrCrossCheckMismatch.Error)
+			continue
+		}
 		if err != nil {
-			c.logger.Sugar().Errorw("Skipping opset: CalculateOperatorTableBytes reverted",
+			l.Sugar().Errorw("Skipping opset: CalculateOperatorTableBytes reverted",
 				zap.Uint32("opsetId", opset.Id),
 				zap.String("opsetAvs", opset.Avs.String()),
 				zap.Error(err),
 			)
+			report.skip(opset, func() string { return RevertReason(err) })
 			continue
 		}
-		c.logger.Sugar().Infow("Got operator table bytes for opset",
+		l.Sugar().Infow("Got operator table bytes for opset",
 			zap.Uint32("opsetId", opset.Id),
 			zap.String("opsetAvs", opset.Avs.String()),
 			zap.String("bytes", hexutil.Encode(tableBytes)),
 		)
 
-		encodedLeaf := distribution.EncodeOperatorTableLeaf(tableBytes)
-		c.logger.Sugar().Infow("Encoded operator table leaf for opset",
+		encodedLeaf := results[i].leaf
+		if encodedLeaf == nil {
+			encodedLeaf = distribution.EncodeOperatorTableLeaf(tableBytes)
+		}
+		l.Sugar().Infow("Encoded operator table leaf for opset",
 			zap.Uint32("opsetId", opset.Id),
 			zap.String("opsetAvs", opset.Avs.String()),
 			zap.String("encodedLeaf", hexutil.Encode(encodedLeaf)),
 		)
 
+		report.include(opset, uint64(len(successfulOpsets)), tableBytes, results[i].local)
+		reuse.keep(opsetsWithCalculators[i], tableBytes, encodedLeaf, results[i].local)
 		successfulOpsets = append(successfulOpsets, opset)
 		opsetTableRoots = append(opsetTableRoots, encodedLeaf)
 		opsetTableBytes = append(opsetTableBytes, tableBytes)
 	}
 
+	// Reverts against a vanished block hash look like calculator failures, so confirm the
+	// reference block is still canonical before trusting which opsets were skipped.
+	if snap != nil {
+		if err := snap.Verify(ctx, c.ethClient); err != nil {
+			return zeroRoot, nil, nil, fmt.Errorf("failed to verify reference block: %w", err)
+		}
+	}
+
 	if len(successfulOpsets) == 0 {
-		c.logger.Sugar().Warnw("All operator set calculators failed, global table root will be zero.")
+		l.Sugar().Warnw("All operator set calculators failed, global table root will be zero.")
 		return zeroRoot, nil, dist, nil
 	}
 
 	if len(successfulOpsets) < len(allOpsets) {
-		c.logger.Sugar().Warnw("Some operator set calculators failed, proceeding with successful ones only",
+		l.Sugar().Warnw("Some operator set calculators failed, proceeding with successful ones only",
 			zap.Int("total", len(allOpsets)),
 			zap.Int("successful", len(successfulOpsets)),
 			zap.Int("failed", len(allOpsets)-len(successfulOpsets)),
@@ -171,24 +385,124 @@ func (c *StakeTableCalculator) CalculateStakeTableRoot(
 		}
 	}
 
-	tree, err := merkletree.NewTree(
-		merkletree.WithData(opsetTableRoots),
-		merkletree.WithHashType(keccak256.New()),
-	)
+	tree, err := distribution.NewMerkleTree(opsetTableRoots)
 	if err != nil {
 		return zeroRoot, nil, nil, fmt.Errorf("calculator: failed to create merkle tree: %w", err)
 	}
 
 	merkleRoot := tree.Root()
+	report.setRoot(merkleRoot)
 
-	c.logger.Sugar().Infow("calculated stake table root",
+	l.Sugar().Infow("calculated stake table root",
 		zap.String("root", hexutil.Encode(merkleRoot[:])),
 		zap.Uint64("referenceBlockNumber", referenceBlockNumber),
 	)
-	return [32]byte(merkleRoot), tree, dist, nil
+	return merkleRoot, tree, dist, nil
+}
+
+// operatorTableResult is the outcome of CalculateOperatorTableBytes for a single opset.
+type operatorTableResult struct {
+	tableBytes []byte
+	err        error
+	// local is set when the table was calculated by Config.LocalFallback
+	local bool
+	// reused is set when the table was carried over from the previous block of a backfill
+	reused bool
+	// leaf is the encoded leaf of a reused table
+	leaf []byte
+}
+
+// calculateOperatorTables calls CalculateOperatorTableBytes for every opset, with at most
+// Config.Parallelism calls in flight. Results are returned in the same order as opsets,
+// so leaf order does not depend on which calls finish first.
+func (c *StakeTableCalculator) calculateOperatorTables(
+	ctx context.Context,
+	pin *bind.CallOpts,
+	opsets []ICrossChainRegistry.OperatorSet,
+) []operatorTableResult {
+	l := logger.WithTraceContext(ctx, c.logger)
+
+	if c.multicaller != nil {
+		batched, err := c.calculateOperatorTablesMulticall(ctx, pin, opsets)
+		if err == nil {
+			return batched
+		}
+		l.Sugar().Warnw("Multicall failed, falling back to individual CalculateOperatorTableBytes calls",
+			zap.Error(err),
+		)
+	}
+
+	results := make([]operatorTableResult, len(opsets))
+	indices := make([]int, len(opsets))
+	for i := range opsets {
+		indices[i] = i
+	}
+	c.calculateOperatorTablesIndividually(ctx, pin, opsets, indices, results)
+	return results
+}
+
+// calculateOperatorTablesIndividually calls CalculateOperatorTableBytes for the opsets at the
+// given indices, with at most Config.Parallelism calls in flight, and stores each outcome in
+// results at the same index.
+func (c *StakeTableCalculator) calculateOperatorTablesIndividually(
+	ctx context.Context,
+	pin *bind.CallOpts,
+	opsets []ICrossChainRegistry.OperatorSet,
+	indices []int,
+	results []operatorTableResult,
+) {
+	l := logger.WithTraceContext(ctx, c.logger)
+
+	sem := make(chan struct{}, c.parallelism())
+	var wg sync.WaitGroup
+	for _, i := range indices {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, opset ICrossChainRegistry.OperatorSet) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			l.Sugar().Infow("Calculating operator table bytes for opset",
+				zap.Uint32("opsetId", opset.Id),
+				zap.String("opsetAvs", opset.Avs.String()),
+			)
+			tableBytes, err := c.calculateOperatorTableBytes(ctx, pin, opset)
+			results[i] = operatorTableResult{tableBytes: tableBytes, err: err}
+		}(i, opsets[i])
+	}
+	wg.Wait()
+}
+
+// parallelism returns the configured number of concurrent calculator calls, at least 1.
+func (c *StakeTableCalculator) parallelism() int {
+	if c.config == nil || c.config.Parallelism < 1 {
+		return 1
+	}
+	return c.config.Parallelism
+}
+
+// calculateOperatorTableBytes calls CalculateOperatorTableBytes for a single opset at the reference block.
+func (c *StakeTableCalculator) calculateOperatorTableBytes(
+	ctx context.Context,
+	pin *bind.CallOpts,
+	opset ICrossChainRegistry.OperatorSet,
+) ([]byte, error) {
+	ctx, span := tracing.Start(ctx, c.tracer, "StakeTableCalculator.CalculateOperatorTableBytes",
+		attribute.Int64("opsetId", int64(opset.Id)),
+		attribute.String("opsetAvs", opset.Avs.String()),
+	)
+	callOpts := *pin
+	callOpts.Context = ctx
+	tableBytes, err := c.crossChainRegistryCaller.CalculateOperatorTableBytes(&callOpts, opset)
+	span.SetAttributes(attribute.Int("tableBytesLength", len(tableBytes)))
+	tracing.End(span, err)
+	return tableBytes, err
 }
 
 // fetchActiveGenerationReservationsPaginated fetches active generation reservations using pagination.
+// Once every page is fetched, the reservation count is read again at the same block, so that an
+// RPC that does not serve the pinned block consistently fails the calculation instead of
+// producing a partial reservation list.
 func (c *StakeTableCalculator) fetchActiveGenerationReservationsPaginated(
 	callOpts *bind.CallOpts,
 ) ([]ICrossChainRegistry.OperatorSet, error) {
@@ -202,26 +516,66 @@ func (c *StakeTableCalculator) fetchActiveGenerationReservationsPaginated(
 		return []ICrossChainRegistry.OperatorSet{}, nil
 	}
 
-	const pageSize = 50
 	var allReservations []ICrossChainRegistry.OperatorSet
-
-	for startIndex := uint64(0); startIndex < totalCount.Uint64(); startIndex += pageSize {
-		endIndex := startIndex + pageSize
-		if endIndex > totalCount.Uint64() {
-			endIndex = totalCount.Uint64()
+	if c.multicaller != nil {
+		pageSize := c.pageSizer.current()
+		var pages []reservationPage
+		for startIndex := uint64(0); startIndex < totalCount.Uint64(); startIndex += pageSize {
+			endIndex := min(startIndex+pageSize, totalCount.Uint64())
+			pages = append(pages, reservationPage{startIndex: startIndex, endIndex: endIndex})
 		}
 
-		pageReservations, err := c.crossChainRegistryCaller.GetActiveGenerationReservationsByRange(
-			callOpts,
-			new(big.Int).SetUint64(startIndex),
-			new(big.Int).SetUint64(endIndex),
-		)
+		c.fetchActiveGenerationReservationPagesMulticall(callOpts, pages)
+
+		for _, page := range pages {
+			if !page.fetched {
+				pageReservations, err := c.fetchActiveGenerationReservationsRange(callOpts, page.startIndex, page.endIndex)
+				if err != nil {
+					return nil, err
+				}
+				page.reservations = pageReservations
+			}
+			allReservations = append(allReservations, page.reservations...)
+		}
+	} else {
+		allReservations, err = c.fetchActiveGenerationReservationsRange(callOpts, 0, totalCount.Uint64())
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch active generation reservations for range [%d, %d): %w", startIndex, endIndex, err)
+			return nil, err
 		}
+	}
 
-		allReservations = append(allReservations, pageReservations...)
+	recount, err := c.crossChainRegistryCaller.GetActiveGenerationReservationCount(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-check generation reservation count: %w", err)
+	}
+	if recount.Cmp(totalCount) != 0 {
+		return nil, fmt.Errorf("generation reservation count changed from %s to %s while paging at the same block", totalCount, recount)
+	}
+	if uint64(len(allReservations)) != totalCount.Uint64() {
+		return nil, fmt.Errorf("fetched %d active generation reservations, expected %s", len(allReservations), totalCount)
 	}
 
 	return allReservations, nil
 }
+
+// fetchActiveGenerationReservationsPage fetches the reservations in [startIndex, endIndex).
+func (c *StakeTableCalculator) fetchActiveGenerationReservationsPage(
+	callOpts *bind.CallOpts,
+	startIndex uint64,
+	endIndex uint64,
+) ([]ICrossChainRegistry.OperatorSet, error) {
+	ctx, span := tracing.Start(callOpts.Context, c.tracer, "StakeTableCalculator.GetActiveGenerationReservationsByRange",
+		attribute.Int64("startIndex", int64(startIndex)),
+		attribute.Int64("endIndex", int64(endIndex)),
+	)
+	pageOpts := *callOpts
+	pageOpts.Context = ctx
+
+	pageReservations, err := c.crossChainRegistryCaller.GetActiveGenerationReservationsByRange(
+		&pageOpts,
+		new(big.Int).SetUint64(startIndex),
+		new(big.Int).SetUint64(endIndex),
+	)
+	tracing.End(span, err)
+	return pageReservations, err
+}
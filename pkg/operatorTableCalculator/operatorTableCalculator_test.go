@@ -1,18 +1,29 @@
 package operatorTableCalculator
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"math/big"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
 	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
 	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/multicall"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/zap"
 )
 
@@ -432,3 +443,399 @@ func TestStakeTableCalculatorConfig_Validation(t *testing.T) {
 		})
 	}
 }
+
+// TestCalculateStakeTableRoot_Tracing verifies that a calculation with a tracer provider
+// configured records a root span with child spans for each page and each opset.
+func TestCalculateStakeTableRoot_Tracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	mockEthClient := chainManager.NewMockEthClientInterface(t)
+	mockRegistryCaller := NewMockICrossChainRegistryCaller(t)
+	logger, _ := zap.NewDevelopment()
+	calculator, err := NewStakeTableRootCalculatorWithRegistryCaller(&Config{
+		CrossChainRegistryAddress: common.HexToAddress("0x1234567890123456789012345678901234567890"),
+		TracerProvider:            tp,
+	}, mockEthClient, mockRegistryCaller, logger)
+	require.NoError(t, err)
+
+	blockNumber := uint64(12345)
+	atBlock := mock.MatchedBy(func(opts *bind.CallOpts) bool {
+		return opts.BlockNumber.Uint64() == blockNumber
+	})
+
+	allOpsets := createTestOperatorSets(2)
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", atBlock).
+		Return(big.NewInt(2), nil)
+	mockRegistryCaller.On("GetActiveGenerationReservationsByRange", atBlock, big.NewInt(0), big.NewInt(2)).
+		Return(allOpsets, nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", atBlock, allOpsets[0]).
+		Return([]byte{0x01}, nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", atBlock, allOpsets[1]).
+		Return([]byte(nil), errors.New("execution reverted"))
+
+	_, _, _, err = calculator.CalculateStakeTableRoot(context.Background(), blockNumber)
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	spansByName := make(map[string][]tracetest.SpanStub)
+	for _, s := range spans {
+		spansByName[s.Name] = append(spansByName[s.Name], s)
+	}
+
+	require.Len(t, spansByName["StakeTableCalculator.CalculateStakeTableRoot"], 1)
+	root := spansByName["StakeTableCalculator.CalculateStakeTableRoot"][0]
+
+	pages := spansByName["StakeTableCalculator.GetActiveGenerationReservationsByRange"]
+	require.Len(t, pages, 1)
+	assert.Equal(t, root.SpanContext.SpanID(), pages[0].Parent.SpanID())
+
+	opsetSpans := spansByName["StakeTableCalculator.CalculateOperatorTableBytes"]
+	require.Len(t, opsetSpans, 2)
+	var failed int
+	for _, s := range opsetSpans {
+		assert.Equal(t, root.SpanContext.TraceID(), s.SpanContext.TraceID())
+		assert.Equal(t, root.SpanContext.SpanID(), s.Parent.SpanID())
+		if s.Status.Code == codes.Error {
+			failed++
+		}
+	}
+	assert.Equal(t, 1, failed, "the reverting opset should be recorded as an errored span")
+}
+
+// TestCalculateStakeTableRootAtSnapshot_PinsReadsToBlockHash verifies that every registry read is
+// made at the snapshot's block hash rather than its number.
+func TestCalculateStakeTableRootAtSnapshot_PinsReadsToBlockHash(t *testing.T) {
+	calculator, mockRegistryCaller, mockEthClient := setupTestCalculator(t)
+
+	header := &types.Header{Number: big.NewInt(12345), Time: 1700000000}
+	snap := snapshot.New(header)
+	callOpts := &bind.CallOpts{
+		Context:   context.Background(),
+		BlockHash: header.Hash(),
+	}
+
+	opsets := createTestOperatorSets(2)
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).
+		Return(big.NewInt(2), nil)
+	mockRegistryCaller.On("GetActiveGenerationReservationsByRange", callOpts, big.NewInt(0), big.NewInt(2)).
+		Return(opsets, nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[0]).
+		Return([]byte{0x01}, nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[1]).
+		Return([]byte{0x02}, nil)
+	mockEthClient.On("HeaderByNumber", mock.Anything, big.NewInt(12345)).
+		Return(header, nil)
+
+	root, tree, dist, err := calculator.CalculateStakeTableRootAtSnapshot(context.Background(), snap)
+	require.NoError(t, err)
+	assert.NotEqual(t, [32]byte{}, root)
+	assert.NotNil(t, tree)
+	assert.Len(t, dist.GetOrderedOperatorSets(), 2)
+}
+
+// TestCalculateStakeTableRootAtSnapshot_Reorg verifies that the calculation aborts with
+// snapshot.ErrReorg when the reference block is replaced mid-run, including when the
+// vanished hash makes every calculator call fail.
+func TestCalculateStakeTableRootAtSnapshot_Reorg(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(12345), Time: 1700000000}
+	reorged := &types.Header{Number: big.NewInt(12345), Time: 1700000012}
+	callOpts := &bind.CallOpts{
+		Context:   context.Background(),
+		BlockHash: header.Hash(),
+	}
+
+	t.Run("reservation read fails", func(t *testing.T) {
+		calculator, mockRegistryCaller, mockEthClient := setupTestCalculator(t)
+		mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).
+			Return(nil, errors.New("header for hash not found"))
+		mockEthClient.On("HeaderByNumber", mock.Anything, big.NewInt(12345)).
+			Return(reorged, nil)
+
+		_, _, _, err := calculator.CalculateStakeTableRootAtSnapshot(context.Background(), snapshot.New(header))
+		require.Error(t, err)
+		assert.ErrorIs(t, err, snapshot.ErrReorg)
+	})
+
+	t.Run("calculator reads fail", func(t *testing.T) {
+		calculator, mockRegistryCaller, mockEthClient := setupTestCalculator(t)
+		opsets := createTestOperatorSets(1)
+		mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).
+			Return(big.NewInt(1), nil)
+		mockRegistryCaller.On("GetActiveGenerationReservationsByRange", callOpts, big.NewInt(0), big.NewInt(1)).
+			Return(opsets, nil)
+		mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[0]).
+			Return([]byte(nil), errors.New("header for hash not found"))
+		mockEthClient.On("HeaderByNumber", mock.Anything, big.NewInt(12345)).
+			Return(reorged, nil)
+
+		_, _, _, err := calculator.CalculateStakeTableRootAtSnapshot(context.Background(), snapshot.New(header))
+		require.Error(t, err)
+		assert.ErrorIs(t, err, snapshot.ErrReorg)
+	})
+}
+
+func TestCalculateOperatorTableBytesAtSnapshot(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(12345), Time: 1700000000}
+	callOpts := &bind.CallOpts{
+		Context:   context.Background(),
+		BlockHash: header.Hash(),
+	}
+	opsets := createTestOperatorSets(2)
+
+	calculator, mockRegistryCaller, mockEthClient := setupTestCalculator(t)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[0]).
+		Return([]byte{0x01, 0x02}, nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[1]).
+		Return([]byte(nil), errors.New("header for hash not found"))
+	mockEthClient.On("HeaderByNumber", mock.Anything, big.NewInt(12345)).
+		Return(&types.Header{Number: big.NewInt(12345), Time: 1700000012}, nil)
+
+	tableBytes, err := calculator.CalculateOperatorTableBytesAtSnapshot(context.Background(), snapshot.New(header), opsets[0])
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x02}, tableBytes)
+
+	_, err = calculator.CalculateOperatorTableBytesAtSnapshot(context.Background(), snapshot.New(header), opsets[1])
+	assert.ErrorIs(t, err, snapshot.ErrReorg)
+}
+
+// TestCalculateStakeTableRoot_Parallel verifies that concurrent table calculation never exceeds
+// the configured parallelism, isolates reverting calculators, and produces the same leaf order
+// and root as a sequential run regardless of the order calls complete in.
+func TestCalculateStakeTableRoot_Parallel(t *testing.T) {
+	const (
+		opsetCount  = 20
+		parallelism = 4
+	)
+	blockNumber := uint64(12345)
+	callOpts := &bind.CallOpts{
+		Context:     context.Background(),
+		BlockNumber: new(big.Int).SetUint64(blockNumber),
+	}
+	opsets := createTestOperatorSets(opsetCount)
+	reverting := map[uint32]bool{3: true, 11: true}
+
+	setupMocks := func(mockRegistryCaller *MockICrossChainRegistryCaller, inFlight, maxInFlight *atomic.Int32) {
+		mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).
+			Return(big.NewInt(opsetCount), nil)
+		mockRegistryCaller.On("GetActiveGenerationReservationsByRange", callOpts, big.NewInt(0), big.NewInt(opsetCount)).
+			Return(opsets, nil)
+		for i, opset := range opsets {
+			// Earlier opsets take longer, so calls complete roughly in reverse order
+			delay := time.Duration(opsetCount-i) * time.Millisecond
+			call := mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opset).
+				Run(func(mock.Arguments) {
+					n := inFlight.Add(1)
+					for {
+						m := maxInFlight.Load()
+						if n <= m || maxInFlight.CompareAndSwap(m, n) {
+							break
+						}
+					}
+					time.Sleep(delay)
+					inFlight.Add(-1)
+				})
+			if reverting[opset.Id] {
+				call.Return([]byte(nil), errors.New("execution reverted"))
+			} else {
+				call.Return([]byte{byte(opset.Id), 0xff}, nil)
+			}
+		}
+	}
+
+	// Sequential reference run
+	sequential, seqRegistryCaller, _ := setupTestCalculator(t)
+	var seqInFlight, seqMaxInFlight atomic.Int32
+	setupMocks(seqRegistryCaller, &seqInFlight, &seqMaxInFlight)
+	seqRoot, _, seqDist, err := sequential.CalculateStakeTableRoot(context.Background(), blockNumber)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), seqMaxInFlight.Load())
+
+	// Parallel run
+	parallel, parRegistryCaller, _ := setupTestCalculator(t)
+	parallel.config.Parallelism = parallelism
+	var parInFlight, parMaxInFlight atomic.Int32
+	setupMocks(parRegistryCaller, &parInFlight, &parMaxInFlight)
+	parRoot, parTree, parDist, err := parallel.CalculateStakeTableRoot(context.Background(), blockNumber)
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, parMaxInFlight.Load(), int32(parallelism))
+	assert.Greater(t, parMaxInFlight.Load(), int32(1))
+	assert.Equal(t, seqRoot, parRoot)
+	assert.NotNil(t, parTree)
+
+	ordered := parDist.GetOrderedOperatorSets()
+	assert.Equal(t, seqDist.GetOrderedOperatorSets(), ordered)
+	require.Len(t, ordered, opsetCount-len(reverting))
+	for i := 1; i < len(ordered); i++ {
+		assert.Less(t, ordered[i-1].Id, ordered[i].Id, "leaf order must follow reservation order")
+	}
+	for id := range reverting {
+		_, found := parDist.GetTableIndex(distribution.OperatorSet{Id: id, Avs: opsets[0].Avs})
+		assert.False(t, found, "reverting opset %d should not be in distribution", id)
+	}
+}
+
+// multicallResponder answers batched registry calls the way the CrossChainRegistry would,
+// returning failed results for opsets in reverting.
+func multicallResponder(t *testing.T, opsets []ICrossChainRegistry.OperatorSet, reverting map[uint32]bool) func(*bind.CallOpts, []multicall.Call) ([]multicall.Result, error) {
+	registryAbi, err := ICrossChainRegistry.ICrossChainRegistryMetaData.GetAbi()
+	require.NoError(t, err)
+	rangeMethod := registryAbi.Methods["getActiveGenerationReservationsByRange"]
+	tableMethod := registryAbi.Methods["calculateOperatorTableBytes"]
+
+	return func(_ *bind.CallOpts, calls []multicall.Call) ([]multicall.Result, error) {
+		results := make([]multicall.Result, len(calls))
+		for i, call := range calls {
+			switch {
+			case bytes.Equal(call.CallData[:4], rangeMethod.ID):
+				args, err := rangeMethod.Inputs.Unpack(call.CallData[4:])
+				require.NoError(t, err)
+				start, end := args[0].(*big.Int).Uint64(), args[1].(*big.Int).Uint64()
+				out, err := rangeMethod.Outputs.Pack(opsets[start:end])
+				require.NoError(t, err)
+				results[i] = multicall.Result{Success: true, ReturnData: out}
+			case bytes.Equal(call.CallData[:4], tableMethod.ID):
+				args, err := tableMethod.Inputs.Unpack(call.CallData[4:])
+				require.NoError(t, err)
+				opset := *abi.ConvertType(args[0], new(ICrossChainRegistry.OperatorSet)).(*ICrossChainRegistry.OperatorSet)
+				if reverting[opset.Id] {
+					results[i] = multicall.Result{Success: false}
+					continue
+				}
+				out, err := tableMethod.Outputs.Pack([]byte{byte(opset.Id), 0xff})
+				require.NoError(t, err)
+				results[i] = multicall.Result{Success: true, ReturnData: out}
+			default:
+				t.Fatalf("unexpected call data %x", call.CallData)
+			}
+		}
+		return results, nil
+	}
+}
+
+// TestCalculateStakeTableRoot_Multicall verifies that batched reads produce the same distribution
+// as individual calls, and that opsets failing inside a batch are retried individually before
+// being skipped.
+func TestCalculateStakeTableRoot_Multicall(t *testing.T) {
+	blockNumber := uint64(12345)
+	callOpts := &bind.CallOpts{
+		Context:     context.Background(),
+		BlockNumber: new(big.Int).SetUint64(blockNumber),
+	}
+	opsets := createTestOperatorSets(60)
+	reverting := map[uint32]bool{7: true}
+
+	calculator, mockRegistryCaller, _ := setupTestCalculator(t)
+	mockMulticaller := NewMockMulticallerInterface(t)
+	calculator.multicaller = mockMulticaller
+
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).
+		Return(big.NewInt(60), nil)
+	mockMulticaller.On("TryAggregate", callOpts, mock.Anything).
+		Return(multicallResponder(t, opsets, reverting), nil).Times(2)
+	// The opset that failed inside the batch is retried on its own
+	mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[6]).
+		Return([]byte(nil), errors.New("execution reverted")).Once()
+
+	root, tree, dist, err := calculator.CalculateStakeTableRoot(context.Background(), blockNumber)
+	require.NoError(t, err)
+	assert.NotEqual(t, [32]byte{}, root)
+	assert.NotNil(t, tree)
+
+	ordered := dist.GetOrderedOperatorSets()
+	require.Len(t, ordered, 59)
+	for _, opset := range ordered {
+		data, found := dist.GetTableData(opset)
+		require.True(t, found)
+		assert.Equal(t, []byte{byte(opset.Id), 0xff}, data)
+	}
+	_, found := dist.GetTableIndex(distribution.OperatorSet{Id: 7, Avs: opsets[6].Avs})
+	assert.False(t, found)
+}
+
+// TestCalculateStakeTableRoot_MulticallFallback verifies that a failing Multicall3 falls back to
+// individual registry calls.
+func TestCalculateStakeTableRoot_MulticallFallback(t *testing.T) {
+	blockNumber := uint64(12345)
+	callOpts := &bind.CallOpts{
+		Context:     context.Background(),
+		BlockNumber: new(big.Int).SetUint64(blockNumber),
+	}
+	opsets := createTestOperatorSets(2)
+
+	calculator, mockRegistryCaller, _ := setupTestCalculator(t)
+	mockMulticaller := NewMockMulticallerInterface(t)
+	calculator.multicaller = mockMulticaller
+
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).
+		Return(big.NewInt(2), nil)
+	mockMulticaller.On("TryAggregate", callOpts, mock.Anything).
+		Return(nil, errors.New("multicall3 not deployed")).Times(2)
+	mockRegistryCaller.On("GetActiveGenerationReservationsByRange", callOpts, big.NewInt(0), big.NewInt(2)).
+		Return(opsets, nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[0]).
+		Return([]byte{0x01}, nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[1]).
+		Return([]byte{0x02}, nil)
+
+	_, _, dist, err := calculator.CalculateStakeTableRoot(context.Background(), blockNumber)
+	require.NoError(t, err)
+	assert.Len(t, dist.GetOrderedOperatorSets(), 2)
+}
+
+// TestCalculateStakeTableRoot_MulticallBatchFailure verifies that opsets whose whole batch failed
+// are calculated with individual calls while the rest of the batched results are kept.
+func TestCalculateStakeTableRoot_MulticallBatchFailure(t *testing.T) {
+	blockNumber := uint64(12345)
+	callOpts := &bind.CallOpts{
+		Context:     context.Background(),
+		BlockNumber: new(big.Int).SetUint64(blockNumber),
+	}
+	opsets := createTestOperatorSets(6)
+	respond := multicallResponder(t, opsets, nil)
+	registryAbi, err := ICrossChainRegistry.ICrossChainRegistryMetaData.GetAbi()
+	require.NoError(t, err)
+	tableMethod := registryAbi.Methods["calculateOperatorTableBytes"]
+
+	calculator, mockRegistryCaller, _ := setupTestCalculator(t)
+	calculator.config.Parallelism = 2
+	mockMulticaller := NewMockMulticallerInterface(t)
+	calculator.multicaller = mockMulticaller
+
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).
+		Return(big.NewInt(6), nil)
+	mockMulticaller.On("TryAggregate", callOpts, mock.Anything).
+		Return(func(opts *bind.CallOpts, calls []multicall.Call) ([]multicall.Result, error) {
+			results, err := respond(opts, calls)
+			if err != nil || !bytes.Equal(calls[0].CallData[:4], tableMethod.ID) {
+				return results, err
+			}
+			// The batch holding the last three opsets failed as a whole
+			batchErr := errors.New("failed to execute multicall batch of 3 calls: unknown block")
+			for i := 3; i < len(results); i++ {
+				results[i] = multicall.Result{Err: batchErr}
+			}
+			return results, nil
+		}, nil).Times(2)
+	for _, opset := range opsets[3:] {
+		mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opset).
+			Return([]byte{byte(opset.Id), 0xee}, nil).Once()
+	}
+
+	_, _, dist, err := calculator.CalculateStakeTableRoot(context.Background(), blockNumber)
+	require.NoError(t, err)
+
+	ordered := dist.GetOrderedOperatorSets()
+	require.Len(t, ordered, 6)
+	for i, opset := range ordered {
+		data, found := dist.GetTableData(opset)
+		require.True(t, found)
+		if i < 3 {
+			assert.Equal(t, []byte{byte(opset.Id), 0xff}, data)
+		} else {
+			assert.Equal(t, []byte{byte(opset.Id), 0xee}, data)
+		}
+	}
+}
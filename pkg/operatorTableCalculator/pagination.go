@@ -0,0 +1,211 @@
+package operatorTableCalculator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/multichain-go/pkg/logger"
+	"github.com/Layr-Labs/multichain-go/pkg/multicall"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultPageSize is the starting page size used when Config.PageSize is unset.
+	DefaultPageSize = 50
+	// DefaultPageRetries is the number of retries used when Config.PageRetries is unset.
+	DefaultPageRetries = 3
+	// defaultPageRetryBackoff is the delay before the first retry of a page; it doubles on each retry.
+	defaultPageRetryBackoff = 500 * time.Millisecond
+)
+
+// pageErrorClass is how a failed GetActiveGenerationReservationsByRange call is handled.
+type pageErrorClass int
+
+const (
+	// pageErrorFatal fails the calculation
+	pageErrorFatal pageErrorClass = iota
+	// pageErrorTooLarge halves the page size and retries the range
+	pageErrorTooLarge
+	// pageErrorTransient retries the same page after a backoff
+	pageErrorTransient
+)
+
+var transientPageErrorMarkers = []string{
+	"too many requests",
+	"rate limit",
+	"connection reset",
+	"connection refused",
+	"broken pipe",
+	"unexpected eof",
+	"bad gateway",
+	"service unavailable",
+	"temporarily unavailable",
+}
+
+// timeoutPageErrorMarkers shrink a page like multicall.IsLimitError does, since a smaller
+// page is also faster to serve.
+var timeoutPageErrorMarkers = []string{
+	"timeout",
+	"timed out",
+	"deadline exceeded",
+}
+
+// classifyPageError decides whether a failed page is retried as is, retried smaller, or fatal.
+func classifyPageError(err error) pageErrorClass {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusRequestEntityTooLarge, http.StatusRequestTimeout, http.StatusGatewayTimeout:
+			return pageErrorTooLarge
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable:
+			return pageErrorTransient
+		}
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, marker := range transientPageErrorMarkers {
+		if strings.Contains(message, marker) {
+			return pageErrorTransient
+		}
+	}
+	if multicall.IsLimitError(err) {
+		return pageErrorTooLarge
+	}
+	for _, marker := range timeoutPageErrorMarkers {
+		if strings.Contains(message, marker) {
+			return pageErrorTooLarge
+		}
+	}
+	return pageErrorFatal
+}
+
+// pageSizer tracks the reservation page size. It doubles after each full page up to its
+// maximum and halves when the RPC rejects a page. The maximum is never lowered, so a single
+// timeout does not cap the size for good. The current size is kept across calculations. It is
+// safe for concurrent use.
+type pageSizer struct {
+	mu      sync.Mutex
+	size    int
+	maxSize int
+}
+
+func newPageSizer(cfg *Config) *pageSizer {
+	size := DefaultPageSize
+	if cfg != nil && cfg.PageSize > 0 {
+		size = cfg.PageSize
+	}
+	maxSize := size
+	if cfg != nil && cfg.MaxPageSize > size {
+		maxSize = cfg.MaxPageSize
+	}
+	return &pageSizer{size: size, maxSize: maxSize}
+}
+
+func (p *pageSizer) current() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return uint64(p.size)
+}
+
+// shrink halves the page size after a page of the given size failed.
+func (p *pageSizer) shrink(failedSize uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if next := int(failedSize / 2); next < p.size {
+		p.size = max(next, 1)
+	}
+}
+
+// grow doubles the page size after a full page of the given size succeeded.
+func (p *pageSizer) grow(succeededSize uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if int(succeededSize) >= p.size {
+		p.size = min(p.size*2, p.maxSize)
+	}
+}
+
+func (c *StakeTableCalculator) pageRetries() int {
+	if c.config == nil || c.config.PageRetries == 0 {
+		return DefaultPageRetries
+	}
+	return max(c.config.PageRetries, 0)
+}
+
+// fetchActiveGenerationReservationsRange fetches the reservations in [startIndex, endIndex)
+// in pages of the current page size, halving it when a page is too large or times out.
+func (c *StakeTableCalculator) fetchActiveGenerationReservationsRange(
+	callOpts *bind.CallOpts,
+	startIndex uint64,
+	endIndex uint64,
+) ([]ICrossChainRegistry.OperatorSet, error) {
+	l := logger.WithTraceContext(callContext(callOpts), c.logger)
+
+	var reservations []ICrossChainRegistry.OperatorSet
+	for startIndex < endIndex {
+		size := min(c.pageSizer.current(), endIndex-startIndex)
+		pageEnd := startIndex + size
+		page, err := c.fetchActiveGenerationReservationsPageWithRetry(callOpts, startIndex, pageEnd)
+		if err != nil {
+			if size > 1 && callContext(callOpts).Err() == nil && classifyPageError(err) == pageErrorTooLarge {
+				c.pageSizer.shrink(size)
+				l.Sugar().Warnw("Reservation page rejected, retrying with a smaller page",
+					zap.Uint64("startIndex", startIndex),
+					zap.Uint64("endIndex", pageEnd),
+					zap.Uint64("pageSize", c.pageSizer.current()),
+					zap.Error(err),
+				)
+				continue
+			}
+			return nil, fmt.Errorf("failed to fetch active generation reservations for range [%d, %d): %w", startIndex, pageEnd, err)
+		}
+		reservations = append(reservations, page...)
+		c.pageSizer.grow(size)
+		startIndex = pageEnd
+	}
+	return reservations, nil
+}
+
+// fetchActiveGenerationReservationsPageWithRetry fetches one page, retrying transient failures
+// with exponential backoff.
+func (c *StakeTableCalculator) fetchActiveGenerationReservationsPageWithRetry(
+	callOpts *bind.CallOpts,
+	startIndex uint64,
+	endIndex uint64,
+) ([]ICrossChainRegistry.OperatorSet, error) {
+	ctx := callContext(callOpts)
+	backoff := c.pageRetryBackoff
+	for attempt := 0; ; attempt++ {
+		page, err := c.fetchActiveGenerationReservationsPage(callOpts, startIndex, endIndex)
+		if err == nil || attempt >= c.pageRetries() || classifyPageError(err) != pageErrorTransient {
+			return page, err
+		}
+		logger.WithTraceContext(ctx, c.logger).Sugar().Debugw("Reservation page failed, retrying",
+			zap.Uint64("startIndex", startIndex),
+			zap.Uint64("endIndex", endIndex),
+			zap.Int("attempt", attempt+1),
+			zap.Error(err),
+		)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func callContext(callOpts *bind.CallOpts) context.Context {
+	if callOpts.Context == nil {
+		return context.Background()
+	}
+	return callOpts.Context
+}
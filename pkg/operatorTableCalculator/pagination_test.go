@@ -0,0 +1,161 @@
+package operatorTableCalculator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupPagingCalculator(t *testing.T, cfg *Config) (*StakeTableCalculator, *MockICrossChainRegistryCaller) {
+	mockRegistryCaller := NewMockICrossChainRegistryCaller(t)
+	cfg.CrossChainRegistryAddress = common.HexToAddress("0x1234567890123456789012345678901234567890")
+	calculator, err := NewStakeTableRootCalculatorWithRegistryCaller(cfg, chainManager.NewMockEthClientInterface(t), mockRegistryCaller, zap.NewNop())
+	require.NoError(t, err)
+	calculator.pageRetryBackoff = 0
+	return calculator, mockRegistryCaller
+}
+
+func expectRange(m *MockICrossChainRegistryCaller, callOpts *bind.CallOpts, opsets []ICrossChainRegistry.OperatorSet, start, end int64) {
+	m.On("GetActiveGenerationReservationsByRange", callOpts, big.NewInt(start), big.NewInt(end)).
+		Return(opsets[start:end], nil)
+}
+
+func TestFetchActiveGenerationReservationsPaginated_HalvesRejectedPages(t *testing.T) {
+	calculator, mockRegistryCaller := setupPagingCalculator(t, &Config{})
+	callOpts := &bind.CallOpts{Context: context.Background(), BlockNumber: big.NewInt(12345)}
+	opsets := createTestOperatorSets(100)
+
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).Return(big.NewInt(100), nil)
+	mockRegistryCaller.On("GetActiveGenerationReservationsByRange", callOpts, big.NewInt(0), big.NewInt(50)).
+		Return([]ICrossChainRegistry.OperatorSet(nil), errors.New("response size exceeded"))
+	expectRange(mockRegistryCaller, callOpts, opsets, 0, 25)
+	expectRange(mockRegistryCaller, callOpts, opsets, 25, 75)
+	expectRange(mockRegistryCaller, callOpts, opsets, 75, 100)
+
+	result, err := calculator.fetchActiveGenerationReservationsPaginated(callOpts)
+	require.NoError(t, err)
+	assert.Equal(t, opsets, result)
+	assert.Equal(t, uint64(50), calculator.pageSizer.current(), "the page size grows back after a rejection")
+}
+
+func TestFetchActiveGenerationReservationsPaginated_TimeoutDoesNotCapSize(t *testing.T) {
+	calculator, mockRegistryCaller := setupPagingCalculator(t, &Config{PageSize: 40})
+	callOpts := &bind.CallOpts{Context: context.Background(), BlockNumber: big.NewInt(12345)}
+	opsets := createTestOperatorSets(100)
+
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).Return(big.NewInt(100), nil)
+	mockRegistryCaller.On("GetActiveGenerationReservationsByRange", callOpts, big.NewInt(0), big.NewInt(40)).
+		Return([]ICrossChainRegistry.OperatorSet(nil), rpc.HTTPError{StatusCode: 504, Status: "504 Gateway Timeout"}).Once()
+	expectRange(mockRegistryCaller, callOpts, opsets, 0, 20)
+	expectRange(mockRegistryCaller, callOpts, opsets, 20, 60)
+	expectRange(mockRegistryCaller, callOpts, opsets, 60, 100)
+
+	result, err := calculator.fetchActiveGenerationReservationsPaginated(callOpts)
+	require.NoError(t, err)
+	assert.Equal(t, opsets, result)
+	assert.Equal(t, uint64(40), calculator.pageSizer.current())
+}
+
+func TestFetchActiveGenerationReservationsPaginated_GrowsOnSuccess(t *testing.T) {
+	calculator, mockRegistryCaller := setupPagingCalculator(t, &Config{PageSize: 10, MaxPageSize: 40})
+	callOpts := &bind.CallOpts{Context: context.Background(), BlockNumber: big.NewInt(12345)}
+	opsets := createTestOperatorSets(110)
+
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).Return(big.NewInt(110), nil)
+	expectRange(mockRegistryCaller, callOpts, opsets, 0, 10)
+	expectRange(mockRegistryCaller, callOpts, opsets, 10, 30)
+	expectRange(mockRegistryCaller, callOpts, opsets, 30, 70)
+	expectRange(mockRegistryCaller, callOpts, opsets, 70, 110)
+
+	result, err := calculator.fetchActiveGenerationReservationsPaginated(callOpts)
+	require.NoError(t, err)
+	assert.Equal(t, opsets, result)
+	assert.Equal(t, uint64(40), calculator.pageSizer.current())
+}
+
+func TestFetchActiveGenerationReservationsPaginated_RetriesTransientFailures(t *testing.T) {
+	calculator, mockRegistryCaller := setupPagingCalculator(t, &Config{})
+	callOpts := &bind.CallOpts{Context: context.Background(), BlockNumber: big.NewInt(12345)}
+	opsets := createTestOperatorSets(25)
+
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).Return(big.NewInt(25), nil)
+	mockRegistryCaller.On("GetActiveGenerationReservationsByRange", callOpts, big.NewInt(0), big.NewInt(25)).
+		Return([]ICrossChainRegistry.OperatorSet(nil), rpc.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"}).Twice()
+	expectRange(mockRegistryCaller, callOpts, opsets, 0, 25)
+
+	result, err := calculator.fetchActiveGenerationReservationsPaginated(callOpts)
+	require.NoError(t, err)
+	assert.Equal(t, opsets, result)
+	mockRegistryCaller.AssertNumberOfCalls(t, "GetActiveGenerationReservationsByRange", 3)
+}
+
+func TestFetchActiveGenerationReservationsPaginated_RetriesExhausted(t *testing.T) {
+	calculator, mockRegistryCaller := setupPagingCalculator(t, &Config{PageRetries: 1})
+	callOpts := &bind.CallOpts{Context: context.Background(), BlockNumber: big.NewInt(12345)}
+
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).Return(big.NewInt(25), nil)
+	mockRegistryCaller.On("GetActiveGenerationReservationsByRange", callOpts, big.NewInt(0), big.NewInt(25)).
+		Return([]ICrossChainRegistry.OperatorSet(nil), errors.New("connection reset by peer"))
+
+	_, err := calculator.fetchActiveGenerationReservationsPaginated(callOpts)
+	assert.ErrorContains(t, err, "failed to fetch active generation reservations for range [0, 25): connection reset by peer")
+	mockRegistryCaller.AssertNumberOfCalls(t, "GetActiveGenerationReservationsByRange", 2)
+}
+
+func TestFetchActiveGenerationReservationsPaginated_CountChanged(t *testing.T) {
+	calculator, mockRegistryCaller := setupPagingCalculator(t, &Config{})
+	callOpts := &bind.CallOpts{Context: context.Background(), BlockNumber: big.NewInt(12345)}
+	opsets := createTestOperatorSets(25)
+
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).Return(big.NewInt(25), nil).Once()
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).Return(big.NewInt(26), nil).Once()
+	expectRange(mockRegistryCaller, callOpts, opsets, 0, 25)
+
+	_, err := calculator.fetchActiveGenerationReservationsPaginated(callOpts)
+	assert.ErrorContains(t, err, "generation reservation count changed from 25 to 26")
+}
+
+func TestFetchActiveGenerationReservationsPaginated_ShortPage(t *testing.T) {
+	calculator, mockRegistryCaller := setupPagingCalculator(t, &Config{})
+	callOpts := &bind.CallOpts{Context: context.Background(), BlockNumber: big.NewInt(12345)}
+	opsets := createTestOperatorSets(25)
+
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).Return(big.NewInt(25), nil)
+	mockRegistryCaller.On("GetActiveGenerationReservationsByRange", callOpts, big.NewInt(0), big.NewInt(25)).
+		Return(opsets[:20], nil)
+
+	_, err := calculator.fetchActiveGenerationReservationsPaginated(callOpts)
+	assert.ErrorContains(t, err, "fetched 20 active generation reservations, expected 25")
+}
+
+func TestClassifyPageError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want pageErrorClass
+	}{
+		{err: errors.New("execution reverted"), want: pageErrorFatal},
+		{err: errors.New("response size should not greater than 10000000 bytes"), want: pageErrorTooLarge},
+		{err: errors.New("query returned more than 10000 results"), want: pageErrorTooLarge},
+		{err: errors.New("gas required exceeds allowance (30000000)"), want: pageErrorTooLarge},
+		{err: errors.New("daily request limit exceeded"), want: pageErrorFatal},
+		{err: context.DeadlineExceeded, want: pageErrorTooLarge},
+		{err: errors.New("rate limit exceeded"), want: pageErrorTransient},
+		{err: errors.New("read tcp: connection reset by peer"), want: pageErrorTransient},
+		{err: rpc.HTTPError{StatusCode: 413}, want: pageErrorTooLarge},
+		{err: rpc.HTTPError{StatusCode: 504}, want: pageErrorTooLarge},
+		{err: rpc.HTTPError{StatusCode: 503}, want: pageErrorTransient},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, classifyPageError(tt.err), tt.err.Error())
+	}
+}
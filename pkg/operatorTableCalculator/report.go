@@ -0,0 +1,173 @@
+package operatorTableCalculator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// CalculationReport summarizes a stake table calculation: which reservations became leaves
+// of the tree, which were skipped and why, and the block the calculation read from.
+type CalculationReport struct {
+	// BlockNumber is the reference block number
+	BlockNumber uint64 `json:"blockNumber"`
+	// BlockHash is the reference block hash, or zero if the calculation was pinned by number
+	BlockHash common.Hash `json:"blockHash"`
+	// Root is the calculated global table root
+	Root common.Hash `json:"root"`
+	// TotalReservations is the number of active generation reservations at the reference block
+	TotalReservations int `json:"totalReservations"`
+	// Included are the operator sets in the tree, in leaf order
+	Included []IncludedOperatorSet `json:"included"`
+	// Skipped are the operator sets whose table could not be calculated, in reservation order
+	Skipped []SkippedOperatorSet `json:"skipped"`
+	// CrossChecks compare each calculated table with the local calculation, in reservation
+	// order. Set only when a LocalTableComputer is configured.
+	CrossChecks []CrossCheckResult `json:"crossChecks,omitempty"`
+	// Elapsed is how long the calculation took
+	Elapsed Duration `json:"elapsed"`
+}
+
+// IncludedOperatorSet is an operator set that became a leaf of the tree.
+type IncludedOperatorSet struct {
+	Avs common.Address `json:"avs"`
+	Id  uint32         `json:"id"`
+	// LeafIndex is the operator set's index in the tree
+	LeafIndex uint64 `json:"leafIndex"`
+	// TableBytesHash is the keccak256 hash of the operator table bytes
+	TableBytesHash common.Hash `json:"tableBytesHash"`
+	// CalculatedLocally is set when CalculateOperatorTableBytes ran out of gas and the table
+	// was calculated off-chain by Config.LocalFallback
+	CalculatedLocally bool `json:"calculatedLocally,omitempty"`
+}
+
+// SkippedOperatorSet is an operator set that was left out of the tree.
+type SkippedOperatorSet struct {
+	Avs common.Address `json:"avs"`
+	Id  uint32         `json:"id"`
+	// Reason is the decoded revert reason or error message
+	Reason string `json:"reason"`
+}
+
+// Duration is a time.Duration that is encoded in JSON as a string such as "1.5s".
+type Duration time.Duration
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func newCalculationReport(blockNumber uint64, blockHash common.Hash) *CalculationReport {
+	return &CalculationReport{
+		BlockNumber: blockNumber,
+		BlockHash:   blockHash,
+		Included:    []IncludedOperatorSet{},
+		Skipped:     []SkippedOperatorSet{},
+	}
+}
+
+// The recording methods below do nothing on a nil report, so calculations that return no
+// report skip the work of building one.
+
+func (r *CalculationReport) setTotalReservations(n int) {
+	if r != nil {
+		r.TotalReservations = n
+	}
+}
+
+func (r *CalculationReport) setCrossChecks(checks []*CrossCheckResult) {
+	if r == nil {
+		return
+	}
+	r.CrossChecks = []CrossCheckResult{}
+	for _, check := range checks {
+		if check != nil {
+			r.CrossChecks = append(r.CrossChecks, *check)
+		}
+	}
+}
+
+func (r *CalculationReport) include(opset distribution.OperatorSet, leafIndex uint64, tableBytes []byte, local bool) {
+	if r == nil {
+		return
+	}
+	r.Included = append(r.Included, IncludedOperatorSet{
+		Avs:               opset.Avs,
+		Id:                opset.Id,
+		LeafIndex:         leafIndex,
+		TableBytesHash:    crypto.Keccak256Hash(tableBytes),
+		CalculatedLocally: local,
+	})
+}
+
+func (r *CalculationReport) skip(opset distribution.OperatorSet, reason func() string) {
+	if r == nil {
+		return
+	}
+	r.Skipped = append(r.Skipped, SkippedOperatorSet{Avs: opset.Avs, Id: opset.Id, Reason: reason()})
+}
+
+func (r *CalculationReport) setRoot(root [32]byte) {
+	if r != nil {
+		r.Root = root
+	}
+}
+
+// RevertReason extracts a human-readable reason from a failed contract call. Error(string)
+// reverts are decoded to their message, and CrossChainRegistry custom errors to their name.
+// Anything else falls back to the error's message.
+//
+// Parameters:
+//   - err: The error returned by the contract call
+//
+// Returns:
+//   - string: The revert reason
+func RevertReason(err error) string {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return err.Error()
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return err.Error()
+	}
+	data, decodeErr := hexutil.Decode(hexData)
+	if decodeErr != nil || len(data) < 4 {
+		return err.Error()
+	}
+	if reason, unpackErr := abi.UnpackRevert(data); unpackErr == nil {
+		return reason
+	}
+	if registryAbi, abiErr := ICrossChainRegistry.ICrossChainRegistryMetaData.GetAbi(); abiErr == nil {
+		for name, abiError := range registryAbi.Errors {
+			if bytes.Equal(abiError.ID[:4], data[:4]) {
+				return name
+			}
+		}
+	}
+	return fmt.Sprintf("%s (revert data %s)", err.Error(), hexData)
+}
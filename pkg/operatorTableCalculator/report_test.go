@@ -0,0 +1,150 @@
+package operatorTableCalculator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// revertError mimics the JSON-RPC error returned for a reverted eth_call.
+type revertError struct {
+	data string
+}
+
+func (e *revertError) Error() string          { return "execution reverted" }
+func (e *revertError) ErrorCode() int         { return 3 }
+func (e *revertError) ErrorData() interface{} { return e.data }
+
+func encodeErrorString(t *testing.T, reason string) string {
+	packed, err := abi.Arguments{{Type: abi.Type{T: abi.StringTy}}}.Pack(reason)
+	require.NoError(t, err)
+	return hexutil.Encode(append(crypto.Keccak256([]byte("Error(string)"))[:4], packed...))
+}
+
+func TestCalculateStakeTableRootWithReport(t *testing.T) {
+	calculator, mockRegistryCaller, mockEthClient := setupTestCalculator(t)
+
+	header := &types.Header{Number: big.NewInt(12345), Time: 1700000000}
+	snap := snapshot.New(header)
+	callOpts := &bind.CallOpts{
+		Context:   context.Background(),
+		BlockHash: header.Hash(),
+	}
+
+	opsets := createTestOperatorSets(4)
+	mockRegistryCaller.On("GetActiveGenerationReservationCount", callOpts).
+		Return(big.NewInt(4), nil)
+	mockRegistryCaller.On("GetActiveGenerationReservationsByRange", callOpts, big.NewInt(0), big.NewInt(4)).
+		Return(opsets, nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[0]).
+		Return([]byte{0x01}, nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[1]).
+		Return([]byte(nil), &revertError{data: encodeErrorString(t, "operator set has no operators")})
+	mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[2]).
+		Return([]byte{0x03}, nil)
+	mockRegistryCaller.On("CalculateOperatorTableBytes", callOpts, opsets[3]).
+		Return([]byte(nil), errors.New("connection reset"))
+	mockEthClient.On("HeaderByNumber", mock.Anything, big.NewInt(12345)).
+		Return(header, nil)
+
+	root, _, _, report, err := calculator.CalculateStakeTableRootWithReport(context.Background(), snap)
+	require.NoError(t, err)
+	require.NotNil(t, report)
+
+	assert.Equal(t, uint64(12345), report.BlockNumber)
+	assert.Equal(t, header.Hash(), report.BlockHash)
+	assert.Equal(t, common.Hash(root), report.Root)
+	assert.Equal(t, 4, report.TotalReservations)
+	assert.Greater(t, time.Duration(report.Elapsed), time.Duration(0))
+
+	assert.Equal(t, []IncludedOperatorSet{
+		{Avs: opsets[0].Avs, Id: opsets[0].Id, LeafIndex: 0, TableBytesHash: crypto.Keccak256Hash([]byte{0x01})},
+		{Avs: opsets[2].Avs, Id: opsets[2].Id, LeafIndex: 1, TableBytesHash: crypto.Keccak256Hash([]byte{0x03})},
+	}, report.Included)
+	assert.Equal(t, []SkippedOperatorSet{
+		{Avs: opsets[1].Avs, Id: opsets[1].Id, Reason: "operator set has no operators"},
+		{Avs: opsets[3].Avs, Id: opsets[3].Id, Reason: "connection reset"},
+	}, report.Skipped)
+
+	encoded, err := json.Marshal(report)
+	require.NoError(t, err)
+	var decoded CalculationReport
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, *report, decoded)
+}
+
+func TestCalculationReport_Nil(t *testing.T) {
+	var report *CalculationReport
+	opset := distribution.OperatorSet{Id: 1}
+	assert.NotPanics(t, func() {
+		report.setTotalReservations(1)
+		report.setCrossChecks([]*CrossCheckResult{nil})
+		report.include(opset, 0, []byte{0x01}, false)
+		report.skip(opset, func() string {
+			t.Fatal("the reason of a nil report is not evaluated")
+			return ""
+		})
+		report.setRoot([32]byte{1})
+	})
+}
+
+func TestRevertReason(t *testing.T) {
+	registryAbi, err := ICrossChainRegistry.ICrossChainRegistryMetaData.GetAbi()
+	require.NoError(t, err)
+	var customName string
+	var customErr abi.Error
+	for name, e := range registryAbi.Errors {
+		customName, customErr = name, e
+		break
+	}
+	require.NotEmpty(t, customName)
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "plain error",
+			err:  errors.New("timeout"),
+			want: "timeout",
+		},
+		{
+			name: "error string",
+			err:  &revertError{data: encodeErrorString(t, "bad opset")},
+			want: "bad opset",
+		},
+		{
+			name: "registry custom error",
+			err:  &revertError{data: hexutil.Encode(customErr.ID[:4])},
+			want: customName,
+		},
+		{
+			name: "unknown custom error",
+			err:  &revertError{data: "0xdeadbeef"},
+			want: "execution reverted (revert data 0xdeadbeef)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RevertReason(tt.err))
+		})
+	}
+}
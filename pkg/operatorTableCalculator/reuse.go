@@ -0,0 +1,124 @@
+package operatorTableCalculator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/multichain-go/pkg/logger"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"go.uber.org/zap"
+)
+
+// tableReuse carries the tables included at the previous block of a backfill run to the next
+// block, so that operator sets the change source reports as unchanged are not calculated again.
+// A nil tableReuse calculates every table.
+type tableReuse struct {
+	source OperatorSetChangeSource
+	// snap is the block the kept tables were calculated at, nil before the first block
+	snap   *snapshot.Snapshot
+	tables map[ICrossChainRegistry.OperatorSet]reusableTable
+	// next collects the tables included at the block being calculated
+	next   map[ICrossChainRegistry.OperatorSet]reusableTable
+	reused int
+}
+
+// reusableTable is an operator table included in the tree at a previous block.
+type reusableTable struct {
+	tableBytes []byte
+	leaf       []byte
+	local      bool
+}
+
+// start prepares for the calculation of the next block.
+func (r *tableReuse) start() {
+	if r == nil {
+		return
+	}
+	r.next = make(map[ICrossChainRegistry.OperatorSet]reusableTable)
+	r.reused = 0
+}
+
+// keep records a table included in the tree at the block being calculated.
+func (r *tableReuse) keep(opset ICrossChainRegistry.OperatorSet, tableBytes []byte, leaf []byte, local bool) {
+	if r == nil {
+		return
+	}
+	r.next[opset] = reusableTable{tableBytes: tableBytes, leaf: leaf, local: local}
+}
+
+// finish makes the tables kept at snap available to the next block.
+func (r *tableReuse) finish(snap *snapshot.Snapshot) {
+	if r == nil {
+		return
+	}
+	r.snap = snap
+	r.tables = r.next
+	r.next = nil
+}
+
+// reusedCount returns the number of tables reused at the latest block.
+func (r *tableReuse) reusedCount() int {
+	if r == nil {
+		return 0
+	}
+	return r.reused
+}
+
+// reuseOrCalculateOperatorTables returns the previous block's table for every opset the change
+// source reports as unchanged, and calls CalculateOperatorTableBytes for the rest. Opsets that
+// were skipped at the previous block are always calculated. If the change source fails, every
+// table is calculated.
+func (c *StakeTableCalculator) reuseOrCalculateOperatorTables(
+	ctx context.Context,
+	pin *bind.CallOpts,
+	snap *snapshot.Snapshot,
+	opsets []ICrossChainRegistry.OperatorSet,
+	reuse *tableReuse,
+) []operatorTableResult {
+	if reuse == nil || reuse.snap == nil || snap == nil {
+		return c.calculateOperatorTables(ctx, pin, opsets)
+	}
+	l := logger.WithTraceContext(ctx, c.logger)
+
+	changed, err := reuse.source.ChangedOperatorSets(ctx, reuse.snap, snap, opsets)
+	if err == nil && len(changed) != len(opsets) {
+		err = fmt.Errorf("change source returned %d results for %d operator sets", len(changed), len(opsets))
+	}
+	if err != nil {
+		l.Sugar().Warnw("Failed to find changed operator sets, calculating every table",
+			zap.Uint64("fromBlockNumber", reuse.snap.Number),
+			zap.Uint64("toBlockNumber", snap.Number),
+			zap.Error(err),
+		)
+		return c.calculateOperatorTables(ctx, pin, opsets)
+	}
+
+	results := make([]operatorTableResult, len(opsets))
+	var stale []ICrossChainRegistry.OperatorSet
+	var staleIndices []int
+	for i, opset := range opsets {
+		if table, ok := reuse.tables[opset]; ok && !changed[i] {
+			results[i] = operatorTableResult{tableBytes: table.tableBytes, leaf: table.leaf, local: table.local, reused: true}
+			reuse.reused++
+			continue
+		}
+		stale = append(stale, opset)
+		staleIndices = append(staleIndices, i)
+	}
+
+	l.Sugar().Infow("Reusing operator tables of unchanged operator sets",
+		zap.Uint64("previousBlockNumber", reuse.snap.Number),
+		zap.Int("reused", reuse.reused),
+		zap.Int("calculated", len(stale)),
+	)
+
+	if len(stale) == 0 {
+		return results
+	}
+	for j, result := range c.calculateOperatorTables(ctx, pin, stale) {
+		results[staleIndices[j]] = result
+	}
+	return results
+}
@@ -0,0 +1,323 @@
+// Package policy provides a declarative allow/deny policy that decides which destination
+// chains and operator sets the transporter pushes tables to. Policies can be loaded from
+// YAML files or built in Go, and CLI filter flags compile down to the same rules.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// Effect is the outcome a rule applies when it matches.
+type Effect string
+
+const (
+	// EffectAllow permits the transport
+	EffectAllow Effect = "allow"
+	// EffectDeny prevents the transport
+	EffectDeny Effect = "deny"
+)
+
+// Destination identifies a destination chain and the OperatorTableUpdater deployed on it.
+type Destination struct {
+	// ChainId is the destination chain ID
+	ChainId uint64
+	// OperatorTableUpdater is the address of the destination's OperatorTableUpdater
+	OperatorTableUpdater common.Address
+}
+
+// TableChange is the outcome of comparing an operator table against a destination's copy.
+type TableChange struct {
+	// Changed reports whether the table should be transported
+	Changed bool
+	// Reason explains the outcome
+	Reason string
+}
+
+// ChangeDetector reports whether an operator table differs from what a destination chain last
+// received, or needs to be refreshed before it goes stale, and records tables once they have
+// been transported. It is consulted for rules that set OnlyIfChanged.
+type ChangeDetector interface {
+	DetectTableChange(ctx context.Context, dest Destination, opset distribution.OperatorSet, tableBytes []byte) (TableChange, error)
+	RecordTableTransported(ctx context.Context, dest Destination, opset distribution.OperatorSet, tableBytes []byte, referenceTimestamp uint32) error
+}
+
+// Rule matches transports by destination and operator set, and applies an effect to them.
+// A rule matches when every selector it sets matches; unset selectors match anything.
+// Operator set selectors (Avs, NotAvs, OperatorSetIds, CurveTypes) never match a subject
+// without an operator set; the global table root is evaluated through its operator sets with
+// EvaluateRoot.
+type Rule struct {
+	// Name identifies the rule in logs and decisions
+	Name string `yaml:"name"`
+	// Effect is applied when the rule matches
+	Effect Effect `yaml:"effect"`
+
+	// ChainIds matches destination chains in the list
+	ChainIds []uint64 `yaml:"chainIds,omitempty"`
+	// NotChainIds matches destination chains not in the list
+	NotChainIds []uint64 `yaml:"notChainIds,omitempty"`
+	// Avs matches operator sets belonging to an AVS in the list
+	Avs []common.Address `yaml:"avs,omitempty"`
+	// NotAvs matches operator sets belonging to an AVS not in the list
+	NotAvs []common.Address `yaml:"notAvs,omitempty"`
+	// OperatorSetIds matches operator sets whose ID is in the list
+	OperatorSetIds []uint32 `yaml:"operatorSetIds,omitempty"`
+	// CurveTypes matches operator sets whose table uses a curve type in the list
+	CurveTypes []operatorTable.CurveType `yaml:"curveTypes,omitempty"`
+
+	// OnlyIfChanged, on an allow rule, transports only when the table differs from the destination's
+	OnlyIfChanged bool `yaml:"onlyIfChanged,omitempty"`
+	// MaxGasPriceWei, on an allow rule, skips the transport when the fee cap would exceed this value
+	MaxGasPriceWei uint64 `yaml:"maxGasPriceWei,omitempty"`
+}
+
+// Policy is an ordered list of rules. The first matching rule decides; if none match, Default applies.
+type Policy struct {
+	// Default is the effect applied when no rule matches. Defaults to allow.
+	Default Effect `yaml:"default,omitempty"`
+	// Rules are evaluated in order
+	Rules []Rule `yaml:"rules"`
+}
+
+// Subject describes a single transport that a policy is evaluated against.
+type Subject struct {
+	// ChainId is the destination chain ID
+	ChainId uint64
+	// OperatorSet is the operator set being transported, or nil for the global table root
+	OperatorSet *distribution.OperatorSet
+	// CurveType is the curve type of the operator set's table
+	CurveType operatorTable.CurveType
+}
+
+// Decision is the result of evaluating a policy against a subject.
+type Decision struct {
+	// Allowed reports whether the transport may proceed
+	Allowed bool
+	// Rule is the name of the matching rule, or empty if the default applied
+	Rule string
+	// OnlyIfChanged requires the table to differ from the destination's before transporting
+	OnlyIfChanged bool
+	// MaxGasPrice caps the fee per gas for the transport, or nil for no cap
+	MaxGasPrice *big.Int
+}
+
+// Load reads and parses a YAML policy file.
+//
+// Parameters:
+//   - path: Path to the YAML policy file
+//
+// Returns:
+//   - *Policy: The parsed and validated policy
+//   - error: An error if the file cannot be read or is invalid
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file %s: %w", path, err)
+	}
+	return Parse(data)
+}
+
+// Parse parses a YAML policy document.
+//
+// Parameters:
+//   - data: The YAML document
+//
+// Returns:
+//   - *Policy: The parsed and validated policy
+//   - error: An error if the document is malformed or invalid
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Validate checks that the policy's effects and curve types are recognized.
+//
+// Returns:
+//   - error: An error describing the first invalid rule, or nil
+func (p *Policy) Validate() error {
+	if p.Default != "" && p.Default != EffectAllow && p.Default != EffectDeny {
+		return fmt.Errorf("invalid default effect %q", p.Default)
+	}
+	for i, r := range p.Rules {
+		if r.Effect != EffectAllow && r.Effect != EffectDeny {
+			return fmt.Errorf("rule %d (%s): invalid effect %q", i, r.Name, r.Effect)
+		}
+		for _, ct := range r.CurveTypes {
+			if ct != operatorTable.CurveTypeECDSA && ct != operatorTable.CurveTypeBN254 {
+				return fmt.Errorf("rule %d (%s): invalid curve type %q", i, r.Name, ct)
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the decision for a transport. A nil policy allows everything.
+//
+// Parameters:
+//   - s: The transport being evaluated
+//
+// Returns:
+//   - Decision: Whether the transport is allowed and any overrides that apply
+func (p *Policy) Evaluate(s Subject) Decision {
+	if p == nil {
+		return Decision{Allowed: true}
+	}
+	for _, r := range p.Rules {
+		if !r.matches(s) {
+			continue
+		}
+		if r.Effect == EffectDeny {
+			return Decision{Allowed: false, Rule: r.Name}
+		}
+		d := Decision{Allowed: true, Rule: r.Name, OnlyIfChanged: r.OnlyIfChanged}
+		if r.MaxGasPriceWei != 0 {
+			d.MaxGasPrice = new(big.Int).SetUint64(r.MaxGasPriceWei)
+		}
+		return d
+	}
+	return Decision{Allowed: p.Default != EffectDeny}
+}
+
+// EvaluateRoot returns the decision for confirming the global table root on a chain. The root
+// is needed on a chain when any operator set is transported there, so it is allowed when any
+// of the operator sets is allowed on the chain, and its fee is capped by the lowest gas price
+// cap of those operator sets. Without operator sets, only the chain is evaluated.
+//
+// Parameters:
+//   - chainId: The destination chain ID
+//   - opsets: The operator sets in the root; their ChainId is ignored
+//
+// Returns:
+//   - Decision: Whether the root may be confirmed on the chain, and its gas price cap
+func (p *Policy) EvaluateRoot(chainId uint64, opsets []Subject) Decision {
+	if len(opsets) == 0 {
+		return p.Evaluate(Subject{ChainId: chainId})
+	}
+	root := Decision{Allowed: false}
+	for _, s := range opsets {
+		s.ChainId = chainId
+		d := p.Evaluate(s)
+		if !d.Allowed {
+			if !root.Allowed && root.Rule == "" {
+				root.Rule = d.Rule
+			}
+			continue
+		}
+		if !root.Allowed {
+			root = Decision{Allowed: true, Rule: d.Rule}
+		}
+		if d.MaxGasPrice != nil && (root.MaxGasPrice == nil || d.MaxGasPrice.Cmp(root.MaxGasPrice) < 0) {
+			root.MaxGasPrice = d.MaxGasPrice
+		}
+	}
+	return root
+}
+
+func (r *Rule) matches(s Subject) bool {
+	if len(r.ChainIds) > 0 && !slices.Contains(r.ChainIds, s.ChainId) {
+		return false
+	}
+	if len(r.NotChainIds) > 0 && slices.Contains(r.NotChainIds, s.ChainId) {
+		return false
+	}
+	if !r.hasOperatorSetSelectors() {
+		return true
+	}
+	if s.OperatorSet == nil {
+		return false
+	}
+	if len(r.Avs) > 0 && !slices.Contains(r.Avs, s.OperatorSet.Avs) {
+		return false
+	}
+	if len(r.NotAvs) > 0 && slices.Contains(r.NotAvs, s.OperatorSet.Avs) {
+		return false
+	}
+	if len(r.OperatorSetIds) > 0 && !slices.Contains(r.OperatorSetIds, s.OperatorSet.Id) {
+		return false
+	}
+	if len(r.CurveTypes) > 0 && !slices.Contains(r.CurveTypes, s.CurveType) {
+		return false
+	}
+	return true
+}
+
+func (r *Rule) hasOperatorSetSelectors() bool {
+	return len(r.Avs) > 0 || len(r.NotAvs) > 0 || len(r.OperatorSetIds) > 0 || len(r.CurveTypes) > 0
+}
+
+// Filters holds the simple transport filters exposed as CLI flags.
+type Filters struct {
+	// OnlyChainIds restricts transport to these destination chains
+	OnlyChainIds []uint64
+	// OnlyAvs restricts operator table transport to these AVSs
+	OnlyAvs []common.Address
+	// ExcludeOperatorSets are never transported
+	ExcludeOperatorSets []distribution.OperatorSet
+}
+
+// Rules compiles the filters into deny rules. The rules are intended to be placed ahead of
+// any rules loaded from a policy file so the filters always take precedence.
+//
+// Returns:
+//   - []Rule: The compiled rules, empty if no filters are set
+func (f *Filters) Rules() []Rule {
+	var rules []Rule
+	for _, opset := range f.ExcludeOperatorSets {
+		rules = append(rules, Rule{
+			Name:           fmt.Sprintf("exclude-opset-%s-%d", opset.Avs.Hex(), opset.Id),
+			Effect:         EffectDeny,
+			Avs:            []common.Address{opset.Avs},
+			OperatorSetIds: []uint32{opset.Id},
+		})
+	}
+	if len(f.OnlyChainIds) > 0 {
+		rules = append(rules, Rule{
+			Name:        "only-chain",
+			Effect:      EffectDeny,
+			NotChainIds: f.OnlyChainIds,
+		})
+	}
+	if len(f.OnlyAvs) > 0 {
+		rules = append(rules, Rule{
+			Name:   "only-avs",
+			Effect: EffectDeny,
+			NotAvs: f.OnlyAvs,
+		})
+	}
+	return rules
+}
+
+// WithFilters returns a copy of the policy with the filter rules evaluated first.
+// A nil policy is treated as an empty, allow-by-default policy.
+//
+// Parameters:
+//   - f: The filters to prepend
+//
+// Returns:
+//   - *Policy: The combined policy
+func (p *Policy) WithFilters(f *Filters) *Policy {
+	combined := &Policy{Default: EffectAllow}
+	if p != nil {
+		combined.Default = p.Default
+	}
+	combined.Rules = append(combined.Rules, f.Rules()...)
+	if p != nil {
+		combined.Rules = append(combined.Rules, p.Rules...)
+	}
+	return combined
+}
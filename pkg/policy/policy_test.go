@@ -0,0 +1,186 @@
+package policy
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	avsA = common.HexToAddress("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	avsB = common.HexToAddress("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+)
+
+func opsetSubject(chainId uint64, avs common.Address, id uint32, curve operatorTable.CurveType) Subject {
+	return Subject{
+		ChainId:     chainId,
+		OperatorSet: &distribution.OperatorSet{Id: id, Avs: avs},
+		CurveType:   curve,
+	}
+}
+
+func TestPolicy_NilAllowsEverything(t *testing.T) {
+	var p *Policy
+	assert.True(t, p.Evaluate(Subject{ChainId: 1}).Allowed)
+	assert.True(t, p.Evaluate(opsetSubject(1, avsA, 1, operatorTable.CurveTypeBN254)).Allowed)
+}
+
+func TestPolicy_ParseAndEvaluate(t *testing.T) {
+	doc := `
+default: deny
+rules:
+  - name: no-ecdsa-on-base
+    effect: deny
+    chainIds: [8453]
+    curveTypes: [ecdsa]
+  - name: base
+    effect: allow
+    chainIds: [8453]
+    onlyIfChanged: true
+    maxGasPriceWei: 5000000000
+  - name: avs-a-everywhere
+    effect: allow
+    avs: ["0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"]
+`
+	p, err := Parse([]byte(doc))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		subject Subject
+		want    Decision
+	}{
+		{
+			name:    "global root on base uses chain rule",
+			subject: Subject{ChainId: 8453},
+			want:    Decision{Allowed: true, Rule: "base", OnlyIfChanged: true, MaxGasPrice: big.NewInt(5000000000)},
+		},
+		{
+			name:    "ecdsa opset on base is denied",
+			subject: opsetSubject(8453, avsB, 1, operatorTable.CurveTypeECDSA),
+			want:    Decision{Allowed: false, Rule: "no-ecdsa-on-base"},
+		},
+		{
+			name:    "bn254 opset on base is allowed with overrides",
+			subject: opsetSubject(8453, avsB, 1, operatorTable.CurveTypeBN254),
+			want:    Decision{Allowed: true, Rule: "base", OnlyIfChanged: true, MaxGasPrice: big.NewInt(5000000000)},
+		},
+		{
+			name:    "avs A on another chain is allowed",
+			subject: opsetSubject(10, avsA, 7, operatorTable.CurveTypeBN254),
+			want:    Decision{Allowed: true, Rule: "avs-a-everywhere"},
+		},
+		{
+			name:    "global root on another chain falls through to default",
+			subject: Subject{ChainId: 10},
+			want:    Decision{Allowed: false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, p.Evaluate(tt.subject))
+		})
+	}
+}
+
+func TestPolicy_ParseRejectsInvalidRules(t *testing.T) {
+	_, err := Parse([]byte("rules:\n  - name: bad\n    effect: maybe\n"))
+	assert.ErrorContains(t, err, "invalid effect")
+
+	_, err = Parse([]byte("rules:\n  - name: bad\n    effect: deny\n    curveTypes: [secp]\n"))
+	assert.ErrorContains(t, err, "invalid curve type")
+
+	_, err = Parse([]byte("default: sometimes\n"))
+	assert.ErrorContains(t, err, "invalid default effect")
+}
+
+func TestPolicy_Load(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("rules:\n  - name: deny-all-avs-b\n    effect: deny\n    avs: [\"0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb\"]\n"), 0o600))
+
+	p, err := Load(path)
+	require.NoError(t, err)
+	assert.False(t, p.Evaluate(opsetSubject(1, avsB, 1, operatorTable.CurveTypeBN254)).Allowed)
+	assert.True(t, p.Evaluate(opsetSubject(1, avsA, 1, operatorTable.CurveTypeBN254)).Allowed)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestFilters_CompileToPolicy(t *testing.T) {
+	filters := &Filters{
+		OnlyChainIds:        []uint64{1, 8453},
+		OnlyAvs:             []common.Address{avsA},
+		ExcludeOperatorSets: []distribution.OperatorSet{{Id: 2, Avs: avsA}},
+	}
+	fileRules := &Policy{Rules: []Rule{{Name: "file-allow", Effect: EffectAllow, MaxGasPriceWei: 1}}}
+	p := fileRules.WithFilters(filters)
+
+	// Filters take precedence over the file rules
+	assert.Equal(t, "exclude-opset-"+avsA.Hex()+"-2", p.Rules[0].Name)
+	assert.Equal(t, "file-allow", p.Rules[len(p.Rules)-1].Name)
+
+	assert.True(t, p.Evaluate(Subject{ChainId: 1}).Allowed, "global root on an allowed chain")
+	assert.False(t, p.Evaluate(Subject{ChainId: 10}).Allowed, "global root on a chain outside --only-chain")
+	assert.True(t, p.Evaluate(opsetSubject(8453, avsA, 1, operatorTable.CurveTypeBN254)).Allowed)
+	assert.False(t, p.Evaluate(opsetSubject(8453, avsA, 2, operatorTable.CurveTypeBN254)).Allowed, "excluded opset")
+	assert.False(t, p.Evaluate(opsetSubject(8453, avsB, 1, operatorTable.CurveTypeBN254)).Allowed, "AVS outside --only-avs")
+
+	var nilPolicy *Policy
+	assert.Len(t, nilPolicy.WithFilters(filters).Rules, 3)
+}
+
+func TestPolicy_ParseCurveTypes(t *testing.T) {
+	p, err := Parse([]byte("rules:\n  - name: curves\n    effect: deny\n    curveTypes: [ecdsa, bn254]\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []operatorTable.CurveType{operatorTable.CurveTypeECDSA, operatorTable.CurveTypeBN254}, p.Rules[0].CurveTypes)
+
+	_, err = Parse([]byte("rules:\n  - name: bad\n    effect: deny\n    curveTypes: [none]\n"))
+	assert.ErrorContains(t, err, "invalid curve type")
+}
+
+func TestPolicy_EvaluateRoot(t *testing.T) {
+	doc := `
+default: deny
+rules:
+  - name: no-ecdsa
+    effect: deny
+    curveTypes: [ecdsa]
+  - name: avs-a
+    effect: allow
+    avs: ["0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"]
+    maxGasPriceWei: 7000000000
+  - name: avs-b-on-base
+    effect: allow
+    chainIds: [8453]
+    avs: ["0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"]
+    maxGasPriceWei: 3000000000
+`
+	p, err := Parse([]byte(doc))
+	require.NoError(t, err)
+	opsets := []Subject{
+		opsetSubject(0, avsB, 1, operatorTable.CurveTypeBN254),
+		opsetSubject(0, avsA, 1, operatorTable.CurveTypeBN254),
+	}
+
+	// An AVS allowlist allows the root wherever one of its operator sets is allowed
+	assert.Equal(t, Decision{Allowed: true, Rule: "avs-a", MaxGasPrice: big.NewInt(7000000000)}, p.EvaluateRoot(1, opsets))
+	// with the lowest cap of the allowed operator sets
+	assert.Equal(t, Decision{Allowed: true, Rule: "avs-b-on-base", MaxGasPrice: big.NewInt(3000000000)}, p.EvaluateRoot(8453, opsets))
+
+	// The root is denied where no operator set is allowed
+	denied := p.EvaluateRoot(1, []Subject{opsetSubject(0, avsA, 1, operatorTable.CurveTypeECDSA), opsetSubject(0, avsB, 1, operatorTable.CurveTypeBN254)})
+	assert.Equal(t, Decision{Allowed: false, Rule: "no-ecdsa"}, denied)
+
+	// Without operator sets, only the chain is evaluated
+	assert.False(t, p.EvaluateRoot(1, nil).Allowed)
+	var nilPolicy *Policy
+	assert.True(t, nilPolicy.EvaluateRoot(1, opsets).Allowed)
+}
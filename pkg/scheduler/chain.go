@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IBaseCertificateVerifier"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IOperatorTableUpdater"
+	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/policy"
+	"github.com/Layr-Labs/multichain-go/pkg/transport"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ChainReader reads staleness state from each destination's certificate verifiers and
+// estimates fee caps the same way the transport does. It implements StalenessReader and GasOracle.
+type ChainReader struct {
+	chainManager chainManager.IChainManager
+}
+
+// NewChainReader creates a ChainReader.
+//
+// Parameters:
+//   - cm: The chain manager holding a client for every destination chain
+//
+// Returns:
+//   - *ChainReader: The reader
+func NewChainReader(cm chainManager.IChainManager) *ChainReader {
+	return &ChainReader{chainManager: cm}
+}
+
+// ReadStaleness implements StalenessReader.
+func (r *ChainReader) ReadStaleness(
+	ctx context.Context,
+	dest policy.Destination,
+	opset distribution.OperatorSet,
+	curveType operatorTable.CurveType,
+) (Staleness, error) {
+	chain, err := r.chainManager.GetChainForId(dest.ChainId)
+	if err != nil {
+		return Staleness{}, fmt.Errorf("failed to get chain for ID %d: %w", dest.ChainId, err)
+	}
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	verifierAddress, err := r.certificateVerifier(callOpts, chain, dest.OperatorTableUpdater, curveType)
+	if err != nil {
+		return Staleness{}, err
+	}
+	verifier, err := IBaseCertificateVerifier.NewIBaseCertificateVerifierCaller(verifierAddress, chain.RPCClient)
+	if err != nil {
+		return Staleness{}, fmt.Errorf("failed to bind certificate verifier: %w", err)
+	}
+	verifierOpset := IBaseCertificateVerifier.OperatorSet{Avs: opset.Avs, Id: opset.Id}
+
+	latest, err := verifier.LatestReferenceTimestamp(callOpts, verifierOpset)
+	if err != nil {
+		return Staleness{}, fmt.Errorf("failed to read latest reference timestamp: %w", err)
+	}
+	maxStaleness, err := verifier.MaxOperatorTableStaleness(callOpts, verifierOpset)
+	if err != nil {
+		return Staleness{}, fmt.Errorf("failed to read max operator table staleness: %w", err)
+	}
+	return Staleness{LatestReferenceTimestamp: latest, MaxStalenessPeriod: maxStaleness}, nil
+}
+
+func (r *ChainReader) certificateVerifier(
+	callOpts *bind.CallOpts,
+	chain *chainManager.Chain,
+	updaterAddress common.Address,
+	curveType operatorTable.CurveType,
+) (common.Address, error) {
+	if curveType != operatorTable.CurveTypeBN254 && curveType != operatorTable.CurveTypeECDSA {
+		return common.Address{}, fmt.Errorf("unsupported curve type %s", curveType)
+	}
+	updater, err := IOperatorTableUpdater.NewIOperatorTableUpdaterCaller(updaterAddress, chain.RPCClient)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to bind OperatorTableUpdater: %w", err)
+	}
+	verifierAddress, err := updater.GetCertificateVerifier(callOpts, uint8(curveType))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to get %s certificate verifier: %w", curveType, err)
+	}
+	return verifierAddress, nil
+}
+
+// GasFeeCap implements GasOracle. The fee cap is 1.5 times the latest base fee plus the
+// suggested tip, as used by the transport.
+func (r *ChainReader) GasFeeCap(ctx context.Context, chainId uint64) (*big.Int, error) {
+	chain, err := r.chainManager.GetChainForId(chainId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain for ID %d: %w", chainId, err)
+	}
+	gasTipCap, err := chain.RPCClient.SuggestGasTipCap(ctx)
+	if err != nil {
+		gasTipCap = transport.FallbackGasTipCap
+	}
+	header, err := chain.RPCClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest header: %w", err)
+	}
+	if header.BaseFee == nil {
+		return gasTipCap, nil
+	}
+	overestimatedBasefee := new(big.Int).Div(new(big.Int).Mul(header.BaseFee, big.NewInt(3)), big.NewInt(2))
+	return overestimatedBasefee.Add(overestimatedBasefee, gasTipCap), nil
+}
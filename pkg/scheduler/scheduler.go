@@ -0,0 +1,300 @@
+// Package scheduler plans operator table transports around the destination chains' staleness
+// deadlines. A table transported with reference timestamp T to an operator set whose max
+// staleness period is P can no longer be used to verify certificates after T+P. The scheduler
+// reads T and P for every (chain, operator set), orders the transports by deadline, and raises
+// alerts for deadlines that will be missed given the chain's gas price and transport throughput.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/policy"
+)
+
+// DefaultTransportDuration is the default time one UpdateOperatorTable transport is expected
+// to take, from submission to inclusion.
+const DefaultTransportDuration = 30 * time.Second
+
+// Staleness is a destination chain's staleness state for an operator set.
+type Staleness struct {
+	// LatestReferenceTimestamp is the reference timestamp of the destination's latest table,
+	// or 0 if the destination has never received one
+	LatestReferenceTimestamp uint32
+	// MaxStalenessPeriod is the destination's max staleness period in seconds; 0 means tables never go stale
+	MaxStalenessPeriod uint32
+}
+
+// StalenessReader reads a destination chain's staleness state for an operator set.
+type StalenessReader interface {
+	ReadStaleness(ctx context.Context, dest policy.Destination, opset distribution.OperatorSet, curveType operatorTable.CurveType) (Staleness, error)
+}
+
+// GasOracle estimates the fee cap a transport to a destination chain would pay right now.
+type GasOracle interface {
+	GasFeeCap(ctx context.Context, chainId uint64) (*big.Int, error)
+}
+
+// Config holds the configuration for a Scheduler.
+type Config struct {
+	// Reader reads each destination's staleness state
+	Reader StalenessReader
+	// GasOracle, when set, is compared against the policy's max gas price for each transport
+	GasOracle GasOracle
+	// Policy, when set, excludes denied transports and supplies max gas prices
+	Policy *policy.Policy
+	// TransportDuration is how long one transport to a chain is expected to take. Transports to
+	// the same chain are assumed to run one after another. Defaults to DefaultTransportDuration.
+	TransportDuration time.Duration
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Task is a single (chain, operator set) transport in a plan.
+type Task struct {
+	// Destination is the destination chain
+	Destination policy.Destination `json:"destination"`
+	// OperatorSet is the operator set to transport
+	OperatorSet distribution.OperatorSet `json:"operatorSet"`
+	// CurveType is the operator set's key type
+	CurveType operatorTable.CurveType `json:"curveType"`
+	// LastUpdated is the reference time of the destination's latest table, or zero if it has none
+	LastUpdated time.Time `json:"lastUpdated"`
+	// MaxStalenessPeriod is the destination's max staleness period in seconds; zero means the
+	// table never goes stale
+	MaxStalenessPeriod uint32 `json:"maxStalenessPeriod"`
+	// Deadline is when the destination's table goes stale. It is zero when the table never goes
+	// stale or the destination has no table yet.
+	Deadline time.Time `json:"deadline"`
+	// MaxGasPrice is the policy's max gas price for the transport, or nil for no cap
+	MaxGasPrice *big.Int `json:"maxGasPrice,omitempty"`
+	// EstimatedCompletion is when the transport is expected to land if transports to the chain
+	// run in plan order starting now
+	EstimatedCompletion time.Time `json:"estimatedCompletion"`
+}
+
+// HasDeadline reports whether the destination's table can go stale.
+func (t *Task) HasDeadline() bool {
+	return !t.Deadline.IsZero()
+}
+
+// Alert warns that a task will miss its deadline.
+type Alert struct {
+	// Task is the task that will miss its deadline
+	Task Task `json:"task"`
+	// Reason explains why the deadline will be missed
+	Reason string `json:"reason"`
+}
+
+// Plan is an ordered list of transports and the deadlines they are expected to miss.
+type Plan struct {
+	// GeneratedAt is the time the plan was computed
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Tasks are ordered by priority: destinations without a table first, then by deadline,
+	// then tables that never go stale
+	Tasks []Task `json:"tasks"`
+	// Alerts are the tasks that will miss their deadline
+	Alerts []Alert `json:"alerts"`
+}
+
+// OperatorSetOrder returns each operator set once, ordered by its most urgent task. Transporting
+// operator sets in this order serves the earliest deadlines first.
+//
+// Returns:
+//   - []distribution.OperatorSet: The operator sets in priority order
+func (p *Plan) OperatorSetOrder() []distribution.OperatorSet {
+	seen := make(map[distribution.OperatorSet]bool)
+	order := make([]distribution.OperatorSet, 0, len(p.Tasks))
+	for _, task := range p.Tasks {
+		if seen[task.OperatorSet] {
+			continue
+		}
+		seen[task.OperatorSet] = true
+		order = append(order, task.OperatorSet)
+	}
+	return order
+}
+
+// Scheduler computes transport plans.
+type Scheduler struct {
+	reader            StalenessReader
+	gasOracle         GasOracle
+	policy            *policy.Policy
+	transportDuration time.Duration
+	now               func() time.Time
+}
+
+// NewScheduler creates a new Scheduler.
+//
+// Parameters:
+//   - cfg: The scheduler configuration
+//
+// Returns:
+//   - *Scheduler: The scheduler
+//   - error: An error if no staleness reader is configured
+func NewScheduler(cfg *Config) (*Scheduler, error) {
+	if cfg.Reader == nil {
+		return nil, fmt.Errorf("scheduler requires a staleness reader")
+	}
+	duration := cfg.TransportDuration
+	if duration <= 0 {
+		duration = DefaultTransportDuration
+	}
+	now := cfg.Now
+	if now == nil {
+		now = time.Now
+	}
+	return &Scheduler{
+		reader:            cfg.Reader,
+		gasOracle:         cfg.GasOracle,
+		policy:            cfg.Policy,
+		transportDuration: duration,
+		now:               now,
+	}, nil
+}
+
+// Plan reads the staleness state of every operator set in the distribution on every destination
+// and orders the transports by deadline. Transports denied by the policy are left out.
+//
+// Parameters:
+//   - ctx: Context for the destination reads
+//   - destinations: The destination chains
+//   - dist: The calculated distribution whose operator sets are to be transported
+//
+// Returns:
+//   - *Plan: The prioritized plan and its alerts
+//   - error: An error if a destination's staleness state cannot be read
+func (s *Scheduler) Plan(
+	ctx context.Context,
+	destinations []policy.Destination,
+	dist *distribution.Distribution,
+) (*Plan, error) {
+	now := s.now()
+	plan := &Plan{
+		GeneratedAt: now,
+		Tasks:       []Task{},
+		Alerts:      []Alert{},
+	}
+
+	for _, opset := range dist.GetOrderedOperatorSets() {
+		tableBytes, _ := dist.GetTableData(opset)
+		table, err := operatorTable.Decode(tableBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode table for operator set %s with ID %d: %w", opset.Avs.String(), opset.Id, err)
+		}
+		for _, dest := range destinations {
+			decision := s.policy.Evaluate(policy.Subject{
+				ChainId:     dest.ChainId,
+				OperatorSet: &opset,
+				CurveType:   table.CurveType,
+			})
+			if !decision.Allowed {
+				continue
+			}
+			staleness, err := s.reader.ReadStaleness(ctx, dest, opset, table.CurveType)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read staleness of operator set %s with ID %d on chain %d: %w",
+					opset.Avs.String(), opset.Id, dest.ChainId, err)
+			}
+			task := newTask(dest, opset, table, staleness)
+			task.MaxGasPrice = decision.MaxGasPrice
+			plan.Tasks = append(plan.Tasks, task)
+		}
+	}
+
+	sort.SliceStable(plan.Tasks, func(i, j int) bool {
+		return taskLess(&plan.Tasks[i], &plan.Tasks[j])
+	})
+
+	feeCaps := make(map[uint64]*big.Int)
+	queued := make(map[uint64]int)
+	for i := range plan.Tasks {
+		task := &plan.Tasks[i]
+		queued[task.Destination.ChainId]++
+		task.EstimatedCompletion = now.Add(time.Duration(queued[task.Destination.ChainId]) * s.transportDuration)
+		if !task.HasDeadline() {
+			continue
+		}
+		reason, err := s.gasBlocked(ctx, task, feeCaps)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			plan.Alerts = append(plan.Alerts, Alert{Task: *task, Reason: reason})
+			continue
+		}
+		if task.EstimatedCompletion.After(task.Deadline) {
+			plan.Alerts = append(plan.Alerts, Alert{Task: *task, Reason: missedDeadlineReason(task, now)})
+		}
+	}
+	return plan, nil
+}
+
+func newTask(dest policy.Destination, opset distribution.OperatorSet, table *operatorTable.OperatorTable, staleness Staleness) Task {
+	task := Task{
+		Destination: dest,
+		OperatorSet: opset,
+		CurveType:   table.CurveType,
+	}
+	maxStaleness := staleness.MaxStalenessPeriod
+	if staleness.LatestReferenceTimestamp == 0 {
+		// The destination has no table yet; it will take the calculated table's config
+		maxStaleness = table.Config.MaxStalenessPeriod
+	}
+	task.MaxStalenessPeriod = maxStaleness
+	if staleness.LatestReferenceTimestamp != 0 {
+		task.LastUpdated = time.Unix(int64(staleness.LatestReferenceTimestamp), 0).UTC()
+		if maxStaleness != 0 {
+			task.Deadline = task.LastUpdated.Add(time.Duration(maxStaleness) * time.Second)
+		}
+	}
+	return task
+}
+
+// taskLess orders destinations without a table first, then tasks by deadline, then tables
+// that never go stale. Ties keep distribution order.
+func taskLess(a, b *Task) bool {
+	aMissing, bMissing := a.LastUpdated.IsZero(), b.LastUpdated.IsZero()
+	if aMissing != bMissing {
+		return aMissing
+	}
+	aStale, bStale := !a.Deadline.IsZero(), !b.Deadline.IsZero()
+	if aStale != bStale {
+		return aStale
+	}
+	return a.Deadline.Before(b.Deadline)
+}
+
+// gasBlocked explains why a task cannot be transported when the chain's current fee cap exceeds
+// the task's max gas price. Fee caps are cached per chain in feeCaps.
+func (s *Scheduler) gasBlocked(ctx context.Context, task *Task, feeCaps map[uint64]*big.Int) (string, error) {
+	if s.gasOracle == nil || task.MaxGasPrice == nil {
+		return "", nil
+	}
+	chainId := task.Destination.ChainId
+	feeCap, ok := feeCaps[chainId]
+	if !ok {
+		var err error
+		if feeCap, err = s.gasOracle.GasFeeCap(ctx, chainId); err != nil {
+			return "", fmt.Errorf("failed to estimate gas price on chain %d: %w", chainId, err)
+		}
+		feeCaps[chainId] = feeCap
+	}
+	if feeCap.Cmp(task.MaxGasPrice) <= 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("gas fee cap %s wei is above the policy maximum of %s wei", feeCap, task.MaxGasPrice), nil
+}
+
+func missedDeadlineReason(task *Task, now time.Time) string {
+	if !task.Deadline.After(now) {
+		return fmt.Sprintf("table went stale at %s", task.Deadline.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("table goes stale at %s but transport is expected to land at %s",
+		task.Deadline.Format(time.RFC3339), task.EstimatedCompletion.Format(time.RFC3339))
+}
@@ -0,0 +1,165 @@
+package scheduler
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/policy"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stalenessKey struct {
+	chainId uint64
+	opset   distribution.OperatorSet
+}
+
+type fakeStalenessReader map[stalenessKey]Staleness
+
+func (f fakeStalenessReader) ReadStaleness(_ context.Context, dest policy.Destination, opset distribution.OperatorSet, _ operatorTable.CurveType) (Staleness, error) {
+	return f[stalenessKey{dest.ChainId, opset}], nil
+}
+
+type fakeGasOracle map[uint64]*big.Int
+
+func (f fakeGasOracle) GasFeeCap(_ context.Context, chainId uint64) (*big.Int, error) {
+	return f[chainId], nil
+}
+
+var (
+	testNow     = time.Unix(1700000000, 0).UTC()
+	mainnet     = policy.Destination{ChainId: 1, OperatorTableUpdater: common.HexToAddress("0x01")}
+	base        = policy.Destination{ChainId: 8453, OperatorTableUpdater: common.HexToAddress("0x02")}
+	testAvs     = common.HexToAddress("0xa1")
+	opsetFresh  = distribution.OperatorSet{Avs: testAvs, Id: 1}
+	opsetUrgent = distribution.OperatorSet{Avs: testAvs, Id: 2}
+	opsetNew    = distribution.OperatorSet{Avs: testAvs, Id: 3}
+	opsetNever  = distribution.OperatorSet{Avs: testAvs, Id: 4}
+)
+
+func newTestDistribution(t *testing.T, opsets ...distribution.OperatorSet) *distribution.Distribution {
+	dist := distribution.NewDistributionWithOperatorSets(opsets)
+	for _, opset := range opsets {
+		tableBytes, err := operatorTable.Encode(&operatorTable.OperatorTable{
+			OperatorSet: operatorTable.OperatorSet{Avs: opset.Avs, Id: opset.Id},
+			CurveType:   operatorTable.CurveTypeECDSA,
+			Config:      operatorTable.OperatorSetConfig{MaxStalenessPeriod: 3600},
+			ECDSA:       []operatorTable.ECDSAOperatorInfo{{Pubkey: common.HexToAddress("0x01"), Weights: []*big.Int{big.NewInt(1)}}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, dist.SetTableData(opset, tableBytes))
+	}
+	return dist
+}
+
+func updatedAgo(d time.Duration) uint32 {
+	return uint32(testNow.Add(-d).Unix())
+}
+
+func TestPlan_OrdersByDeadline(t *testing.T) {
+	reader := fakeStalenessReader{
+		{mainnet.ChainId, opsetFresh}:  {LatestReferenceTimestamp: updatedAgo(10 * time.Minute), MaxStalenessPeriod: 3600},
+		{mainnet.ChainId, opsetUrgent}: {LatestReferenceTimestamp: updatedAgo(55 * time.Minute), MaxStalenessPeriod: 3600},
+		{mainnet.ChainId, opsetNever}:  {LatestReferenceTimestamp: updatedAgo(24 * time.Hour), MaxStalenessPeriod: 0},
+		// opsetNew has no table on mainnet
+	}
+	scheduler, err := NewScheduler(&Config{
+		Reader:            reader,
+		TransportDuration: time.Minute,
+		Now:               func() time.Time { return testNow },
+	})
+	require.NoError(t, err)
+
+	plan, err := scheduler.Plan(context.Background(), []policy.Destination{mainnet},
+		newTestDistribution(t, opsetFresh, opsetUrgent, opsetNew, opsetNever))
+	require.NoError(t, err)
+
+	require.Len(t, plan.Tasks, 4)
+	assert.Equal(t, []distribution.OperatorSet{opsetNew, opsetUrgent, opsetFresh, opsetNever}, plan.OperatorSetOrder())
+
+	newTask := plan.Tasks[0]
+	assert.True(t, newTask.LastUpdated.IsZero())
+	assert.False(t, newTask.HasDeadline())
+	assert.Equal(t, uint32(3600), newTask.MaxStalenessPeriod, "uses the calculated table's config")
+
+	urgent := plan.Tasks[1]
+	assert.Equal(t, testNow.Add(5*time.Minute), urgent.Deadline)
+	assert.Equal(t, testNow.Add(2*time.Minute), urgent.EstimatedCompletion)
+
+	assert.False(t, plan.Tasks[3].HasDeadline())
+	assert.Empty(t, plan.Alerts)
+}
+
+func TestPlan_AlertsOnThroughput(t *testing.T) {
+	reader := fakeStalenessReader{
+		{mainnet.ChainId, opsetFresh}:  {LatestReferenceTimestamp: updatedAgo(58 * time.Minute), MaxStalenessPeriod: 3600},
+		{mainnet.ChainId, opsetUrgent}: {LatestReferenceTimestamp: updatedAgo(59 * time.Minute), MaxStalenessPeriod: 3600},
+		{mainnet.ChainId, opsetNever}:  {LatestReferenceTimestamp: updatedAgo(2 * time.Hour), MaxStalenessPeriod: 3600},
+		{base.ChainId, opsetFresh}:     {LatestReferenceTimestamp: updatedAgo(58 * time.Minute), MaxStalenessPeriod: 3600},
+	}
+	scheduler, err := NewScheduler(&Config{
+		Reader:            reader,
+		TransportDuration: 90 * time.Second,
+		Now:               func() time.Time { return testNow },
+	})
+	require.NoError(t, err)
+
+	dist := newTestDistribution(t, opsetFresh, opsetUrgent, opsetNever)
+	plan, err := scheduler.Plan(context.Background(), []policy.Destination{mainnet, base}, dist)
+	require.NoError(t, err)
+
+	// Mainnet: opsetNever is already stale; opsetUrgent (1m left) lands at +3m; opsetFresh
+	// (2m left) lands at +4.5m. Base has no table for opsetUrgent or opsetNever, so those go
+	// first and opsetFresh also lands at +4.5m.
+	require.Len(t, plan.Alerts, 4)
+	assert.Equal(t, opsetNever, plan.Alerts[0].Task.OperatorSet)
+	assert.Contains(t, plan.Alerts[0].Reason, "went stale")
+	assert.Equal(t, opsetUrgent, plan.Alerts[1].Task.OperatorSet)
+	assert.Contains(t, plan.Alerts[1].Reason, "expected to land")
+	assert.Equal(t, opsetFresh, plan.Alerts[2].Task.OperatorSet)
+	assert.Equal(t, mainnet.ChainId, plan.Alerts[2].Task.Destination.ChainId)
+	assert.Equal(t, opsetFresh, plan.Alerts[3].Task.OperatorSet)
+	assert.Equal(t, base.ChainId, plan.Alerts[3].Task.Destination.ChainId)
+	assert.Equal(t, testNow.Add(270*time.Second), plan.Alerts[3].Task.EstimatedCompletion)
+}
+
+func TestPlan_PolicyAndGas(t *testing.T) {
+	reader := fakeStalenessReader{
+		{mainnet.ChainId, opsetFresh}: {LatestReferenceTimestamp: updatedAgo(10 * time.Minute), MaxStalenessPeriod: 3600},
+		{base.ChainId, opsetFresh}:    {LatestReferenceTimestamp: updatedAgo(10 * time.Minute), MaxStalenessPeriod: 3600},
+	}
+	pol := &policy.Policy{
+		Default: policy.EffectAllow,
+		Rules: []policy.Rule{
+			{Name: "skip-urgent", Effect: policy.EffectDeny, OperatorSetIds: []uint32{opsetUrgent.Id}},
+			{Name: "base-cap", Effect: policy.EffectAllow, ChainIds: []uint64{base.ChainId}, MaxGasPriceWei: 1000},
+		},
+	}
+	scheduler, err := NewScheduler(&Config{
+		Reader:    reader,
+		GasOracle: fakeGasOracle{base.ChainId: big.NewInt(2000)},
+		Policy:    pol,
+		Now:       func() time.Time { return testNow },
+	})
+	require.NoError(t, err)
+
+	plan, err := scheduler.Plan(context.Background(), []policy.Destination{mainnet, base},
+		newTestDistribution(t, opsetFresh, opsetUrgent))
+	require.NoError(t, err)
+
+	require.Len(t, plan.Tasks, 2, "denied operator set is left out")
+	require.Len(t, plan.Alerts, 1)
+	assert.Equal(t, base.ChainId, plan.Alerts[0].Task.Destination.ChainId)
+	assert.Equal(t, big.NewInt(1000), plan.Alerts[0].Task.MaxGasPrice)
+	assert.Contains(t, plan.Alerts[0].Reason, "above the policy maximum")
+}
+
+func TestNewScheduler_RequiresReader(t *testing.T) {
+	_, err := NewScheduler(&Config{})
+	assert.Error(t, err)
+}
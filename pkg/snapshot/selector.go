@@ -0,0 +1,216 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// SelectorKind is how a Selector picks its block.
+type SelectorKind string
+
+const (
+	// SelectLatest selects the chain head
+	SelectLatest SelectorKind = "latest"
+	// SelectSafe selects the latest safe block
+	SelectSafe SelectorKind = "safe"
+	// SelectFinalized selects the latest finalized block
+	SelectFinalized SelectorKind = "finalized"
+	// SelectNumber selects an explicit block number
+	SelectNumber SelectorKind = "number"
+	// SelectTimestamp selects the last block at or before a timestamp
+	SelectTimestamp SelectorKind = "timestamp"
+)
+
+// timestampPrefix introduces a timestamp selector, e.g. "timestamp:1700000000".
+const timestampPrefix = "timestamp:"
+
+// Selector picks a reference block: a finality tag optionally offset by a number of blocks, an
+// explicit block number, or the last block at or before a timestamp.
+type Selector struct {
+	// Kind is how the block is picked
+	Kind SelectorKind
+	// Offset is the number of blocks before a latest, safe or finalized block
+	Offset uint64
+	// Number is the block number for SelectNumber
+	Number uint64
+	// Timestamp is the Unix timestamp for SelectTimestamp
+	Timestamp uint64
+	// Cadence, when non-zero, moves the selected block back to the last block at or before the
+	// start of its cadence period, i.e. its timestamp rounded down to a multiple of Cadence
+	// seconds. Use the CrossChainRegistry's table update cadence to align reference timestamps.
+	Cadence uint32
+}
+
+// ParseSelector parses a block selector. Accepted forms are "latest", "safe" and "finalized",
+// each optionally followed by "-N" to go N blocks back (e.g. "latest-10"); a block number;
+// and "timestamp:" followed by a Unix timestamp or an RFC 3339 time. The empty string selects
+// the latest block.
+//
+// Parameters:
+//   - value: The selector to parse
+//
+// Returns:
+//   - Selector: The parsed selector
+//   - error: An error if the selector is malformed
+func ParseSelector(value string) (Selector, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Selector{Kind: SelectLatest}, nil
+	}
+
+	if strings.HasPrefix(value, timestampPrefix) {
+		raw := strings.TrimPrefix(value, timestampPrefix)
+		if unix, err := strconv.ParseUint(raw, 10, 64); err == nil {
+			return Selector{Kind: SelectTimestamp, Timestamp: unix}, nil
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil || parsed.Unix() < 0 {
+			return Selector{}, fmt.Errorf("invalid block selector %q: timestamp must be a Unix timestamp or RFC 3339 time", value)
+		}
+		return Selector{Kind: SelectTimestamp, Timestamp: uint64(parsed.Unix())}, nil
+	}
+
+	if number, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return Selector{Kind: SelectNumber, Number: number}, nil
+	}
+
+	tag, offset, hasOffset := strings.Cut(value, "-")
+	kind := SelectorKind(tag)
+	if kind != SelectLatest && kind != SelectSafe && kind != SelectFinalized {
+		return Selector{}, fmt.Errorf("invalid block selector %q: expected latest, safe, finalized, a block number, or timestamp:T", value)
+	}
+	selector := Selector{Kind: kind}
+	if hasOffset {
+		n, err := strconv.ParseUint(offset, 10, 64)
+		if err != nil {
+			return Selector{}, fmt.Errorf("invalid block selector %q: offset must be a number of blocks", value)
+		}
+		selector.Offset = n
+	}
+	return selector, nil
+}
+
+// String returns the selector in the form accepted by ParseSelector, without the cadence.
+func (s Selector) String() string {
+	switch s.Kind {
+	case SelectNumber:
+		return strconv.FormatUint(s.Number, 10)
+	case SelectTimestamp:
+		return timestampPrefix + strconv.FormatUint(s.Timestamp, 10)
+	case "":
+		return string(SelectLatest)
+	}
+	if s.Offset > 0 {
+		return fmt.Sprintf("%s-%d", s.Kind, s.Offset)
+	}
+	return string(s.Kind)
+}
+
+// Resolve fetches the selected block's header and pins a snapshot to it.
+//
+// Parameters:
+//   - ctx: Context for the RPC calls
+//   - client: The L1 client
+//
+// Returns:
+//   - *Snapshot: The snapshot pinned to the selected block
+//   - error: An error if the block does not exist or a header cannot be fetched
+func (s Selector) Resolve(ctx context.Context, client HeaderReader) (*Snapshot, error) {
+	header, err := s.resolveHeader(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	if s.Cadence > 0 {
+		boundary := header.Time / uint64(s.Cadence) * uint64(s.Cadence)
+		if boundary < header.Time {
+			if header, err = headerAtOrBefore(ctx, client, header, boundary); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return New(header), nil
+}
+
+func (s Selector) resolveHeader(ctx context.Context, client HeaderReader) (*types.Header, error) {
+	switch s.Kind {
+	case SelectNumber:
+		return fetchHeader(ctx, client, new(big.Int).SetUint64(s.Number))
+	case SelectTimestamp:
+		latest, err := fetchHeader(ctx, client, nil)
+		if err != nil {
+			return nil, err
+		}
+		return headerAtOrBefore(ctx, client, latest, s.Timestamp)
+	}
+
+	var tag *big.Int
+	switch s.Kind {
+	case SelectLatest, "":
+	case SelectSafe:
+		tag = big.NewInt(int64(rpc.SafeBlockNumber))
+	case SelectFinalized:
+		tag = big.NewInt(int64(rpc.FinalizedBlockNumber))
+	default:
+		return nil, fmt.Errorf("unknown block selector kind %q", s.Kind)
+	}
+	header, err := fetchHeader(ctx, client, tag)
+	if err != nil || s.Offset == 0 {
+		return header, err
+	}
+	if s.Offset > header.Number.Uint64() {
+		return nil, fmt.Errorf("block selector %s is before genesis: %s block is %d", s, s.Kind, header.Number.Uint64())
+	}
+	return fetchHeader(ctx, client, new(big.Int).SetUint64(header.Number.Uint64()-s.Offset))
+}
+
+// headerAtOrBefore binary searches blocks up to upper for the last one whose timestamp is at
+// or before t.
+func headerAtOrBefore(ctx context.Context, client HeaderReader, upper *types.Header, t uint64) (*types.Header, error) {
+	if upper.Time <= t {
+		return upper, nil
+	}
+	low, err := fetchHeader(ctx, client, new(big.Int))
+	if err != nil {
+		return nil, err
+	}
+	if low.Time > t {
+		return nil, fmt.Errorf("timestamp %d is before genesis block timestamp %d", t, low.Time)
+	}
+
+	// Invariant: low.Time <= t < timestamp of block high
+	high := upper.Number.Uint64()
+	for high-low.Number.Uint64() > 1 {
+		mid := low.Number.Uint64() + (high-low.Number.Uint64())/2
+		header, err := fetchHeader(ctx, client, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return nil, err
+		}
+		if header.Time <= t {
+			low = header
+		} else {
+			high = mid
+		}
+	}
+	return low, nil
+}
+
+func fetchHeader(ctx context.Context, client HeaderReader, number *big.Int) (*types.Header, error) {
+	header, err := client.HeaderByNumber(ctx, number)
+	if err != nil {
+		if number == nil {
+			return nil, fmt.Errorf("failed to get latest block header: %w", err)
+		}
+		if number.Sign() < 0 {
+			return nil, fmt.Errorf("failed to get %s block header: %w", rpc.BlockNumber(number.Int64()), err)
+		}
+		return nil, fmt.Errorf("failed to get block header %d: %w", number, err)
+	}
+	return header, nil
+}
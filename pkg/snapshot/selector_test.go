@@ -0,0 +1,118 @@
+package snapshot
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newChain returns a client for blocks 0..latest with timestamps 1000 + 12*n.
+func newChain(latest uint64) *fakeHeaderReader {
+	headers := make(map[uint64]*types.Header)
+	for n := uint64(0); n <= latest; n++ {
+		headers[n] = &types.Header{Number: new(big.Int).SetUint64(n), Time: 1000 + 12*n}
+	}
+	return &fakeHeaderReader{
+		headers: headers,
+		latest:  latest,
+		tags: map[int64]uint64{
+			int64(rpc.SafeBlockNumber):      latest - 32,
+			int64(rpc.FinalizedBlockNumber): latest - 64,
+		},
+	}
+}
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Selector
+	}{
+		{value: "", want: Selector{Kind: SelectLatest}},
+		{value: "latest", want: Selector{Kind: SelectLatest}},
+		{value: "safe", want: Selector{Kind: SelectSafe}},
+		{value: "finalized", want: Selector{Kind: SelectFinalized}},
+		{value: "latest-10", want: Selector{Kind: SelectLatest, Offset: 10}},
+		{value: "finalized-3", want: Selector{Kind: SelectFinalized, Offset: 3}},
+		{value: "12345", want: Selector{Kind: SelectNumber, Number: 12345}},
+		{value: "timestamp:1700000000", want: Selector{Kind: SelectTimestamp, Timestamp: 1700000000}},
+		{value: "timestamp:2023-11-14T22:13:20Z", want: Selector{Kind: SelectTimestamp, Timestamp: 1700000000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSelector(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			roundTrip, err := ParseSelector(got.String())
+			require.NoError(t, err)
+			assert.Equal(t, got, roundTrip)
+		})
+	}
+
+	for _, invalid := range []string{"pending", "latest-", "latest-x", "-5", "timestamp:", "timestamp:yesterday"} {
+		_, err := ParseSelector(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestSelectorResolve(t *testing.T) {
+	client := newChain(1000)
+
+	tests := []struct {
+		name     string
+		selector Selector
+		want     uint64
+	}{
+		{name: "latest", selector: Selector{Kind: SelectLatest}, want: 1000},
+		{name: "zero value", selector: Selector{}, want: 1000},
+		{name: "latest offset", selector: Selector{Kind: SelectLatest, Offset: 10}, want: 990},
+		{name: "safe", selector: Selector{Kind: SelectSafe}, want: 968},
+		{name: "finalized offset", selector: Selector{Kind: SelectFinalized, Offset: 6}, want: 930},
+		{name: "number", selector: Selector{Kind: SelectNumber, Number: 42}, want: 42},
+		{name: "exact timestamp", selector: Selector{Kind: SelectTimestamp, Timestamp: 1000 + 12*500}, want: 500},
+		{name: "between blocks", selector: Selector{Kind: SelectTimestamp, Timestamp: 1000 + 12*500 + 11}, want: 500},
+		{name: "genesis", selector: Selector{Kind: SelectTimestamp, Timestamp: 1005}, want: 0},
+		{name: "after head", selector: Selector{Kind: SelectTimestamp, Timestamp: 1 << 40}, want: 1000},
+		// Block 990 has timestamp 12880; the 60s boundary is 12840, which is block 986
+		{name: "aligned to cadence", selector: Selector{Kind: SelectLatest, Offset: 10, Cadence: 60}, want: 986},
+		{name: "already aligned", selector: Selector{Kind: SelectNumber, Number: 985, Cadence: 4}, want: 985},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snap, err := tt.selector.Resolve(context.Background(), client)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, snap.Number)
+			assert.Equal(t, client.headers[tt.want].Hash(), snap.Hash)
+		})
+	}
+}
+
+func TestSelectorResolve_BinarySearch(t *testing.T) {
+	client := newChain(1 << 16)
+	snap, err := Selector{Kind: SelectTimestamp, Timestamp: 1000 + 12*12345}.Resolve(context.Background(), client)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(12345), snap.Number)
+	assert.LessOrEqual(t, client.calls, 20, "search is logarithmic in the chain length")
+}
+
+func TestSelectorResolve_Errors(t *testing.T) {
+	client := newChain(100)
+
+	_, err := Selector{Kind: SelectTimestamp, Timestamp: 999}.Resolve(context.Background(), client)
+	assert.ErrorContains(t, err, "before genesis")
+
+	_, err = Selector{Kind: SelectLatest, Offset: 101}.Resolve(context.Background(), client)
+	assert.ErrorContains(t, err, "before genesis")
+
+	_, err = Selector{Kind: SelectNumber, Number: 101}.Resolve(context.Background(), client)
+	assert.ErrorContains(t, err, "failed to get block header 101")
+
+	client.tags = nil
+	_, err = Selector{Kind: SelectFinalized}.Resolve(context.Background(), client)
+	assert.ErrorContains(t, err, "failed to get finalized block header")
+}
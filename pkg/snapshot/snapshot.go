@@ -0,0 +1,129 @@
+// Package snapshot pins L1 reads to a single reference block. A Snapshot is resolved
+// once to a (number, hash, timestamp) triple, and every contract read made through it
+// is addressed by block hash (EIP-1898), so all reads in a run see the same state even
+// if the chain head moves. If the reference block is reorged out of the canonical
+// chain during the run, the snapshot reports ErrReorg.
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	// ErrReorg is returned when the snapshot's reference block is no longer part of the canonical chain
+	ErrReorg = errors.New("reference block was reorged out of the canonical chain")
+)
+
+// HeaderReader is the subset of an L1 client needed to resolve and verify a snapshot.
+type HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Snapshot identifies the L1 block that a calculation and transport run reads state from.
+type Snapshot struct {
+	// Number is the reference block number
+	Number uint64 `json:"number"`
+	// Hash is the reference block hash
+	Hash common.Hash `json:"hash"`
+	// Timestamp is the reference block timestamp
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// New creates a snapshot from a block header.
+//
+// Parameters:
+//   - header: The header of the reference block
+//
+// Returns:
+//   - *Snapshot: The snapshot pinned to the header's block
+func New(header *types.Header) *Snapshot {
+	return &Snapshot{
+		Number:    header.Number.Uint64(),
+		Hash:      header.Hash(),
+		Timestamp: header.Time,
+	}
+}
+
+// Resolve fetches the header for a block number and pins a snapshot to it.
+//
+// Parameters:
+//   - ctx: Context for the RPC call
+//   - client: The L1 client
+//   - number: The reference block number, or nil for the latest block
+//
+// Returns:
+//   - *Snapshot: The snapshot pinned to the resolved block
+//   - error: An error if the header cannot be fetched
+func Resolve(ctx context.Context, client HeaderReader, number *big.Int) (*Snapshot, error) {
+	header, err := fetchHeader(ctx, client, number)
+	if err != nil {
+		return nil, err
+	}
+	return New(header), nil
+}
+
+// ReferenceTimestamp returns the block timestamp as the uint32 reference timestamp used on-chain.
+func (s *Snapshot) ReferenceTimestamp() uint32 {
+	return uint32(s.Timestamp)
+}
+
+// CallOpts returns call options that read state at the snapshot's block hash.
+//
+// Parameters:
+//   - ctx: Context for the call
+//
+// Returns:
+//   - *bind.CallOpts: Call options pinned to the snapshot
+func (s *Snapshot) CallOpts(ctx context.Context) *bind.CallOpts {
+	return &bind.CallOpts{
+		Context:   ctx,
+		BlockHash: s.Hash,
+	}
+}
+
+// Verify checks that the snapshot's block is still the canonical block at its height.
+//
+// Parameters:
+//   - ctx: Context for the RPC call
+//   - client: The L1 client
+//
+// Returns:
+//   - error: An error wrapping ErrReorg if the canonical hash changed, any RPC error, or nil
+func (s *Snapshot) Verify(ctx context.Context, client HeaderReader) error {
+	header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(s.Number))
+	if err != nil {
+		return fmt.Errorf("failed to get block header %d: %w", s.Number, err)
+	}
+	if header.Hash() != s.Hash {
+		return fmt.Errorf("%w: block %d hash changed from %s to %s", ErrReorg, s.Number, s.Hash.Hex(), header.Hash().Hex())
+	}
+	return nil
+}
+
+// CheckError explains a failed pinned read. If the snapshot's block was reorged out, the
+// reorg error is returned in place of err, since reads by a vanished block hash fail with
+// node-specific "not found" errors. Otherwise err is returned unchanged.
+//
+// Parameters:
+//   - ctx: Context for the RPC call
+//   - client: The L1 client
+//   - err: The error returned by a pinned read
+//
+// Returns:
+//   - error: The reorg error, err, or nil if err is nil
+func (s *Snapshot) CheckError(ctx context.Context, client HeaderReader, err error) error {
+	if err == nil {
+		return nil
+	}
+	if verifyErr := s.Verify(ctx, client); errors.Is(verifyErr, ErrReorg) {
+		return verifyErr
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHeaderReader struct {
+	headers map[uint64]*types.Header
+	latest  uint64
+	// tags maps rpc.SafeBlockNumber and rpc.FinalizedBlockNumber to block numbers
+	tags  map[int64]uint64
+	err   error
+	calls int
+}
+
+func (f *fakeHeaderReader) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	n := f.latest
+	if number != nil && number.Sign() < 0 {
+		tagged, ok := f.tags[number.Int64()]
+		if !ok {
+			return nil, errors.New("unsupported tag")
+		}
+		n = tagged
+	} else if number != nil {
+		n = number.Uint64()
+	}
+	h, ok := f.headers[n]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return h, nil
+}
+
+func TestResolve(t *testing.T) {
+	h100 := &types.Header{Number: big.NewInt(100), Time: 1000}
+	h101 := &types.Header{Number: big.NewInt(101), Time: 1012}
+	client := &fakeHeaderReader{headers: map[uint64]*types.Header{100: h100, 101: h101}, latest: 101}
+
+	snap, err := Resolve(context.Background(), client, big.NewInt(100))
+	require.NoError(t, err)
+	assert.Equal(t, &Snapshot{Number: 100, Hash: h100.Hash(), Timestamp: 1000}, snap)
+
+	snap, err = Resolve(context.Background(), client, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(101), snap.Number)
+	assert.Equal(t, uint32(1012), snap.ReferenceTimestamp())
+
+	opts := snap.CallOpts(context.Background())
+	assert.Equal(t, h101.Hash(), opts.BlockHash)
+	assert.Nil(t, opts.BlockNumber)
+}
+
+func TestVerify(t *testing.T) {
+	original := &types.Header{Number: big.NewInt(100), Time: 1000}
+	replacement := &types.Header{Number: big.NewInt(100), Time: 1001}
+	snap := New(original)
+
+	client := &fakeHeaderReader{headers: map[uint64]*types.Header{100: original}}
+	require.NoError(t, snap.Verify(context.Background(), client))
+
+	client.headers[100] = replacement
+	err := snap.Verify(context.Background(), client)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrReorg)
+	assert.Contains(t, err.Error(), replacement.Hash().Hex())
+
+	client.err = errors.New("connection refused")
+	err = snap.Verify(context.Background(), client)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrReorg)
+}
+
+func TestCheckError(t *testing.T) {
+	original := &types.Header{Number: big.NewInt(100), Time: 1000}
+	snap := New(original)
+	readErr := errors.New("execution reverted")
+
+	client := &fakeHeaderReader{headers: map[uint64]*types.Header{100: original}}
+	assert.NoError(t, snap.CheckError(context.Background(), client, nil))
+	assert.Equal(t, readErr, snap.CheckError(context.Background(), client, readErr))
+
+	client.headers[100] = &types.Header{Number: big.NewInt(100), ParentHash: common.HexToHash("0x01")}
+	assert.ErrorIs(t, snap.CheckError(context.Background(), client, readErr), ErrReorg)
+
+	client.err = errors.New("connection refused")
+	assert.Equal(t, readErr, snap.CheckError(context.Background(), client, readErr))
+}
@@ -0,0 +1,108 @@
+// Package tracing provides OpenTelemetry instrumentation helpers for multichain-go.
+// Tracing is opt-in: components accept a trace.TracerProvider in their configuration
+// and, when none is provided, run without creating spans or altering contexts.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the instrumentation scope used for all tracers created by this library.
+const InstrumentationName = "github.com/Layr-Labs/multichain-go"
+
+// NewTracer returns a tracer from the given provider, or nil if no provider is configured.
+// A nil tracer disables span creation in Start.
+//
+// Parameters:
+//   - tp: The tracer provider to create the tracer from, may be nil
+//
+// Returns:
+//   - trace.Tracer: A tracer scoped to InstrumentationName, or nil
+func NewTracer(tp trace.TracerProvider) trace.Tracer {
+	if tp == nil {
+		return nil
+	}
+	return tp.Tracer(InstrumentationName)
+}
+
+// Start begins a new span as a child of any span already in ctx.
+// If tracer is nil the context is returned unchanged together with a non-recording span,
+// so callers can instrument unconditionally.
+//
+// Parameters:
+//   - ctx: The parent context
+//   - tracer: The tracer to use, may be nil
+//   - name: The span name
+//   - attrs: Attributes to set on the span
+//
+// Returns:
+//   - context.Context: The context carrying the new span
+//   - trace.Span: The started span, which must be ended by the caller
+func Start(ctx context.Context, tracer trace.Tracer, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if tracer == nil {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, if non-nil, and ends it.
+//
+// Parameters:
+//   - span: The span to end
+//   - err: The error the traced operation finished with, may be nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ExporterConfig holds the configuration for exporting spans over OTLP/HTTP.
+type ExporterConfig struct {
+	// Endpoint is the host:port of the OTLP/HTTP collector
+	Endpoint string
+	// Insecure disables TLS when talking to the collector
+	Insecure bool
+	// ServiceName is reported as the service.name resource attribute
+	ServiceName string
+}
+
+// NewTracerProvider creates a batching tracer provider that exports spans over OTLP/HTTP.
+// The caller is responsible for calling Shutdown on the returned provider to flush spans.
+//
+// Parameters:
+//   - ctx: Context used while creating the exporter
+//   - cfg: The exporter configuration
+//
+// Returns:
+//   - *sdktrace.TracerProvider: The configured tracer provider
+//   - error: An error if the exporter cannot be created
+func NewTracerProvider(ctx context.Context, cfg *ExporterConfig) (*sdktrace.TracerProvider, error) {
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(cfg.Endpoint),
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res := resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName))
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	), nil
+}
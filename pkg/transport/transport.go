@@ -10,6 +10,11 @@ import (
 	"github.com/Layr-Labs/multichain-go/pkg/blsSigner"
 	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
 	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/logger"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/policy"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/Layr-Labs/multichain-go/pkg/tracing"
 	"github.com/Layr-Labs/multichain-go/pkg/txSigner"
 	"github.com/Layr-Labs/multichain-go/pkg/util"
 	"github.com/ethereum/go-ethereum"
@@ -18,14 +23,38 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
-	merkletree "github.com/wealdtech/go-merkletree/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"math"
 	"math/big"
+	"sync"
 	"time"
 )
 
 type TransportConfig struct {
 	L1CrossChainRegistryAddress common.Address
+	// TracerProvider, when set, enables OpenTelemetry spans for each transport step
+	TracerProvider trace.TracerProvider
+	// Policy, when set, decides which chains and operator sets are transported
+	Policy *policy.Policy
+	// ChangeDetector is consulted for policy rules that only transport changed tables
+	ChangeDetector policy.ChangeDetector
+	// OnlyChangedTables, when set, consults the ChangeDetector for every operator table, as if
+	// every allowing policy rule set OnlyIfChanged
+	OnlyChangedTables bool
+}
+
+// TableDecision records whether an operator table was sent to a destination chain, and why.
+type TableDecision struct {
+	// ChainId is the destination chain ID
+	ChainId uint64
+	// OperatorSet is the operator set whose table was considered
+	OperatorSet distribution.OperatorSet
+	// Transported reports whether an UpdateOperatorTable transaction succeeded
+	Transported bool
+	// Reason explains the decision
+	Reason string
 }
 
 type Transport struct {
@@ -35,6 +64,11 @@ type Transport struct {
 	blsSigner                blsSigner.IBLSSigner
 	txSigner                 txSigner.ITransactionSigner
 	chainManager             chainManager.IChainManager
+	l1Client                 chainManager.EthClientInterface
+	tracer                   trace.Tracer
+
+	decisionsMu sync.Mutex
+	decisions   []TableDecision
 }
 
 func NewTransport(
@@ -56,12 +90,17 @@ func NewTransport(
 		blsSigner:                blsSig,
 		txSigner:                 txSig,
 		chainManager:             cm,
+		l1Client:                 client,
 		crossChainRegistryCaller: ccRegistryCaller,
+		tracer:                   tracing.NewTracer(cfg.TracerProvider),
 	}, nil
 }
 
 var emptyRoot [32]byte
 
+// SignAndTransportGlobalTableRoot signs the global table root and confirms it on every supported
+// destination chain. Supported chains are read from the CrossChainRegistry at the latest block.
+// The policy is evaluated for each chain alone.
 func (t *Transport) SignAndTransportGlobalTableRoot(
 	ctx context.Context,
 	root [32]byte,
@@ -69,13 +108,73 @@ func (t *Transport) SignAndTransportGlobalTableRoot(
 	referenceBlockHeight uint64,
 	ignoreChainIds []*big.Int,
 ) error {
-	t.logger.Info("Signing and transporting global table root",
+	return t.signAndTransportGlobalTableRoot(ctx, root, nil, referenceTimestamp, referenceBlockHeight, nil, ignoreChainIds)
+}
+
+// SignAndTransportGlobalTableRootAtSnapshot is SignAndTransportGlobalTableRoot with the reference
+// block taken from snap. Supported chains are read at the snapshot's block hash, and the snapshot
+// is re-verified before each destination chain; a reorg aborts the run with snapshot.ErrReorg.
+func (t *Transport) SignAndTransportGlobalTableRootAtSnapshot(
+	ctx context.Context,
+	root [32]byte,
+	snap *snapshot.Snapshot,
+	ignoreChainIds []*big.Int,
+) error {
+	return t.signAndTransportGlobalTableRoot(ctx, root, nil, snap.ReferenceTimestamp(), snap.Number, snap, ignoreChainIds)
+}
+
+// SignAndTransportGlobalTableRootForDistribution is SignAndTransportGlobalTableRoot with the
+// policy evaluated against the operator sets of dist, the distribution the root was calculated
+// from. The root is confirmed on a chain when the policy allows any of them there, with the
+// lowest of their fee caps (see policy.Policy.EvaluateRoot).
+func (t *Transport) SignAndTransportGlobalTableRootForDistribution(
+	ctx context.Context,
+	root [32]byte,
+	dist *distribution.Distribution,
+	referenceTimestamp uint32,
+	referenceBlockHeight uint64,
+	ignoreChainIds []*big.Int,
+) error {
+	return t.signAndTransportGlobalTableRoot(ctx, root, dist, referenceTimestamp, referenceBlockHeight, nil, ignoreChainIds)
+}
+
+// SignAndTransportGlobalTableRootForDistributionAtSnapshot is
+// SignAndTransportGlobalTableRootForDistribution with the reference block taken from snap, as in
+// SignAndTransportGlobalTableRootAtSnapshot.
+func (t *Transport) SignAndTransportGlobalTableRootForDistributionAtSnapshot(
+	ctx context.Context,
+	root [32]byte,
+	dist *distribution.Distribution,
+	snap *snapshot.Snapshot,
+	ignoreChainIds []*big.Int,
+) error {
+	return t.signAndTransportGlobalTableRoot(ctx, root, dist, snap.ReferenceTimestamp(), snap.Number, snap, ignoreChainIds)
+}
+
+func (t *Transport) signAndTransportGlobalTableRoot(
+	ctx context.Context,
+	root [32]byte,
+	dist *distribution.Distribution,
+	referenceTimestamp uint32,
+	referenceBlockHeight uint64,
+	snap *snapshot.Snapshot,
+	ignoreChainIds []*big.Int,
+) (err error) {
+	ctx, span := tracing.Start(ctx, t.tracer, "Transport.SignAndTransportGlobalTableRoot",
+		attribute.String("root", hexutil.Encode(root[:])),
+		attribute.Int64("referenceBlockHeight", int64(referenceBlockHeight)),
+		attribute.Int64("referenceTimestamp", int64(referenceTimestamp)),
+	)
+	defer func() { tracing.End(span, err) }()
+	l := logger.WithTraceContext(ctx, t.logger)
+
+	l.Info("Signing and transporting global table root",
 		zap.String("root", hexutil.Encode(root[:])),
 		zap.Uint64("blockHeight", referenceBlockHeight),
 	)
 
 	if root == emptyRoot {
-		t.logger.Info("Empty root provided, skipping signing and transport")
+		l.Info("Empty root provided, skipping signing and transport")
 		return nil
 	}
 
@@ -84,124 +183,171 @@ func (t *Transport) SignAndTransportGlobalTableRoot(
 		return fmt.Errorf("failed to get APK from private key: %w", err)
 	}
 
-	t.logger.Sugar().Infow("Getting supported chains from cross-chain registry",
+	l.Sugar().Infow("Getting supported chains from cross-chain registry",
 		zap.String("crossChainRegistryAddress", t.config.L1CrossChainRegistryAddress.String()),
 	)
 
-	chainIds, addresses, err := t.crossChainRegistryCaller.GetSupportedChains(&bind.CallOpts{})
+	chainIds, addresses, err := t.getSupportedChains(ctx, snap)
 	if err != nil {
-		return fmt.Errorf("failed to get supported chains: %w", err)
+		return err
 	}
 
 	if len(chainIds) == 0 {
 		return fmt.Errorf("no supported chains found in cross-chain registry")
 	}
 
+	opsetSubjects := rootPolicySubjects(dist)
 	for i, chainId := range chainIds {
 		ignoredChainId := util.Find(ignoreChainIds, func(id *big.Int) bool {
 			return chainId.Cmp(id) == 0
 		})
 		if ignoredChainId != nil {
-			t.logger.Sugar().Infow("Skipping transport for ignored chain",
+			l.Sugar().Infow("Skipping transport for ignored chain",
 				zap.Uint64("chainId", chainId.Uint64()),
 				zap.String("chainAddress", addresses[i].String()),
 			)
 			continue
 		}
-		addr := addresses[i]
-		chain, err := t.chainManager.GetChainForId(chainId.Uint64())
-		if err != nil {
-			return fmt.Errorf("failed to get chain for ID %d: %w", chainId, err)
+		decision := t.config.Policy.EvaluateRoot(chainId.Uint64(), opsetSubjects)
+		if !decision.Allowed {
+			l.Sugar().Infow("Skipping transport for chain denied by policy",
+				zap.Uint64("chainId", chainId.Uint64()),
+				zap.String("chainAddress", addresses[i].String()),
+				zap.String("rule", decision.Rule),
+			)
+			continue
 		}
-
-		t.logger.Sugar().Infow("Transporting global table root to chain",
-			zap.Uint64("chainId", chainId.Uint64()),
-			zap.String("chainAddress", addr.String()),
-		)
-		updaterTransactor, err := getOperatorTableUpdaterForChainClient(addr, chain.RPCClient)
-		if err != nil {
-			return fmt.Errorf("failed to get operator table updater transactor for chain %d: %w", chainId, err)
+		if err := t.verifySnapshot(ctx, snap); err != nil {
+			return err
 		}
-
-		blockHeight := uint32(referenceBlockHeight)
-		messageHash, err := updaterTransactor.GetGlobalTableUpdateMessageHash(&bind.CallOpts{}, root, referenceTimestamp, blockHeight)
+		transported, err := t.transportGlobalTableRootToChain(ctx, chainId, addresses[i], root, referenceTimestamp, referenceBlockHeight, apkG2, decision.MaxGasPrice)
 		if err != nil {
-			return fmt.Errorf("failed to get global table update message hash: %w", err)
+			return err
 		}
-		signableDigest, err := updaterTransactor.GetGlobalTableUpdateSignableDigest(&bind.CallOpts{}, root, referenceTimestamp, blockHeight)
-		if err != nil {
-			return fmt.Errorf("failed to get global table update signable digest: %w", err)
+		if transported {
+			time.Sleep(time.Second * 3) // Sleep to avoid rate limiting issues
 		}
+	}
 
-		sigG1, err := t.generateMessageHashSignature(signableDigest)
-		if err != nil {
-			return err
-		}
+	return nil
+}
 
-		previouslyReferencedTimestamp, err := updaterTransactor.GetGeneratorReferenceTimestamp(&bind.CallOpts{})
-		if err != nil {
-			return fmt.Errorf("failed to get latest reference timestamp: %w", err)
-		}
-		t.logger.Sugar().Infow("reference timestamp for global table root",
-			zap.Uint32("previouslyReferencedTimestamp", previouslyReferencedTimestamp),
-			zap.Uint32("newReferenceTimestamp", referenceTimestamp),
-			zap.Uint64("chainId", chainId.Uint64()),
-		)
+// transportGlobalTableRootToChain confirms the global table root on a single destination chain.
+// Failures to submit the transaction are logged and reported as not transported so the
+// remaining chains can proceed; any other failure is returned as an error.
+func (t *Transport) transportGlobalTableRootToChain(
+	ctx context.Context,
+	chainId *big.Int,
+	addr common.Address,
+	root [32]byte,
+	referenceTimestamp uint32,
+	referenceBlockHeight uint64,
+	apkG2 *IOperatorTableUpdater.BN254G2Point,
+	maxGasPrice *big.Int,
+) (transported bool, err error) {
+	ctx, span := tracing.Start(ctx, t.tracer, "Transport.TransportGlobalTableRootToChain",
+		attribute.Int64("chainId", int64(chainId.Uint64())),
+		attribute.String("chainAddress", addr.String()),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Bool("transported", transported))
+		tracing.End(span, err)
+	}()
+	l := logger.WithTraceContext(ctx, t.logger)
 
-		// Get transaction options from signer
-		txOpts, err := t.txSigner.GetNoSendTransactOpts(context.Background(), chainId)
-		if err != nil {
-			return fmt.Errorf("failed to get transaction options: %w", err)
-		}
+	chain, err := t.chainManager.GetChainForId(chainId.Uint64())
+	if err != nil {
+		return false, fmt.Errorf("failed to get chain for ID %d: %w", chainId, err)
+	}
 
-		cert := IOperatorTableUpdater.IBN254CertificateVerifierTypesBN254Certificate{
-			MessageHash:        messageHash,
-			ReferenceTimestamp: previouslyReferencedTimestamp,
-			Signature:          *sigG1,
-			Apk:                *apkG2,
-		}
+	l.Sugar().Infow("Transporting global table root to chain",
+		zap.Uint64("chainId", chainId.Uint64()),
+		zap.String("chainAddress", addr.String()),
+	)
+	updaterTransactor, err := getOperatorTableUpdaterForChainClient(addr, chain.RPCClient)
+	if err != nil {
+		return false, fmt.Errorf("failed to get operator table updater transactor for chain %d: %w", chainId, err)
+	}
 
-		tx, err := updaterTransactor.ConfirmGlobalTableRoot(
-			txOpts,
-			cert,
-			root,
-			referenceTimestamp,
-			blockHeight,
-		)
-		t.logger.Sugar().Infow("Created transaction for global table root",
+	blockHeight := uint32(referenceBlockHeight)
+	messageHash, err := updaterTransactor.GetGlobalTableUpdateMessageHash(&bind.CallOpts{Context: ctx}, root, referenceTimestamp, blockHeight)
+	if err != nil {
+		return false, fmt.Errorf("failed to get global table update message hash: %w", err)
+	}
+	signableDigest, err := updaterTransactor.GetGlobalTableUpdateSignableDigest(&bind.CallOpts{Context: ctx}, root, referenceTimestamp, blockHeight)
+	if err != nil {
+		return false, fmt.Errorf("failed to get global table update signable digest: %w", err)
+	}
+
+	sigG1, err := t.generateMessageHashSignature(signableDigest)
+	if err != nil {
+		return false, err
+	}
+
+	previouslyReferencedTimestamp, err := updaterTransactor.GetGeneratorReferenceTimestamp(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return false, fmt.Errorf("failed to get latest reference timestamp: %w", err)
+	}
+	l.Sugar().Infow("reference timestamp for global table root",
+		zap.Uint32("previouslyReferencedTimestamp", previouslyReferencedTimestamp),
+		zap.Uint32("newReferenceTimestamp", referenceTimestamp),
+		zap.Uint64("chainId", chainId.Uint64()),
+	)
+
+	// Get transaction options from signer
+	txOpts, err := t.txSigner.GetNoSendTransactOpts(ctx, chainId)
+	if err != nil {
+		return false, fmt.Errorf("failed to get transaction options: %w", err)
+	}
+
+	cert := IOperatorTableUpdater.IBN254CertificateVerifierTypesBN254Certificate{
+		MessageHash:        messageHash,
+		ReferenceTimestamp: previouslyReferencedTimestamp,
+		Signature:          *sigG1,
+		Apk:                *apkG2,
+	}
+
+	tx, err := updaterTransactor.ConfirmGlobalTableRoot(
+		txOpts,
+		cert,
+		root,
+		referenceTimestamp,
+		blockHeight,
+	)
+	l.Sugar().Infow("Created transaction for global table root",
+		zap.Uint64("chainId", chainId.Uint64()),
+		zap.Uint64("referenceBlockHeight", referenceBlockHeight),
+		zap.String("chainAddress", addr.String()),
+		zap.String("from", txOpts.From.String()),
+	)
+
+	if err != nil {
+		l.Sugar().Errorw("Failed to confirm global table root, skipping chain",
 			zap.Uint64("chainId", chainId.Uint64()),
-			zap.Uint64("referenceBlockHeight", referenceBlockHeight),
 			zap.String("chainAddress", addr.String()),
-			zap.String("from", txOpts.From.String()),
+			zap.Error(err),
 		)
-
-		if err != nil {
-			t.logger.Sugar().Errorw("Failed to confirm global table root, skipping chain",
-				zap.Uint64("chainId", chainId.Uint64()),
-				zap.String("chainAddress", addr.String()),
-				zap.Error(err),
-			)
-			continue
-		}
-		r, err := t.estimateGasPriceAndLimitAndSendTx(ctx, txOpts.From, tx, chain.RPCClient, "ConfirmGlobalTableRoot")
-		if err != nil {
-			t.logger.Error("Failed to ensure transaction evaled for global table root, skipping chain",
-				zap.Uint64("chainId", chainId.Uint64()),
-				zap.String("chainAddress", addr.String()),
-				zap.Error(err),
-			)
-			continue
-		}
-
-		t.logger.Info("successfully transported global table root",
-			zap.String("transactionHash", r.TxHash.String()),
-			zap.String("root", hexutil.Encode(root[:])),
+		span.RecordError(err)
+		return false, nil
+	}
+	r, err := t.estimateGasPriceAndLimitAndSendTx(ctx, txOpts.From, tx, chain.RPCClient, "ConfirmGlobalTableRoot", maxGasPrice)
+	if err != nil {
+		l.Error("Failed to ensure transaction evaled for global table root, skipping chain",
 			zap.Uint64("chainId", chainId.Uint64()),
+			zap.String("chainAddress", addr.String()),
+			zap.Error(err),
 		)
-		time.Sleep(time.Second * 3) // Sleep to avoid rate limiting issues
+		span.RecordError(err)
+		return false, nil
 	}
 
-	return nil
+	l.Info("successfully transported global table root",
+		zap.String("transactionHash", r.TxHash.String()),
+		zap.String("root", hexutil.Encode(root[:])),
+		zap.Uint64("chainId", chainId.Uint64()),
+	)
+	span.SetAttributes(attribute.String("transactionHash", r.TxHash.String()))
+	return true, nil
 }
 
 // SignAndTransportAvsStakeTable signs and transports the AVS stake table
@@ -213,40 +359,81 @@ func (t *Transport) SignAndTransportAvsStakeTable(
 	referenceBlockHeight uint64,
 	operatorSet distribution.OperatorSet,
 	root [32]byte,
-	tree *merkletree.MerkleTree,
+	tree *distribution.MerkleTree,
 	dist *distribution.Distribution,
 	ignoreChainIds []*big.Int,
 ) error {
-	t.logger.Sugar().Infow("starting transport of AVS stake table for opset",
+	return t.signAndTransportAvsStakeTable(ctx, referenceTimestamp, referenceBlockHeight, nil, operatorSet, root, tree, dist, ignoreChainIds)
+}
+
+// SignAndTransportAvsStakeTableAtSnapshot is SignAndTransportAvsStakeTable with the reference
+// block taken from snap. Supported chains are read at the snapshot's block hash, and the snapshot
+// is re-verified before each destination chain; a reorg aborts the run with snapshot.ErrReorg.
+func (t *Transport) SignAndTransportAvsStakeTableAtSnapshot(
+	ctx context.Context,
+	snap *snapshot.Snapshot,
+	operatorSet distribution.OperatorSet,
+	root [32]byte,
+	tree *distribution.MerkleTree,
+	dist *distribution.Distribution,
+	ignoreChainIds []*big.Int,
+) error {
+	return t.signAndTransportAvsStakeTable(ctx, snap.ReferenceTimestamp(), snap.Number, snap, operatorSet, root, tree, dist, ignoreChainIds)
+}
+
+func (t *Transport) signAndTransportAvsStakeTable(
+	ctx context.Context,
+	referenceTimestamp uint32,
+	referenceBlockHeight uint64,
+	snap *snapshot.Snapshot,
+	operatorSet distribution.OperatorSet,
+	root [32]byte,
+	tree *distribution.MerkleTree,
+	dist *distribution.Distribution,
+	ignoreChainIds []*big.Int,
+) (err error) {
+	ctx, span := tracing.Start(ctx, t.tracer, "Transport.SignAndTransportAvsStakeTable",
+		attribute.Int64("opsetId", int64(operatorSet.Id)),
+		attribute.String("opsetAvs", operatorSet.Avs.String()),
+		attribute.Int64("referenceBlockHeight", int64(referenceBlockHeight)),
+		attribute.Int64("referenceTimestamp", int64(referenceTimestamp)),
+	)
+	defer func() { tracing.End(span, err) }()
+	l := logger.WithTraceContext(ctx, t.logger)
+
+	l.Sugar().Infow("starting transport of AVS stake table for opset",
 		zap.Any("opset", operatorSet),
 	)
+	// read the distribution once, so the proof and table data agree even if it changes meanwhile
+	distSnapshot := dist.Snapshot()
+
 	// generate the proof for the specific operator set
-	proof, opsetIndex, err := t.generateOperatorSetProof(tree, dist, operatorSet)
+	proof, opsetIndex, err := t.generateOperatorSetProof(tree, distSnapshot, operatorSet)
 	if err != nil {
-		t.logger.Error("failed to generate operator set proof", zap.Error(err))
+		l.Error("failed to generate operator set proof", zap.Error(err))
 		return err
 	}
 
 	// get the data specific to the operator set
-	tableInfo, found := dist.GetTableData(operatorSet)
+	tableInfo, found := distSnapshot.GetTableData(operatorSet)
 	if !found {
 		return fmt.Errorf("operator set %v not found in distribution", operatorSet)
 	}
 
-	t.logger.Info("Signing and transporting AVS stake table",
+	l.Info("Signing and transporting AVS stake table",
 		zap.Any("opset", operatorSet),
 		zap.String("root", hexutil.Encode(root[:])),
 		zap.Uint64("blockHeight", referenceBlockHeight),
 	)
 
-	chainIds, addresses, err := t.crossChainRegistryCaller.GetSupportedChains(&bind.CallOpts{})
+	chainIds, addresses, err := t.getSupportedChains(ctx, snap)
 	if err != nil {
-		return fmt.Errorf("failed to get supported chains: %w", err)
+		return err
 	}
 
 	// transport the stake table to each supported destination chain
 	for i, chainId := range chainIds {
-		t.logger.Sugar().Infow("Processing chain for AVS stake table transport",
+		l.Sugar().Infow("Processing chain for AVS stake table transport",
 			zap.Any("opset", operatorSet),
 			zap.Uint64("chainId", chainId.Uint64()),
 			zap.String("chainAddress", addresses[i].String()),
@@ -255,84 +442,308 @@ func (t *Transport) SignAndTransportAvsStakeTable(
 			return chainId.Cmp(id) == 0
 		})
 		if ignoredChainId != nil {
-			t.logger.Sugar().Infow("Skipping transport for ignored chain",
+			l.Sugar().Infow("Skipping transport for ignored chain",
 				zap.Any("opset", operatorSet),
 				zap.Uint64("chainId", chainId.Uint64()),
 				zap.String("chainAddress", addresses[i].String()),
 			)
+			t.recordDecision(chainId.Uint64(), operatorSet, false, "chain ignored")
 			continue
 		}
-		addr := addresses[i]
-		// Get transaction options from signer
-		txOpts, err := t.txSigner.GetNoSendTransactOpts(context.Background(), chainId)
-		if err != nil {
-			return fmt.Errorf("failed to get transaction options: %w", err)
-		}
-		chain, err := t.chainManager.GetChainForId(chainId.Uint64())
-		if err != nil {
-			return fmt.Errorf("failed to get chain for ID %d: %w", chainId, err)
-		}
-
-		t.logger.Info("Transporting AVS stake table to chain",
-			zap.Any("opset", operatorSet),
-			zap.Uint64("chainId", chainId.Uint64()),
-			zap.String("address", addr.String()),
-		)
-		updaterTransactor, err := getOperatorTableUpdaterForChainClient(addr, chain.RPCClient)
-		if err != nil {
-			return fmt.Errorf("failed to get operator table updater transactor for chain %d: %w", chainId, err)
-		}
-		t.logger.Sugar().Debugw("Using operator table updater transactor",
-			zap.Any("opset", operatorSet),
-			zap.Uint64("chainId", chainId.Uint64()),
-			zap.Uint64("referenceBlockHeight", referenceBlockHeight),
-			zap.Uint32("referenceTimestamp", referenceTimestamp),
-			zap.String("root", hexutil.Encode(root[:])),
-			zap.Uint64("opsetIndex", opsetIndex),
-			zap.String("proof", hexutil.Encode(proof)),
-			zap.String("tableInfo", hexutil.Encode(tableInfo)),
-		)
-		tx, err := updaterTransactor.UpdateOperatorTable(
-			txOpts,
-			referenceTimestamp,
-			root,
-			uint32(opsetIndex),
-			proof,
-			tableInfo,
-		)
-		if err != nil {
-			t.logger.Error("Failed to update AVS stake table, skipping chain",
-				zap.String("avsAddress", operatorSet.Avs.String()),
-				zap.Uint64("opsetIndex", opsetIndex),
+		decision := t.config.Policy.Evaluate(policy.Subject{
+			ChainId:     chainId.Uint64(),
+			OperatorSet: &operatorSet,
+			CurveType:   curveType(tableInfo),
+		})
+		if !decision.Allowed {
+			l.Sugar().Infow("Skipping transport for opset denied by policy",
+				zap.Any("opset", operatorSet),
 				zap.Uint64("chainId", chainId.Uint64()),
-				zap.Error(err),
+				zap.String("chainAddress", addresses[i].String()),
+				zap.String("rule", decision.Rule),
 			)
+			t.recordDecision(chainId.Uint64(), operatorSet, false, fmt.Sprintf("denied by policy rule %q", decision.Rule))
 			continue
 		}
-		r, err := t.estimateGasPriceAndLimitAndSendTx(ctx, txOpts.From, tx, chain.RPCClient, "UpdateOperatorTable")
+		dest := policy.Destination{ChainId: chainId.Uint64(), OperatorTableUpdater: addresses[i]}
+		reason := "transport requested"
+		if decision.OnlyIfChanged || t.config.OnlyChangedTables {
+			change, err := t.detectTableChange(ctx, dest, operatorSet, tableInfo)
+			if err != nil {
+				return fmt.Errorf("failed to determine whether table changed for chain %d: %w", chainId, err)
+			}
+			reason = change.Reason
+			if !change.Changed {
+				l.Sugar().Infow("Skipping transport for unchanged opset table",
+					zap.Any("opset", operatorSet),
+					zap.Uint64("chainId", chainId.Uint64()),
+					zap.String("rule", decision.Rule),
+					zap.String("reason", change.Reason),
+				)
+				t.recordDecision(chainId.Uint64(), operatorSet, false, change.Reason)
+				continue
+			}
+		}
+		if err := t.verifySnapshot(ctx, snap); err != nil {
+			return err
+		}
+		transported, err := t.transportAvsStakeTableToChain(ctx, chainId, addresses[i], referenceTimestamp, referenceBlockHeight, operatorSet, root, opsetIndex, proof, tableInfo, decision.MaxGasPrice)
 		if err != nil {
-			t.logger.Error("Failed to ensure transaction evaled for AVS stake table, skipping chain",
-				zap.String("avsAddress", operatorSet.Avs.String()),
-				zap.Uint64("opsetIndex", opsetIndex),
-				zap.Uint64("chainId", chainId.Uint64()),
-				zap.Error(err),
-			)
+			return err
+		}
+		if !transported {
+			t.recordDecision(chainId.Uint64(), operatorSet, false, fmt.Sprintf("%s; transaction failed", reason))
 			continue
 		}
-		t.logger.Info("Successfully transported AVS stake table",
-			zap.Any("opset", operatorSet),
-			zap.Uint32("referenceTimestamp", referenceTimestamp),
-			zap.String("transactionHash", r.TxHash.String()),
+		t.recordDecision(chainId.Uint64(), operatorSet, true, reason)
+		if t.config.ChangeDetector != nil {
+			if err := t.config.ChangeDetector.RecordTableTransported(ctx, dest, operatorSet, tableInfo, referenceTimestamp); err != nil {
+				l.Sugar().Warnw("Failed to record transported table",
+					zap.Any("opset", operatorSet),
+					zap.Uint64("chainId", chainId.Uint64()),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// SignAndTransportAvsStakeTableFromArtifact transports a single operator set's table using a
+// previously persisted distribution artifact instead of a fresh calculation. The artifact's
+// tree is rebuilt and checked against its root, and its reference block is checked to still
+// be canonical (snapshot.ErrReorg otherwise), before anything is sent. As with
+// SignAndTransportAvsStakeTable, the artifact's root must already be confirmed on each
+// destination chain.
+func (t *Transport) SignAndTransportAvsStakeTableFromArtifact(
+	ctx context.Context,
+	artifact *distribution.Artifact,
+	operatorSet distribution.OperatorSet,
+	ignoreChainIds []*big.Int,
+) error {
+	dist, err := artifact.Distribution()
+	if err != nil {
+		return fmt.Errorf("failed to load distribution from artifact: %w", err)
+	}
+	tree, err := artifact.Tree()
+	if err != nil {
+		return fmt.Errorf("failed to load merkle tree from artifact: %w", err)
+	}
+	// Supported chains are read at the latest block rather than pinned to the artifact's block,
+	// which may be old enough to need archive state; the artifact's block must still be canonical.
+	if err := t.verifySnapshot(ctx, artifact.Snapshot()); err != nil {
+		return err
+	}
+	return t.signAndTransportAvsStakeTable(
+		ctx,
+		artifact.ReferenceTimestamp,
+		artifact.BlockNumber,
+		nil,
+		operatorSet,
+		artifact.Root,
+		tree,
+		dist,
+		ignoreChainIds,
+	)
+}
+
+// transportAvsStakeTableToChain submits a single operator table update to one destination chain.
+// Failures to submit the transaction are logged and swallowed so the remaining chains can
+// proceed; any other failure is returned as an error.
+func (t *Transport) transportAvsStakeTableToChain(
+	ctx context.Context,
+	chainId *big.Int,
+	addr common.Address,
+	referenceTimestamp uint32,
+	referenceBlockHeight uint64,
+	operatorSet distribution.OperatorSet,
+	root [32]byte,
+	opsetIndex uint64,
+	proof []byte,
+	tableInfo []byte,
+	maxGasPrice *big.Int,
+) (transported bool, err error) {
+	ctx, span := tracing.Start(ctx, t.tracer, "Transport.TransportAvsStakeTableToChain",
+		attribute.Int64("chainId", int64(chainId.Uint64())),
+		attribute.String("chainAddress", addr.String()),
+		attribute.Int64("opsetId", int64(operatorSet.Id)),
+		attribute.String("opsetAvs", operatorSet.Avs.String()),
+		attribute.Int64("opsetIndex", int64(opsetIndex)),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Bool("transported", transported))
+		tracing.End(span, err)
+	}()
+	l := logger.WithTraceContext(ctx, t.logger)
+
+	// Check the proof with the updater's own inclusion logic; a proof it would reject reverts
+	// on every chain, so it is returned as an error rather than skipping this chain
+	if opsetIndex > math.MaxUint32 {
+		return false, fmt.Errorf("operator set index %d does not fit in uint32", opsetIndex)
+	}
+	if err := distribution.VerifyOperatorSetProof(root, uint32(opsetIndex), distribution.OperatorTableLeafHash(tableInfo), proof); err != nil {
+		return false, fmt.Errorf("operator set proof for %v does not verify against root %s: %w", operatorSet, hexutil.Encode(root[:]), err)
+	}
+
+	// Get transaction options from signer
+	txOpts, err := t.txSigner.GetNoSendTransactOpts(ctx, chainId)
+	if err != nil {
+		return false, fmt.Errorf("failed to get transaction options: %w", err)
+	}
+	chain, err := t.chainManager.GetChainForId(chainId.Uint64())
+	if err != nil {
+		return false, fmt.Errorf("failed to get chain for ID %d: %w", chainId, err)
+	}
+
+	l.Info("Transporting AVS stake table to chain",
+		zap.Any("opset", operatorSet),
+		zap.Uint64("chainId", chainId.Uint64()),
+		zap.String("address", addr.String()),
+	)
+	updaterTransactor, err := getOperatorTableUpdaterForChainClient(addr, chain.RPCClient)
+	if err != nil {
+		return false, fmt.Errorf("failed to get operator table updater transactor for chain %d: %w", chainId, err)
+	}
+	l.Sugar().Debugw("Using operator table updater transactor",
+		zap.Any("opset", operatorSet),
+		zap.Uint64("chainId", chainId.Uint64()),
+		zap.Uint64("referenceBlockHeight", referenceBlockHeight),
+		zap.Uint32("referenceTimestamp", referenceTimestamp),
+		zap.String("root", hexutil.Encode(root[:])),
+		zap.Uint64("opsetIndex", opsetIndex),
+		zap.String("proof", hexutil.Encode(proof)),
+		zap.String("tableInfo", hexutil.Encode(tableInfo)),
+	)
+	tx, err := updaterTransactor.UpdateOperatorTable(
+		txOpts,
+		referenceTimestamp,
+		root,
+		uint32(opsetIndex),
+		proof,
+		tableInfo,
+	)
+	if err != nil {
+		l.Error("Failed to update AVS stake table, skipping chain",
+			zap.String("avsAddress", operatorSet.Avs.String()),
+			zap.Uint64("opsetIndex", opsetIndex),
+			zap.Uint64("chainId", chainId.Uint64()),
+			zap.Error(err),
+		)
+		span.RecordError(err)
+		return false, nil
+	}
+	r, err := t.estimateGasPriceAndLimitAndSendTx(ctx, txOpts.From, tx, chain.RPCClient, "UpdateOperatorTable", maxGasPrice)
+	if err != nil {
+		l.Error("Failed to ensure transaction evaled for AVS stake table, skipping chain",
 			zap.String("avsAddress", operatorSet.Avs.String()),
-			zap.String("root", hexutil.Encode(root[:])),
-			zap.Uint64("blockHeight", referenceBlockHeight),
 			zap.Uint64("opsetIndex", opsetIndex),
 			zap.Uint64("chainId", chainId.Uint64()),
+			zap.Error(err),
 		)
+		span.RecordError(err)
+		return false, nil
+	}
+	l.Info("Successfully transported AVS stake table",
+		zap.Any("opset", operatorSet),
+		zap.Uint32("referenceTimestamp", referenceTimestamp),
+		zap.String("transactionHash", r.TxHash.String()),
+		zap.String("avsAddress", operatorSet.Avs.String()),
+		zap.String("root", hexutil.Encode(root[:])),
+		zap.Uint64("blockHeight", referenceBlockHeight),
+		zap.Uint64("opsetIndex", opsetIndex),
+		zap.Uint64("chainId", chainId.Uint64()),
+	)
+	span.SetAttributes(attribute.String("transactionHash", r.TxHash.String()))
+	return true, nil
+}
+
+// getSupportedChains reads the destination chains from the CrossChainRegistry, at the snapshot's
+// block hash when snap is set and at the latest block otherwise.
+func (t *Transport) getSupportedChains(ctx context.Context, snap *snapshot.Snapshot) ([]*big.Int, []common.Address, error) {
+	callOpts := &bind.CallOpts{Context: ctx}
+	if snap != nil {
+		callOpts = snap.CallOpts(ctx)
+	}
+	chainIds, addresses, err := t.crossChainRegistryCaller.GetSupportedChains(callOpts)
+	if snap != nil {
+		err = snap.CheckError(ctx, t.l1Client, err)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get supported chains: %w", err)
+	}
+	return chainIds, addresses, nil
+}
+
+// SupportedDestinations returns the destination chains registered in the CrossChainRegistry,
+// read at the snapshot's block hash when snap is set and at the latest block otherwise.
+//
+// Parameters:
+//   - ctx: Context for the registry read
+//   - snap: The reference block snapshot, or nil for the latest block
+//
+// Returns:
+//   - []policy.Destination: The destination chains and their OperatorTableUpdater addresses
+//   - error: An error if the registry cannot be read
+func (t *Transport) SupportedDestinations(ctx context.Context, snap *snapshot.Snapshot) ([]policy.Destination, error) {
+	chainIds, addresses, err := t.getSupportedChains(ctx, snap)
+	if err != nil {
+		return nil, err
+	}
+	destinations := make([]policy.Destination, 0, len(chainIds))
+	for i, chainId := range chainIds {
+		destinations = append(destinations, policy.Destination{ChainId: chainId.Uint64(), OperatorTableUpdater: addresses[i]})
+	}
+	return destinations, nil
+}
+
+// verifySnapshot checks that the snapshot's reference block is still canonical on L1. It is a
+// no-op when snap is nil.
+func (t *Transport) verifySnapshot(ctx context.Context, snap *snapshot.Snapshot) error {
+	if snap == nil {
+		return nil
+	}
+	if err := snap.Verify(ctx, t.l1Client); err != nil {
+		return fmt.Errorf("failed to verify reference block: %w", err)
 	}
 	return nil
 }
 
+// detectTableChange consults the configured change detector. Without one, every table is
+// treated as changed so that change-only transport never silently suppresses a transport.
+func (t *Transport) detectTableChange(ctx context.Context, dest policy.Destination, opset distribution.OperatorSet, tableBytes []byte) (policy.TableChange, error) {
+	if t.config.ChangeDetector == nil {
+		t.logger.Sugar().Warnw("Change-only transport requested but no change detector is configured, treating table as changed",
+			zap.Any("opset", opset),
+			zap.Uint64("chainId", dest.ChainId),
+		)
+		return policy.TableChange{Changed: true, Reason: "no change detector configured"}, nil
+	}
+	return t.config.ChangeDetector.DetectTableChange(ctx, dest, opset, tableBytes)
+}
+
+// recordDecision keeps the outcome for an operator table on a destination chain.
+func (t *Transport) recordDecision(chainId uint64, opset distribution.OperatorSet, transported bool, reason string) {
+	t.decisionsMu.Lock()
+	defer t.decisionsMu.Unlock()
+	t.decisions = append(t.decisions, TableDecision{
+		ChainId:     chainId,
+		OperatorSet: opset,
+		Transported: transported,
+		Reason:      reason,
+	})
+}
+
+// TableDecisions returns the decision made for every operator table and destination chain
+// considered so far, in the order they were made.
+//
+// Returns:
+//   - []TableDecision: The decisions
+func (t *Transport) TableDecisions() []TableDecision {
+	t.decisionsMu.Lock()
+	defer t.decisionsMu.Unlock()
+	return append([]TableDecision(nil), t.decisions...)
+}
+
 func (t *Transport) ensureTransactionEvaled(ctx context.Context, rpcClient chainManager.EthClientInterface, tx *types.Transaction, tag string) (*types.Receipt, error) {
 	t.logger.Sugar().Infow("ensureTransactionEvaled entered")
 
@@ -350,6 +761,9 @@ func (t *Transport) ensureTransactionEvaled(ctx context.Context, rpcClient chain
 
 var (
 	FallbackGasTipCap = big.NewInt(15000000000)
+
+	// ErrGasPriceAboveMax is returned when the fee cap for a transaction exceeds the policy's maximum gas price
+	ErrGasPriceAboveMax = errors.New("gas price above policy maximum")
 )
 
 func addGasBuffer(gasLimit uint64) uint64 {
@@ -362,6 +776,7 @@ func (t *Transport) estimateGasPriceAndLimitAndSendTx(
 	tx *types.Transaction,
 	rpcClient chainManager.EthClientInterface,
 	tag string,
+	maxGasPrice *big.Int,
 ) (*types.Receipt, error) {
 
 	gasTipCap, err := rpcClient.SuggestGasTipCap(ctx)
@@ -387,6 +802,9 @@ func (t *Transport) estimateGasPriceAndLimitAndSendTx(
 	overestimatedBasefee := new(big.Int).Div(new(big.Int).Mul(header.BaseFee, big.NewInt(3)), big.NewInt(2))
 
 	gasFeeCap := new(big.Int).Add(overestimatedBasefee, gasTipCap)
+	if maxGasPrice != nil && gasFeeCap.Cmp(maxGasPrice) > 0 {
+		return nil, fmt.Errorf("%w: gasFeeCap=%s max=%s (%s)", ErrGasPriceAboveMax, gasFeeCap, maxGasPrice, tag)
+	}
 
 	// The estimated gas limits performed by RawTransact fail semi-regularly
 	// with out of gas exceptions. To remedy this we extract the internal calls
@@ -433,7 +851,30 @@ func (t *Transport) estimateGasPriceAndLimitAndSendTx(
 	return receipt, err
 }
 
-func (t *Transport) generateOperatorSetProof(tree *merkletree.MerkleTree, dist *distribution.Distribution, operatorSet distribution.OperatorSet) ([]byte, uint64, error) {
+// rootPolicySubjects returns the policy subjects of a distribution's operator sets, against which
+// the global table root is evaluated. A nil distribution has none.
+func rootPolicySubjects(dist *distribution.Distribution) []policy.Subject {
+	if dist == nil {
+		return nil
+	}
+	entries := dist.Entries()
+	subjects := make([]policy.Subject, len(entries))
+	for i, entry := range entries {
+		subjects[i] = policy.Subject{OperatorSet: &entry.OperatorSet, CurveType: curveType(entry.TableData)}
+	}
+	return subjects
+}
+
+// curveType returns the curve type of operator table bytes, or CurveTypeNone if they cannot be decoded.
+func curveType(tableBytes []byte) operatorTable.CurveType {
+	table, err := operatorTable.Decode(tableBytes)
+	if err != nil {
+		return operatorTable.CurveTypeNone
+	}
+	return table.CurveType
+}
+
+func (t *Transport) generateOperatorSetProof(tree *distribution.MerkleTree, dist *distribution.Snapshot, operatorSet distribution.OperatorSet) ([]byte, uint64, error) {
 	t.logger.Sugar().Infow("Generating proof for operator set",
 		zap.Any("operatorSet", operatorSet),
 	)
@@ -454,15 +895,10 @@ func (t *Transport) generateOperatorSetProof(tree *merkletree.MerkleTree, dist *
 		zap.ByteString("tableData", tableData),
 	)
 
-	proof, err := tree.GenerateProofWithIndex(opsetIndex, 0)
+	proofBytes, err := tree.Proof(opsetIndex)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to generate proof for operator set %v: %w", operatorSet, err)
 	}
-	t.logger.Sugar().Infow("Generated proof for operator set",
-		zap.Any("proof", proof),
-	)
-
-	proofBytes := flattenHashes(proof.Hashes)
 
 	t.logger.Info("Successfully generated proof for operator set",
 		zap.Any("operatorSet", operatorSet),
@@ -524,11 +960,3 @@ func (t *Transport) generateMessageHashSignature(messageHash [32]byte) (*IOperat
 		Y: new(big.Int).SetBytes(g1Bytes[32:64]),
 	}, nil
 }
-
-func flattenHashes(hashes [][]byte) []byte {
-	result := make([]byte, 0)
-	for i := 0; i < len(hashes); i++ {
-		result = append(result, hashes[i]...)
-	}
-	return result
-}
@@ -0,0 +1,444 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/crypto-libs/pkg/bn254"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IOperatorTableUpdater"
+	"github.com/Layr-Labs/multichain-go/pkg/blsSigner"
+	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
+	"github.com/Layr-Labs/multichain-go/pkg/changeDetector"
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/policy"
+	"github.com/Layr-Labs/multichain-go/pkg/txSigner"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testTxPrivateKey = "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+var (
+	testRegistry = common.HexToAddress("0x0000000000000000000000000000000000c0ffee")
+	testAvsA     = common.HexToAddress("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	testAvsB     = common.HexToAddress("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+	testBaseFee  = big.NewInt(10_000_000_000)
+)
+
+// sentTx is a transaction a testChain received, decoded against the OperatorTableUpdater ABI.
+type sentTx struct {
+	method     string
+	tableBytes []byte
+}
+
+// testChain is a destination chain whose OperatorTableUpdater answers reads and accepts every
+// transaction, recording what was sent.
+type testChain struct {
+	id      uint64
+	updater common.Address
+	client  *chainManager.MockEthClientInterface
+
+	mu   sync.Mutex
+	sent []sentTx
+}
+
+func newTestChain(t *testing.T, id uint64) *testChain {
+	updaterAbi, err := IOperatorTableUpdater.IOperatorTableUpdaterMetaData.GetAbi()
+	require.NoError(t, err)
+
+	c := &testChain{
+		id:      id,
+		updater: common.BigToAddress(new(big.Int).SetUint64(0x1000 + id)),
+		client:  chainManager.NewMockEthClientInterface(t),
+	}
+	c.client.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+			method, err := updaterAbi.MethodById(msg.Data[:4])
+			if err != nil {
+				return nil, err
+			}
+			switch method.Name {
+			case "getGlobalTableUpdateMessageHash", "getGlobalTableUpdateSignableDigest":
+				return method.Outputs.Pack([32]byte{0x01})
+			case "getGeneratorReferenceTimestamp":
+				return method.Outputs.Pack(uint32(1))
+			}
+			return nil, fmt.Errorf("unexpected call to %s", method.Name)
+		}).Maybe()
+	c.client.On("HeaderByNumber", mock.Anything, (*big.Int)(nil)).
+		Return(&types.Header{Number: big.NewInt(1), BaseFee: testBaseFee}, nil).Maybe()
+	c.client.On("SuggestGasTipCap", mock.Anything).Return(big.NewInt(1_000_000_000), nil).Maybe()
+	c.client.On("PendingNonceAt", mock.Anything, mock.Anything).Return(uint64(0), nil).Maybe()
+	c.client.On("PendingCodeAt", mock.Anything, mock.Anything).Return([]byte{0x01}, nil).Maybe()
+	c.client.On("EstimateGas", mock.Anything, mock.Anything).Return(uint64(100_000), nil).Maybe()
+	c.client.On("SendTransaction", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, tx *types.Transaction) error {
+			method, err := updaterAbi.MethodById(tx.Data()[:4])
+			if err != nil {
+				return err
+			}
+			sent := sentTx{method: method.Name}
+			if method.Name == "updateOperatorTable" {
+				args, err := method.Inputs.Unpack(tx.Data()[4:])
+				if err != nil {
+					return err
+				}
+				sent.tableBytes = args[len(args)-1].([]byte)
+			}
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.sent = append(c.sent, sent)
+			return nil
+		}).Maybe()
+	c.client.On("TransactionReceipt", mock.Anything, mock.Anything).
+		Return(&types.Receipt{Status: types.ReceiptStatusSuccessful}, nil).Maybe()
+	return c
+}
+
+func (c *testChain) sentTxs() []sentTx {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]sentTx(nil), c.sent...)
+}
+
+// newTestTransport creates a Transport whose CrossChainRegistry lists the given chains.
+func newTestTransport(t *testing.T, cfg *TransportConfig, chains ...*testChain) *Transport {
+	registryAbi, err := ICrossChainRegistry.ICrossChainRegistryMetaData.GetAbi()
+	require.NoError(t, err)
+	chainIds := make([]*big.Int, len(chains))
+	updaters := make([]common.Address, len(chains))
+	for i, c := range chains {
+		chainIds[i] = new(big.Int).SetUint64(c.id)
+		updaters[i] = c.updater
+	}
+	supportedChains, err := registryAbi.Methods["getSupportedChains"].Outputs.Pack(chainIds, updaters)
+	require.NoError(t, err)
+
+	l1Client := chainManager.NewMockEthClientInterface(t)
+	l1Client.On("CallContract", mock.Anything, mock.MatchedBy(func(msg ethereum.CallMsg) bool {
+		return *msg.To == testRegistry
+	}), mock.Anything).Return(supportedChains, nil).Maybe()
+
+	cm := chainManager.NewMockIChainManager(t)
+	for _, c := range chains {
+		cm.On("GetChainForId", c.id).Return(&chainManager.Chain{RPCClient: c.client}, nil).Maybe()
+	}
+
+	blsKey, _, err := bn254.GenerateKeyPair()
+	require.NoError(t, err)
+	bls, err := blsSigner.NewInMemoryBLSSigner(blsKey)
+	require.NoError(t, err)
+	txSig, err := txSigner.NewPrivateKeySigner(testTxPrivateKey)
+	require.NoError(t, err)
+
+	cfg.L1CrossChainRegistryAddress = testRegistry
+	transport, err := NewTransport(cfg, l1Client, bls, txSig, cm, zap.NewNop())
+	require.NoError(t, err)
+	return transport
+}
+
+// encodeTestTable encodes an ECDSA operator table for opset with a single operator of the given weight.
+func encodeTestTable(t *testing.T, opset distribution.OperatorSet, maxStaleness uint32, weight int64) []byte {
+	tableBytes, err := operatorTable.Encode(&operatorTable.OperatorTable{
+		OperatorSet: operatorTable.OperatorSet{Avs: opset.Avs, Id: opset.Id},
+		CurveType:   operatorTable.CurveTypeECDSA,
+		Config:      operatorTable.OperatorSetConfig{MaxStalenessPeriod: maxStaleness},
+		ECDSA: []operatorTable.ECDSAOperatorInfo{
+			{Pubkey: common.HexToAddress("0x01"), Weights: []*big.Int{big.NewInt(weight)}},
+		},
+	})
+	require.NoError(t, err)
+	return tableBytes
+}
+
+// newTestDistribution builds a distribution of the operator sets with one table each, and its tree.
+func newTestDistribution(t *testing.T, opsets []distribution.OperatorSet, tables [][]byte) (*distribution.Distribution, *distribution.MerkleTree) {
+	dist := distribution.NewDistributionWithOperatorSets(opsets)
+	for i, opset := range opsets {
+		require.NoError(t, dist.SetTableData(opset, tables[i]))
+	}
+	tree, err := distribution.NewMerkleTreeFromDistribution(dist)
+	require.NoError(t, err)
+	return dist, tree
+}
+
+func parsePolicy(t *testing.T, doc string) *policy.Policy {
+	p, err := policy.Parse([]byte(doc))
+	require.NoError(t, err)
+	return p
+}
+
+func TestSignAndTransportAvsStakeTable_Policy(t *testing.T) {
+	chain1, chain2 := newTestChain(t, 1), newTestChain(t, 2)
+	transport := newTestTransport(t, &TransportConfig{Policy: parsePolicy(t, `
+default: allow
+rules:
+  - name: no-chain-2
+    effect: deny
+    chainIds: [2]
+  - name: no-avs-b
+    effect: deny
+    avs: ["0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"]
+`)}, chain1, chain2)
+
+	opsetA := distribution.OperatorSet{Id: 1, Avs: testAvsA}
+	opsetB := distribution.OperatorSet{Id: 2, Avs: testAvsB}
+	tableA, tableB := encodeTestTable(t, opsetA, 0, 1), encodeTestTable(t, opsetB, 0, 2)
+	dist, tree := newTestDistribution(t, []distribution.OperatorSet{opsetA, opsetB}, [][]byte{tableA, tableB})
+
+	for _, opset := range []distribution.OperatorSet{opsetA, opsetB} {
+		err := transport.SignAndTransportAvsStakeTable(context.Background(), 100, 10, opset, tree.Root(), tree, dist, nil)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []sentTx{{method: "updateOperatorTable", tableBytes: tableA}}, chain1.sentTxs())
+	assert.Empty(t, chain2.sentTxs())
+	chain2.client.AssertNotCalled(t, "SendTransaction", mock.Anything, mock.Anything)
+	assert.Equal(t, []TableDecision{
+		{ChainId: 1, OperatorSet: opsetA, Transported: true, Reason: "transport requested"},
+		{ChainId: 2, OperatorSet: opsetA, Reason: `denied by policy rule "no-chain-2"`},
+		{ChainId: 1, OperatorSet: opsetB, Reason: `denied by policy rule "no-avs-b"`},
+		{ChainId: 2, OperatorSet: opsetB, Reason: `denied by policy rule "no-chain-2"`},
+	}, transport.TableDecisions())
+}
+
+func TestSignAndTransportGlobalTableRoot_Policy(t *testing.T) {
+	chain1, chain2 := newTestChain(t, 1), newTestChain(t, 2)
+	transport := newTestTransport(t, &TransportConfig{Policy: parsePolicy(t, `
+default: deny
+rules:
+  - name: avs-a-on-chain-1
+    effect: allow
+    chainIds: [1]
+    avs: ["0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"]
+`)}, chain1, chain2)
+
+	opsetA := distribution.OperatorSet{Id: 1, Avs: testAvsA}
+	opsetB := distribution.OperatorSet{Id: 2, Avs: testAvsB}
+	dist, tree := newTestDistribution(t, []distribution.OperatorSet{opsetA, opsetB},
+		[][]byte{encodeTestTable(t, opsetA, 0, 1), encodeTestTable(t, opsetB, 0, 2)})
+
+	// Evaluated for the chain alone, no rule allows the root and the default denies it
+	err := transport.SignAndTransportGlobalTableRoot(context.Background(), tree.Root(), 100, 10, nil)
+	require.NoError(t, err)
+	assert.Empty(t, chain1.sentTxs())
+	assert.Empty(t, chain2.sentTxs())
+
+	// Through its operator sets, the root is needed on chain 1, where avs A is allowed
+	err = transport.SignAndTransportGlobalTableRootForDistribution(context.Background(), tree.Root(), dist, 100, 10, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []sentTx{{method: "confirmGlobalTableRoot"}}, chain1.sentTxs())
+	assert.Empty(t, chain2.sentTxs())
+	chain2.client.AssertNotCalled(t, "SendTransaction", mock.Anything, mock.Anything)
+}
+
+func TestSignAndTransportAvsStakeTable_GasPriceAboveMax(t *testing.T) {
+	chain1, chain2 := newTestChain(t, 1), newTestChain(t, 2)
+	// The fee cap is 1.5 times the 10 gwei base fee plus the 1 gwei tip, 16 gwei
+	transport := newTestTransport(t, &TransportConfig{Policy: parsePolicy(t, `
+default: allow
+rules:
+  - name: cheap-chain-1
+    effect: allow
+    chainIds: [1]
+    maxGasPriceWei: 15000000000
+  - name: chain-2
+    effect: allow
+    chainIds: [2]
+    maxGasPriceWei: 20000000000
+`)}, chain1, chain2)
+
+	opset := distribution.OperatorSet{Id: 1, Avs: testAvsA}
+	table := encodeTestTable(t, opset, 0, 1)
+	dist, tree := newTestDistribution(t, []distribution.OperatorSet{opset}, [][]byte{table})
+
+	err := transport.SignAndTransportAvsStakeTable(context.Background(), 100, 10, opset, tree.Root(), tree, dist, nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, chain1.sentTxs())
+	chain1.client.AssertNotCalled(t, "SendTransaction", mock.Anything, mock.Anything)
+	assert.Equal(t, []sentTx{{method: "updateOperatorTable", tableBytes: table}}, chain2.sentTxs())
+	assert.Equal(t, []TableDecision{
+		{ChainId: 1, OperatorSet: opset, Reason: "transport requested; transaction failed"},
+		{ChainId: 2, OperatorSet: opset, Transported: true, Reason: "transport requested"},
+	}, transport.TableDecisions())
+}
+
+func TestEstimateGasPriceAndLimitAndSendTx_GasPriceAboveMax(t *testing.T) {
+	chain := newTestChain(t, 1)
+	transport := newTestTransport(t, &TransportConfig{}, chain)
+	tx := types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(1), To: &chain.updater})
+
+	_, err := transport.estimateGasPriceAndLimitAndSendTx(context.Background(), common.Address{}, tx, chain.client, "test", big.NewInt(15_000_000_000))
+	assert.ErrorIs(t, err, ErrGasPriceAboveMax)
+	assert.Empty(t, chain.sentTxs())
+}
+
+// memoryStore is a changeDetector.Store kept in memory.
+type memoryStore struct {
+	records map[uint64]map[distribution.OperatorSet]changeDetector.Record
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: map[uint64]map[distribution.OperatorSet]changeDetector.Record{}}
+}
+
+func (s *memoryStore) Get(chainId uint64, opset distribution.OperatorSet) (*changeDetector.Record, error) {
+	record, ok := s.records[chainId][opset]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (s *memoryStore) Put(chainId uint64, opset distribution.OperatorSet, record changeDetector.Record) error {
+	if s.records[chainId] == nil {
+		s.records[chainId] = map[distribution.OperatorSet]changeDetector.Record{}
+	}
+	s.records[chainId][opset] = record
+	return nil
+}
+
+// fakeDestinationReader returns fixed records for the destination's tables.
+type fakeDestinationReader struct {
+	records map[distribution.OperatorSet]*changeDetector.Record
+}
+
+func (r *fakeDestinationReader) ReadTable(
+	_ context.Context,
+	_ policy.Destination,
+	opset distribution.OperatorSet,
+	_ operatorTable.CurveType,
+) (*changeDetector.Record, error) {
+	return r.records[opset], nil
+}
+
+func TestSignAndTransportAvsStakeTable_OnlyChangedTables(t *testing.T) {
+	const maxStaleness = 10000
+	now := time.Unix(1700010000, 0)
+	referenceTimestamp := uint32(now.Unix())
+
+	opsets := []distribution.OperatorSet{
+		{Id: 1, Avs: testAvsA}, // unchanged and fresh
+		{Id: 2, Avs: testAvsA}, // unchanged but past the staleness threshold
+		{Id: 3, Avs: testAvsA}, // changed on the destination
+		{Id: 4, Avs: testAvsA}, // never sent
+	}
+	tables := make([][]byte, len(opsets))
+	for i, opset := range opsets {
+		tables[i] = encodeTestTable(t, opset, maxStaleness, int64(i+1))
+	}
+	dist, tree := newTestDistribution(t, opsets, tables)
+
+	store := newMemoryStore()
+	require.NoError(t, store.Put(1, opsets[0], changeDetector.Record{
+		LeafHash:           changeDetector.LeafHash(tables[0]),
+		ReferenceTimestamp: referenceTimestamp - 1000,
+	}))
+	require.NoError(t, store.Put(1, opsets[1], changeDetector.Record{
+		LeafHash:           changeDetector.LeafHash(tables[1]),
+		ReferenceTimestamp: referenceTimestamp - 9000,
+	}))
+	reader := &fakeDestinationReader{records: map[distribution.OperatorSet]*changeDetector.Record{
+		opsets[2]: {LeafHash: changeDetector.LeafHash(tables[0]), ReferenceTimestamp: referenceTimestamp - 500},
+	}}
+	detector, err := changeDetector.NewDetector(&changeDetector.Config{
+		Store:  store,
+		Reader: reader,
+		Now:    func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	chain := newTestChain(t, 1)
+	transport := newTestTransport(t, &TransportConfig{ChangeDetector: detector, OnlyChangedTables: true}, chain)
+	for _, opset := range opsets {
+		err := transport.SignAndTransportAvsStakeTable(context.Background(), referenceTimestamp, 10, opset, tree.Root(), tree, dist, nil)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []sentTx{
+		{method: "updateOperatorTable", tableBytes: tables[1]},
+		{method: "updateOperatorTable", tableBytes: tables[2]},
+		{method: "updateOperatorTable", tableBytes: tables[3]},
+	}, chain.sentTxs())
+	assert.Equal(t, []TableDecision{
+		{ChainId: 1, OperatorSet: opsets[0], Reason: fmt.Sprintf("table unchanged since reference timestamp %d (local store)", referenceTimestamp-1000)},
+		{ChainId: 1, OperatorSet: opsets[1], Transported: true, Reason: "table approaching max staleness (9000s of 10000s elapsed)"},
+		{ChainId: 1, OperatorSet: opsets[2], Transported: true, Reason: fmt.Sprintf("table changed since reference timestamp %d (destination state)", referenceTimestamp-500)},
+		{ChainId: 1, OperatorSet: opsets[3], Transported: true, Reason: "no previous table on destination"},
+	}, transport.TableDecisions())
+
+	// Sent tables are recorded, so the next run skips them
+	for i, opset := range opsets {
+		record, err := store.Get(1, opset)
+		require.NoError(t, err)
+		require.NotNil(t, record)
+		assert.Equal(t, changeDetector.LeafHash(tables[i]), record.LeafHash)
+		if i > 0 {
+			assert.Equal(t, referenceTimestamp, record.ReferenceTimestamp)
+		}
+	}
+}
+
+func TestSignAndTransportAvsStakeTable_OnlyIfChangedRule(t *testing.T) {
+	opsetA := distribution.OperatorSet{Id: 1, Avs: testAvsA}
+	opsetB := distribution.OperatorSet{Id: 2, Avs: testAvsB}
+	tableA, tableB := encodeTestTable(t, opsetA, 0, 1), encodeTestTable(t, opsetB, 0, 2)
+	dist, tree := newTestDistribution(t, []distribution.OperatorSet{opsetA, opsetB}, [][]byte{tableA, tableB})
+
+	// Both tables are unchanged on the destination, but only avs A's rule asks for changes only
+	store := newMemoryStore()
+	for _, opset := range []distribution.OperatorSet{opsetA, opsetB} {
+		tableBytes, _ := dist.GetTableData(opset)
+		require.NoError(t, store.Put(1, opset, changeDetector.Record{LeafHash: changeDetector.LeafHash(tableBytes), ReferenceTimestamp: 50}))
+	}
+	detector, err := changeDetector.NewDetector(&changeDetector.Config{Store: store})
+	require.NoError(t, err)
+
+	chain := newTestChain(t, 1)
+	transport := newTestTransport(t, &TransportConfig{ChangeDetector: detector, Policy: parsePolicy(t, `
+default: allow
+rules:
+  - name: avs-a-changes
+    effect: allow
+    avs: ["0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"]
+    onlyIfChanged: true
+`)}, chain)
+	for _, opset := range []distribution.OperatorSet{opsetA, opsetB} {
+		err := transport.SignAndTransportAvsStakeTable(context.Background(), 100, 10, opset, tree.Root(), tree, dist, nil)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []sentTx{{method: "updateOperatorTable", tableBytes: tableB}}, chain.sentTxs())
+	assert.Equal(t, []TableDecision{
+		{ChainId: 1, OperatorSet: opsetA, Reason: "table unchanged since reference timestamp 50 (local store)"},
+		{ChainId: 1, OperatorSet: opsetB, Transported: true, Reason: "transport requested"},
+	}, transport.TableDecisions())
+}
+
+func TestSignAndTransportAvsStakeTable_OnlyChangedWithoutDetector(t *testing.T) {
+	opset := distribution.OperatorSet{Id: 1, Avs: testAvsA}
+	table := encodeTestTable(t, opset, 0, 1)
+	dist, tree := newTestDistribution(t, []distribution.OperatorSet{opset}, [][]byte{table})
+
+	chain := newTestChain(t, 1)
+	transport := newTestTransport(t, &TransportConfig{OnlyChangedTables: true}, chain)
+	err := transport.SignAndTransportAvsStakeTable(context.Background(), 100, 10, opset, tree.Root(), tree, dist, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []sentTx{{method: "updateOperatorTable", tableBytes: table}}, chain.sentTxs())
+	assert.Equal(t, []TableDecision{
+		{ChainId: 1, OperatorSet: opset, Transported: true, Reason: "no change detector configured"},
+	}, transport.TableDecisions())
+}
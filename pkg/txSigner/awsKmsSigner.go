@@ -127,24 +127,48 @@ func (k *AWSKMSSigner) SignerFn(chainID *big.Int) bind2.SignerFn {
 
 		txHashBytes := signer.Hash(tx).Bytes()
 
-		rBytes, sBytes, err := k.getSignatureFromKms(txHashBytes)
+		signature, err := k.signDigest(context.Background(), pubKeyBytes, txHashBytes)
 		if err != nil {
 			return nil, err
 		}
 
-		// Adjust S value from signature according to Ethereum standard
-		sBigInt := new(big.Int).SetBytes(sBytes)
-		if sBigInt.Cmp(secp256k1HalfN) > 0 {
-			sBytes = new(big.Int).Sub(secp256k1N, sBigInt).Bytes()
-		}
+		return tx.WithSignature(signer, signature)
+	}
+}
 
-		signature, err := k.getEthereumSignature(pubKeyBytes, txHashBytes, rBytes, sBytes)
-		if err != nil {
-			return nil, err
-		}
+// SignHash signs a digest with the KMS key.
+// This method implements the IHashSigner interface.
+//
+// Parameters:
+//   - ctx: Context for the KMS request
+//   - hash: The digest to sign
+//
+// Returns:
+//   - []byte: The 65-byte [R || S || V] signature
+//   - error: An error if KMS fails to sign or the signature does not recover to the key
+func (k *AWSKMSSigner) SignHash(ctx context.Context, hash [32]byte) ([]byte, error) {
+	pubKeyBytes := secp256k1.S256().Marshal(k.publicKey.X, k.publicKey.Y)
+	signature, err := k.signDigest(ctx, pubKeyBytes, hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign hash: %w", err)
+	}
+	return signature, nil
+}
 
-		return tx.WithSignature(signer, signature)
+// signDigest signs a digest with KMS and converts the signature to Ethereum's [R || S || V] form.
+func (k *AWSKMSSigner) signDigest(ctx context.Context, pubKeyBytes []byte, digest []byte) ([]byte, error) {
+	rBytes, sBytes, err := k.getSignatureFromKms(ctx, digest)
+	if err != nil {
+		return nil, err
 	}
+
+	// Adjust S value from signature according to Ethereum standard
+	sBigInt := new(big.Int).SetBytes(sBytes)
+	if sBigInt.Cmp(secp256k1HalfN) > 0 {
+		sBytes = new(big.Int).Sub(secp256k1N, sBigInt).Bytes()
+	}
+
+	return k.getEthereumSignature(pubKeyBytes, digest, rBytes, sBytes)
 }
 
 func (k *AWSKMSSigner) getPublicKeyDerBytesFromKMS() ([]byte, error) {
@@ -176,7 +200,7 @@ func (k *AWSKMSSigner) GetPubKey() (*ecdsa.PublicKey, error) {
 	return pubkey, nil
 }
 
-func (k *AWSKMSSigner) getSignatureFromKms(txHashBytes []byte) ([]byte, []byte, error) {
+func (k *AWSKMSSigner) getSignatureFromKms(ctx context.Context, txHashBytes []byte) ([]byte, []byte, error) {
 	signInput := &kms.SignInput{
 		KeyId:            aws.String(k.keyID),
 		SigningAlgorithm: aws.String("ECDSA_SHA_256"),
@@ -184,7 +208,7 @@ func (k *AWSKMSSigner) getSignatureFromKms(txHashBytes []byte) ([]byte, []byte,
 		Message:          txHashBytes,
 	}
 
-	signOutput, err := k.kmsClient.Sign(signInput)
+	signOutput, err := k.kmsClient.SignWithContext(ctx, signInput)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -86,3 +86,21 @@ func (p *PrivateKeySigner) GetNoSendTransactOpts(ctx context.Context, chainID *b
 func (p *PrivateKeySigner) GetAddress() (common.Address, error) {
 	return p.address, nil
 }
+
+// SignHash signs a digest with the private key.
+// This method implements the IHashSigner interface.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - hash: The digest to sign
+//
+// Returns:
+//   - []byte: The 65-byte [R || S || V] signature
+//   - error: An error if signing fails
+func (p *PrivateKeySigner) SignHash(_ context.Context, hash [32]byte) ([]byte, error) {
+	signature, err := crypto.Sign(hash[:], p.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign hash: %w", err)
+	}
+	return signature, nil
+}
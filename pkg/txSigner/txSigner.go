@@ -38,3 +38,27 @@ type ITransactionSigner interface {
 	//   - error: An error if the address cannot be determined
 	GetAddress() (common.Address, error)
 }
+
+// IHashSigner defines the interface for signing 32-byte digests with an Ethereum key, such
+// as certificate digests that contracts check with ecrecover. Both PrivateKeySigner and
+// AWSKMSSigner implement it, so the same key backends sign transactions and certificates.
+type IHashSigner interface {
+	// SignHash signs a digest without any prefix.
+	//
+	// Parameters:
+	//   - ctx: Context for the operation
+	//   - hash: The digest to sign
+	//
+	// Returns:
+	//   - []byte: The 65-byte [R || S || V] signature, with V 0 or 1 and S in the lower half
+	//     of the curve order, as crypto.Sign returns it
+	//   - error: An error if the digest cannot be signed
+	SignHash(ctx context.Context, hash [32]byte) ([]byte, error)
+
+	// GetAddress returns the Ethereum address associated with this signer.
+	//
+	// Returns:
+	//   - common.Address: The Ethereum address of the signer
+	//   - error: An error if the address cannot be determined
+	GetAddress() (common.Address, error)
+}
@@ -0,0 +1,74 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IOperatorTableUpdater"
+	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
+	"github.com/Layr-Labs/multichain-go/pkg/changeDetector"
+	"github.com/Layr-Labs/multichain-go/pkg/policy"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ChainReader is a DestinationReader that reads roots from each destination's
+// OperatorTableUpdater and rebuilds tables from its certificate verifiers.
+type ChainReader struct {
+	*changeDetector.ChainReader
+	chainManager chainManager.IChainManager
+}
+
+var _ DestinationReader = (*ChainReader)(nil)
+
+// NewChainReader creates a ChainReader.
+//
+// Parameters:
+//   - cm: The chain manager holding a client for every destination chain
+//
+// Returns:
+//   - *ChainReader: The reader
+func NewChainReader(cm chainManager.IChainManager) *ChainReader {
+	return &ChainReader{
+		ChainReader:  changeDetector.NewChainReader(cm),
+		chainManager: cm,
+	}
+}
+
+// GlobalTableRoot implements DestinationReader.
+func (r *ChainReader) GlobalTableRoot(ctx context.Context, dest policy.Destination, referenceTimestamp uint32) (common.Hash, error) {
+	updater, err := r.updater(dest)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	root, err := updater.GetGlobalTableRootByTimestamp(&bind.CallOpts{Context: ctx}, referenceTimestamp)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get global table root for reference timestamp %d: %w", referenceTimestamp, err)
+	}
+	return common.Hash(root), nil
+}
+
+// LatestReferenceTimestamp implements DestinationReader.
+func (r *ChainReader) LatestReferenceTimestamp(ctx context.Context, dest policy.Destination) (uint32, error) {
+	updater, err := r.updater(dest)
+	if err != nil {
+		return 0, err
+	}
+	latest, err := updater.GetLatestReferenceTimestamp(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest reference timestamp: %w", err)
+	}
+	return latest, nil
+}
+
+func (r *ChainReader) updater(dest policy.Destination) (*IOperatorTableUpdater.IOperatorTableUpdaterCaller, error) {
+	chain, err := r.chainManager.GetChainForId(dest.ChainId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain for ID %d: %w", dest.ChainId, err)
+	}
+	updater, err := IOperatorTableUpdater.NewIOperatorTableUpdaterCaller(dest.OperatorTableUpdater, chain.RPCClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind OperatorTableUpdater: %w", err)
+	}
+	return updater, nil
+}
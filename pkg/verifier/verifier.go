@@ -0,0 +1,358 @@
+// Package verifier checks, independently of the transporter that sent them, that destination
+// chains hold the global table root and operator tables calculated on L1 for a reference
+// block. It is a watchdog against faulty generators and partially completed transports.
+package verifier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Layr-Labs/multichain-go/pkg/changeDetector"
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/policy"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Status is the outcome of a single check.
+type Status string
+
+const (
+	// StatusMatch means the destination holds the expected root or table
+	StatusMatch Status = "match"
+	// StatusMismatch means the destination holds a different root or table, or none at all
+	StatusMismatch Status = "mismatch"
+	// StatusSkipped means the policy does not transport the table to the destination
+	StatusSkipped Status = "skipped"
+	// StatusUnknown means the destination's table at the reference timestamp cannot be read,
+	// because the destination has since moved on and no table was sent at that timestamp
+	StatusUnknown Status = "unknown"
+	// StatusError means the destination could not be read
+	StatusError Status = "error"
+)
+
+// DestinationReader reads the roots and operator tables a destination chain holds.
+type DestinationReader interface {
+	// GlobalTableRoot returns the global table root confirmed for referenceTimestamp, or the
+	// zero hash if none was confirmed
+	GlobalTableRoot(ctx context.Context, dest policy.Destination, referenceTimestamp uint32) (common.Hash, error)
+	// LatestReferenceTimestamp returns the reference timestamp of the latest confirmed root
+	LatestReferenceTimestamp(ctx context.Context, dest policy.Destination) (uint32, error)
+	// ReadTable returns the destination's latest table for the operator set, or nil if it has none
+	ReadTable(ctx context.Context, dest policy.Destination, opset distribution.OperatorSet, curveType operatorTable.CurveType) (*changeDetector.Record, error)
+	// ReadTableAt returns the table the destination was sent at exactly referenceTimestamp, or
+	// nil if none was. Its operator set config may be the current one rather than the one sent.
+	ReadTableAt(ctx context.Context, dest policy.Destination, opset distribution.OperatorSet, curveType operatorTable.CurveType, referenceTimestamp uint32) (*operatorTable.OperatorTable, error)
+}
+
+// Config holds the configuration for a Verifier.
+type Config struct {
+	// Reader reads each destination's roots and tables
+	Reader DestinationReader
+	// Policy, when set, marks operator tables it denies for a destination as skipped
+	Policy *policy.Policy
+}
+
+// Report is the outcome of verifying every destination chain against a calculated distribution.
+type Report struct {
+	// BlockNumber is the L1 reference block number
+	BlockNumber uint64 `json:"blockNumber"`
+	// BlockHash is the L1 reference block hash
+	BlockHash common.Hash `json:"blockHash"`
+	// ReferenceTimestamp is the reference timestamp the roots were confirmed for
+	ReferenceTimestamp uint32 `json:"referenceTimestamp"`
+	// Root is the global table root calculated on L1
+	Root common.Hash `json:"root"`
+	// Chains has one result per destination chain, in registry order
+	Chains []ChainResult `json:"chains"`
+}
+
+// OK reports whether every check matched, was skipped or could not be determined.
+func (r *Report) OK() bool {
+	return r.FailureCount() == 0
+}
+
+// FailureCount returns the number of root and operator table checks that mismatched or errored.
+//
+// Returns:
+//   - int: The number of failed checks
+func (r *Report) FailureCount() int {
+	failures := 0
+	for _, chain := range r.Chains {
+		if chain.RootStatus.failed() {
+			failures++
+		}
+		for _, opset := range chain.OperatorSets {
+			if opset.Status.failed() {
+				failures++
+			}
+		}
+	}
+	return failures
+}
+
+func (s Status) failed() bool {
+	return s == StatusMismatch || s == StatusError
+}
+
+// ChainResult is the outcome of verifying one destination chain.
+type ChainResult struct {
+	// ChainId is the destination chain ID
+	ChainId uint64 `json:"chainId"`
+	// OperatorTableUpdater is the destination's OperatorTableUpdater address
+	OperatorTableUpdater common.Address `json:"operatorTableUpdater"`
+	// ConfirmedRoot is the root the destination confirmed for the reference timestamp
+	ConfirmedRoot common.Hash `json:"confirmedRoot"`
+	// RootStatus is the outcome of the root check
+	RootStatus Status `json:"rootStatus"`
+	// RootReason explains a root mismatch or error
+	RootReason string `json:"rootReason,omitempty"`
+	// OperatorSets has one result per operator set, in leaf order
+	OperatorSets []OperatorSetResult `json:"operatorSets"`
+}
+
+// OperatorSetResult is the outcome of verifying one operator table on a destination chain.
+type OperatorSetResult struct {
+	Avs common.Address `json:"avs"`
+	Id  uint32         `json:"id"`
+	// CurveType is the operator set's key type
+	CurveType operatorTable.CurveType `json:"curveType"`
+	// Status is the outcome of the check
+	Status Status `json:"status"`
+	// ExpectedLeafHash is the leaf hash of the table calculated on L1
+	ExpectedLeafHash common.Hash `json:"expectedLeafHash"`
+	// DestinationLeafHash is the leaf hash of the destination's table, or zero if it has none.
+	// For a table read at a past reference timestamp, it is calculated with the L1 operator set
+	// config, since the destination does not keep the config's history.
+	DestinationLeafHash common.Hash `json:"destinationLeafHash"`
+	// DestinationReferenceTimestamp is the reference timestamp of the destination's table
+	DestinationReferenceTimestamp uint32 `json:"destinationReferenceTimestamp"`
+	// Reason explains the status
+	Reason string `json:"reason,omitempty"`
+}
+
+// Verifier compares destination chains against a calculated distribution.
+type Verifier struct {
+	reader DestinationReader
+	policy *policy.Policy
+}
+
+// NewVerifier creates a new Verifier.
+//
+// Parameters:
+//   - cfg: The verifier configuration
+//
+// Returns:
+//   - *Verifier: The verifier
+//   - error: An error if no destination reader is configured
+func NewVerifier(cfg *Config) (*Verifier, error) {
+	if cfg.Reader == nil {
+		return nil, fmt.Errorf("verifier requires a destination reader")
+	}
+	return &Verifier{reader: cfg.Reader, policy: cfg.Policy}, nil
+}
+
+// Verify checks that every destination confirmed root for the snapshot's reference timestamp,
+// and that each destination's table for every operator set in dist has the same leaf hash as
+// the table calculated on L1. A destination whose latest table is older than the reference
+// timestamp but identical passes, since unchanged tables need not be transported again. When
+// the destination's latest table is newer, the table it was sent at the reference timestamp is
+// compared instead; if it was sent none then, the check is StatusUnknown. Read failures are
+// reported per check rather than returned.
+//
+// Parameters:
+//   - ctx: Context for the destination reads
+//   - snap: The L1 reference block the distribution was calculated at
+//   - root: The global table root calculated on L1
+//   - dist: The distribution calculated on L1
+//   - destinations: The destination chains to verify
+//
+// Returns:
+//   - *Report: The outcome of every check
+//   - error: An error if a table in dist cannot be decoded
+func (v *Verifier) Verify(
+	ctx context.Context,
+	snap *snapshot.Snapshot,
+	root [32]byte,
+	dist *distribution.Distribution,
+	destinations []policy.Destination,
+) (*Report, error) {
+	referenceTimestamp := snap.ReferenceTimestamp()
+	report := &Report{
+		BlockNumber:        snap.Number,
+		BlockHash:          snap.Hash,
+		ReferenceTimestamp: referenceTimestamp,
+		Root:               common.Hash(root),
+		Chains:             []ChainResult{},
+	}
+
+	opsets := dist.GetOrderedOperatorSets()
+	tables := make([]*operatorTable.OperatorTable, len(opsets))
+	for i, opset := range opsets {
+		tableBytes, _ := dist.GetTableData(opset)
+		table, err := operatorTable.Decode(tableBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode table for operator set %s with ID %d: %w", opset.Avs.String(), opset.Id, err)
+		}
+		tables[i] = table
+	}
+	subjects := make([]policy.Subject, len(opsets))
+	for i := range opsets {
+		subjects[i] = policy.Subject{OperatorSet: &opsets[i], CurveType: tables[i].CurveType}
+	}
+
+	for _, dest := range destinations {
+		chain := v.verifyRoot(ctx, dest, referenceTimestamp, common.Hash(root), subjects)
+		for i, opset := range opsets {
+			tableBytes, _ := dist.GetTableData(opset)
+			chain.OperatorSets = append(chain.OperatorSets, v.verifyTable(ctx, dest, opset, tables[i], tableBytes, referenceTimestamp))
+		}
+		report.Chains = append(report.Chains, chain)
+	}
+	return report, nil
+}
+
+func (v *Verifier) verifyRoot(ctx context.Context, dest policy.Destination, referenceTimestamp uint32, root common.Hash, subjects []policy.Subject) ChainResult {
+	chain := ChainResult{
+		ChainId:              dest.ChainId,
+		OperatorTableUpdater: dest.OperatorTableUpdater,
+		OperatorSets:         []OperatorSetResult{},
+	}
+	if decision := v.policy.EvaluateRoot(dest.ChainId, subjects); !decision.Allowed {
+		chain.RootStatus = StatusSkipped
+		chain.RootReason = fmt.Sprintf("denied by policy rule %q", decision.Rule)
+		return chain
+	}
+
+	confirmed, err := v.reader.GlobalTableRoot(ctx, dest, referenceTimestamp)
+	if err != nil {
+		chain.RootStatus = StatusError
+		chain.RootReason = err.Error()
+		return chain
+	}
+	chain.ConfirmedRoot = confirmed
+	switch {
+	case confirmed == root:
+		chain.RootStatus = StatusMatch
+	case confirmed == (common.Hash{}):
+		chain.RootStatus = StatusMismatch
+		chain.RootReason = fmt.Sprintf("no root confirmed for reference timestamp %d", referenceTimestamp)
+		if latest, err := v.reader.LatestReferenceTimestamp(ctx, dest); err == nil {
+			chain.RootReason += fmt.Sprintf(" (latest confirmed reference timestamp %d)", latest)
+		}
+	default:
+		chain.RootStatus = StatusMismatch
+		chain.RootReason = "confirmed root differs from the root calculated on L1"
+	}
+	return chain
+}
+
+func (v *Verifier) verifyTable(
+	ctx context.Context,
+	dest policy.Destination,
+	opset distribution.OperatorSet,
+	table *operatorTable.OperatorTable,
+	tableBytes []byte,
+	referenceTimestamp uint32,
+) OperatorSetResult {
+	result := OperatorSetResult{
+		Avs:              opset.Avs,
+		Id:               opset.Id,
+		CurveType:        table.CurveType,
+		ExpectedLeafHash: changeDetector.LeafHash(tableBytes),
+	}
+	decision := v.policy.Evaluate(policy.Subject{
+		ChainId:     dest.ChainId,
+		OperatorSet: &opset,
+		CurveType:   table.CurveType,
+	})
+	if !decision.Allowed {
+		result.Status = StatusSkipped
+		result.Reason = fmt.Sprintf("denied by policy rule %q", decision.Rule)
+		return result
+	}
+
+	record, err := v.reader.ReadTable(ctx, dest, opset, table.CurveType)
+	if err != nil {
+		result.Status = StatusError
+		result.Reason = err.Error()
+		return result
+	}
+	if record == nil {
+		result.Status = StatusMismatch
+		result.Reason = "destination has no table for the operator set"
+		return result
+	}
+	if record.ReferenceTimestamp > referenceTimestamp {
+		// The destination has moved on; compare with the table it was sent at this timestamp
+		return v.verifyTableAt(ctx, dest, opset, table, record.ReferenceTimestamp, referenceTimestamp, result)
+	}
+
+	result.DestinationLeafHash = record.LeafHash
+	result.DestinationReferenceTimestamp = record.ReferenceTimestamp
+	switch {
+	case record.LeafHash == result.ExpectedLeafHash:
+		result.Status = StatusMatch
+		if record.ReferenceTimestamp < referenceTimestamp {
+			result.Reason = fmt.Sprintf("unchanged since reference timestamp %d", record.ReferenceTimestamp)
+		}
+	case record.ReferenceTimestamp < referenceTimestamp:
+		result.Status = StatusMismatch
+		result.Reason = fmt.Sprintf("destination's latest table is from reference timestamp %d and differs", record.ReferenceTimestamp)
+	default:
+		result.Status = StatusMismatch
+		result.Reason = fmt.Sprintf("destination's table at reference timestamp %d differs", record.ReferenceTimestamp)
+	}
+	return result
+}
+
+// verifyTableAt compares the table calculated on L1 with the one the destination was sent at
+// referenceTimestamp, given that its latest table is from the later latestTimestamp. Only the
+// operator data is compared: the destination keeps no history of the operator set config, so
+// the table is re-encoded with the L1 config.
+func (v *Verifier) verifyTableAt(
+	ctx context.Context,
+	dest policy.Destination,
+	opset distribution.OperatorSet,
+	table *operatorTable.OperatorTable,
+	latestTimestamp uint32,
+	referenceTimestamp uint32,
+	result OperatorSetResult,
+) OperatorSetResult {
+	sent, err := v.reader.ReadTableAt(ctx, dest, opset, table.CurveType, referenceTimestamp)
+	if err != nil {
+		result.Status = StatusError
+		result.Reason = err.Error()
+		return result
+	}
+	if sent == nil {
+		// The table in effect then was sent at an earlier, unknown timestamp
+		result.Status = StatusUnknown
+		result.DestinationReferenceTimestamp = latestTimestamp
+		result.Reason = fmt.Sprintf("destination's latest table is from reference timestamp %d and none was sent at %d", latestTimestamp, referenceTimestamp)
+		return result
+	}
+
+	configChanged := sent.Config != table.Config
+	comparable := *sent
+	comparable.Config = table.Config
+	sentBytes, err := operatorTable.Encode(&comparable)
+	if err != nil {
+		result.Status = StatusError
+		result.Reason = fmt.Sprintf("failed to encode destination's table: %v", err)
+		return result
+	}
+	result.DestinationLeafHash = changeDetector.LeafHash(sentBytes)
+	result.DestinationReferenceTimestamp = referenceTimestamp
+	if result.DestinationLeafHash != result.ExpectedLeafHash {
+		result.Status = StatusMismatch
+		result.Reason = fmt.Sprintf("destination's table at reference timestamp %d differs", referenceTimestamp)
+		return result
+	}
+	result.Status = StatusMatch
+	if configChanged {
+		result.Reason = "destination's current operator set config differs, and its history is not kept; only operator data was compared"
+	}
+	return result
+}
@@ -0,0 +1,259 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/multichain-go/pkg/changeDetector"
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTable"
+	"github.com/Layr-Labs/multichain-go/pkg/policy"
+	"github.com/Layr-Labs/multichain-go/pkg/snapshot"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tableKey struct {
+	chainId uint64
+	opset   distribution.OperatorSet
+}
+
+type sentKey struct {
+	chainId            uint64
+	opset              distribution.OperatorSet
+	referenceTimestamp uint32
+}
+
+// fakeReader serves latest records per operator set and, like a certificate verifier, the
+// tables sent at exact reference timestamps.
+type fakeReader struct {
+	roots    map[uint64]common.Hash
+	rootErrs map[uint64]error
+	latest   map[tableKey]*changeDetector.Record
+	sent     map[sentKey]*operatorTable.OperatorTable
+}
+
+func (f *fakeReader) GlobalTableRoot(_ context.Context, dest policy.Destination, _ uint32) (common.Hash, error) {
+	return f.roots[dest.ChainId], f.rootErrs[dest.ChainId]
+}
+
+func (f *fakeReader) LatestReferenceTimestamp(context.Context, policy.Destination) (uint32, error) {
+	return 1000, nil
+}
+
+func (f *fakeReader) ReadTable(_ context.Context, dest policy.Destination, opset distribution.OperatorSet, _ operatorTable.CurveType) (*changeDetector.Record, error) {
+	return f.latest[tableKey{dest.ChainId, opset}], nil
+}
+
+func (f *fakeReader) ReadTableAt(_ context.Context, dest policy.Destination, opset distribution.OperatorSet, _ operatorTable.CurveType, referenceTimestamp uint32) (*operatorTable.OperatorTable, error) {
+	return f.sent[sentKey{dest.ChainId, opset, referenceTimestamp}], nil
+}
+
+const testTimestamp = 2000
+
+var (
+	testAvs   = common.HexToAddress("0xa1")
+	opset1    = distribution.OperatorSet{Avs: testAvs, Id: 1}
+	opset2    = distribution.OperatorSet{Avs: testAvs, Id: 2}
+	mainnet   = policy.Destination{ChainId: 1, OperatorTableUpdater: common.HexToAddress("0x01")}
+	base      = policy.Destination{ChainId: 8453, OperatorTableUpdater: common.HexToAddress("0x02")}
+	testSnap  = &snapshot.Snapshot{Number: 100, Hash: common.HexToHash("0xb1"), Timestamp: testTimestamp}
+	testRoot  = common.HexToHash("0x5007")
+	otherRoot = common.HexToHash("0xbad")
+)
+
+func newTable(opset distribution.OperatorSet, weight int64) *operatorTable.OperatorTable {
+	return &operatorTable.OperatorTable{
+		OperatorSet: operatorTable.OperatorSet{Avs: opset.Avs, Id: opset.Id},
+		CurveType:   operatorTable.CurveTypeECDSA,
+		Config:      operatorTable.OperatorSetConfig{Owner: common.HexToAddress("0x0e"), MaxStalenessPeriod: 86400},
+		ECDSA:       []operatorTable.ECDSAOperatorInfo{{Pubkey: common.HexToAddress("0x01"), Weights: []*big.Int{big.NewInt(weight)}}},
+	}
+}
+
+func encodeTable(t *testing.T, opset distribution.OperatorSet, weight int64) []byte {
+	tableBytes, err := operatorTable.Encode(newTable(opset, weight))
+	require.NoError(t, err)
+	return tableBytes
+}
+
+func newTestDistribution(t *testing.T, tables map[distribution.OperatorSet][]byte, order ...distribution.OperatorSet) *distribution.Distribution {
+	dist := distribution.NewDistributionWithOperatorSets(order)
+	for _, opset := range order {
+		require.NoError(t, dist.SetTableData(opset, tables[opset]))
+	}
+	return dist
+}
+
+func record(tableBytes []byte, referenceTimestamp uint32) *changeDetector.Record {
+	return &changeDetector.Record{LeafHash: changeDetector.LeafHash(tableBytes), ReferenceTimestamp: referenceTimestamp}
+}
+
+func TestVerify(t *testing.T) {
+	table1 := encodeTable(t, opset1, 10)
+	table2 := encodeTable(t, opset2, 20)
+	dist := newTestDistribution(t, map[distribution.OperatorSet][]byte{opset1: table1, opset2: table2}, opset1, opset2)
+
+	reader := &fakeReader{
+		roots: map[uint64]common.Hash{mainnet.ChainId: testRoot, base.ChainId: otherRoot},
+		latest: map[tableKey]*changeDetector.Record{
+			// Transported at this timestamp
+			{mainnet.ChainId, opset1}: record(table1, testTimestamp),
+			// Unchanged since an earlier transport
+			{mainnet.ChainId, opset2}: record(table2, 1500),
+			// Stale and different
+			{base.ChainId, opset1}: record(encodeTable(t, opset1, 9), 1500),
+			// Moved on since this timestamp; the historical table differs
+			{base.ChainId, opset2}: record(table2, 3000),
+		},
+		sent: map[sentKey]*operatorTable.OperatorTable{
+			{base.ChainId, opset2, testTimestamp}: newTable(opset2, 21),
+		},
+	}
+	v, err := NewVerifier(&Config{Reader: reader})
+	require.NoError(t, err)
+
+	report, err := v.Verify(context.Background(), testSnap, testRoot, dist, []policy.Destination{mainnet, base})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(testTimestamp), report.ReferenceTimestamp)
+	require.Len(t, report.Chains, 2)
+
+	mainnetResult := report.Chains[0]
+	assert.Equal(t, StatusMatch, mainnetResult.RootStatus)
+	require.Len(t, mainnetResult.OperatorSets, 2)
+	assert.Equal(t, StatusMatch, mainnetResult.OperatorSets[0].Status)
+	assert.Empty(t, mainnetResult.OperatorSets[0].Reason)
+	assert.Equal(t, StatusMatch, mainnetResult.OperatorSets[1].Status)
+	assert.Contains(t, mainnetResult.OperatorSets[1].Reason, "unchanged since reference timestamp 1500")
+
+	baseResult := report.Chains[1]
+	assert.Equal(t, StatusMismatch, baseResult.RootStatus)
+	assert.Equal(t, otherRoot, baseResult.ConfirmedRoot)
+	assert.Equal(t, StatusMismatch, baseResult.OperatorSets[0].Status)
+	assert.Contains(t, baseResult.OperatorSets[0].Reason, "latest table is from reference timestamp 1500")
+	assert.Equal(t, StatusMismatch, baseResult.OperatorSets[1].Status)
+	assert.Equal(t, uint32(testTimestamp), baseResult.OperatorSets[1].DestinationReferenceTimestamp)
+	assert.Contains(t, baseResult.OperatorSets[1].Reason, "at reference timestamp 2000 differs")
+
+	assert.False(t, report.OK())
+	assert.Equal(t, 3, report.FailureCount())
+}
+
+func TestVerify_DestinationMovedOn(t *testing.T) {
+	table1 := encodeTable(t, opset1, 10)
+	table2 := encodeTable(t, opset2, 20)
+	dist := newTestDistribution(t, map[distribution.OperatorSet][]byte{opset1: table1, opset2: table2}, opset1, opset2)
+
+	// Both destinations' latest tables are from after the reference timestamp
+	sentWithNewOwner := newTable(opset2, 20)
+	sentWithNewOwner.Config.Owner = common.HexToAddress("0x0f")
+	reader := &fakeReader{
+		roots: map[uint64]common.Hash{mainnet.ChainId: testRoot},
+		latest: map[tableKey]*changeDetector.Record{
+			{mainnet.ChainId, opset1}: record(encodeTable(t, opset1, 11), 3000),
+			{mainnet.ChainId, opset2}: record(encodeTable(t, opset2, 22), 3000),
+		},
+		sent: map[sentKey]*operatorTable.OperatorTable{
+			// Sent before and after, but not at, the reference timestamp
+			{mainnet.ChainId, opset1, 1500}: newTable(opset1, 10),
+			{mainnet.ChainId, opset1, 3000}: newTable(opset1, 11),
+			// Sent at the reference timestamp; the owner has changed since
+			{mainnet.ChainId, opset2, testTimestamp}: sentWithNewOwner,
+			{mainnet.ChainId, opset2, 3000}:          newTable(opset2, 22),
+		},
+	}
+	v, err := NewVerifier(&Config{Reader: reader})
+	require.NoError(t, err)
+
+	report, err := v.Verify(context.Background(), testSnap, testRoot, dist, []policy.Destination{mainnet})
+	require.NoError(t, err)
+	opsets := report.Chains[0].OperatorSets
+	require.Len(t, opsets, 2)
+
+	assert.Equal(t, StatusUnknown, opsets[0].Status)
+	assert.Equal(t, uint32(3000), opsets[0].DestinationReferenceTimestamp)
+	assert.Contains(t, opsets[0].Reason, "none was sent at 2000")
+
+	assert.Equal(t, StatusMatch, opsets[1].Status)
+	assert.Equal(t, opsets[1].ExpectedLeafHash, opsets[1].DestinationLeafHash)
+	assert.Equal(t, uint32(testTimestamp), opsets[1].DestinationReferenceTimestamp)
+	assert.Contains(t, opsets[1].Reason, "only operator data was compared")
+
+	assert.True(t, report.OK(), "unknown checks are not failures")
+}
+
+func TestVerify_MissingAndErrors(t *testing.T) {
+	table1 := encodeTable(t, opset1, 10)
+	dist := newTestDistribution(t, map[distribution.OperatorSet][]byte{opset1: table1}, opset1)
+
+	reader := &fakeReader{
+		roots:    map[uint64]common.Hash{},
+		rootErrs: map[uint64]error{base.ChainId: errors.New("rpc down")},
+	}
+	v, err := NewVerifier(&Config{Reader: reader})
+	require.NoError(t, err)
+
+	report, err := v.Verify(context.Background(), testSnap, testRoot, dist, []policy.Destination{mainnet, base})
+	require.NoError(t, err)
+
+	assert.Equal(t, StatusMismatch, report.Chains[0].RootStatus)
+	assert.Contains(t, report.Chains[0].RootReason, "no root confirmed for reference timestamp 2000 (latest confirmed reference timestamp 1000)")
+	assert.Equal(t, StatusMismatch, report.Chains[0].OperatorSets[0].Status)
+	assert.Contains(t, report.Chains[0].OperatorSets[0].Reason, "no table")
+
+	assert.Equal(t, StatusError, report.Chains[1].RootStatus)
+	assert.Contains(t, report.Chains[1].RootReason, "rpc down")
+}
+
+func TestVerify_PolicySkips(t *testing.T) {
+	table1 := encodeTable(t, opset1, 10)
+	table2 := encodeTable(t, opset2, 20)
+	dist := newTestDistribution(t, map[distribution.OperatorSet][]byte{opset1: table1, opset2: table2}, opset1, opset2)
+
+	reader := &fakeReader{
+		roots:  map[uint64]common.Hash{mainnet.ChainId: testRoot},
+		latest: map[tableKey]*changeDetector.Record{{mainnet.ChainId, opset2}: record(table2, testTimestamp)},
+	}
+	pol := &policy.Policy{
+		Default: policy.EffectAllow,
+		Rules: []policy.Rule{
+			{Name: "no-base", Effect: policy.EffectDeny, ChainIds: []uint64{base.ChainId}},
+			{Name: "no-opset1", Effect: policy.EffectDeny, OperatorSetIds: []uint32{opset1.Id}},
+		},
+	}
+	v, err := NewVerifier(&Config{Reader: reader, Policy: pol})
+	require.NoError(t, err)
+
+	report, err := v.Verify(context.Background(), testSnap, testRoot, dist, []policy.Destination{mainnet, base})
+	require.NoError(t, err)
+
+	assert.Equal(t, StatusMatch, report.Chains[0].RootStatus)
+	assert.Equal(t, StatusSkipped, report.Chains[0].OperatorSets[0].Status)
+	assert.Contains(t, report.Chains[0].OperatorSets[0].Reason, "no-opset1")
+	assert.Equal(t, StatusMatch, report.Chains[0].OperatorSets[1].Status)
+	assert.Equal(t, StatusSkipped, report.Chains[1].RootStatus)
+	assert.True(t, report.OK())
+
+	// The root is only needed where an operator set is allowed
+	pol.Rules = append(pol.Rules, policy.Rule{Name: "no-opset2", Effect: policy.EffectDeny, OperatorSetIds: []uint32{opset2.Id}})
+	report, err = v.Verify(context.Background(), testSnap, testRoot, dist, []policy.Destination{mainnet})
+	require.NoError(t, err)
+	assert.Equal(t, StatusSkipped, report.Chains[0].RootStatus)
+}
+
+func TestVerify_UndecodableTable(t *testing.T) {
+	dist := newTestDistribution(t, map[distribution.OperatorSet][]byte{opset1: {0x01}}, opset1)
+	v, err := NewVerifier(&Config{Reader: &fakeReader{}})
+	require.NoError(t, err)
+
+	_, err = v.Verify(context.Background(), testSnap, testRoot, dist, []policy.Destination{mainnet})
+	assert.Error(t, err)
+}
+
+func TestNewVerifier_RequiresReader(t *testing.T) {
+	_, err := NewVerifier(&Config{})
+	assert.Error(t, err)
+}